@@ -73,78 +73,233 @@ func actionCI(c *cli.Context) error {
 		summary.Reports = append(summary.Reports, verifyOwners(entries)...)
 	}
 
+	// Reading rules from the base branch and from the whole repository is
+	// only needed to find references to removed rules and to check budgets.
+	verifyRemoved := meta.cfg.IsCheckEnabled(checks.RemovedCheckName)
+	budgets := meta.cfg.CostBudgets()
+	if verifyRemoved || len(budgets) > 0 {
+		base, err := finder.BaseEntries()
+		if err != nil {
+			return err
+		}
+
+		var current []discovery.Entry
+		if (verifyRemoved && len(base) > 0) || len(budgets) > 0 {
+			current, err = finder.RepositoryEntries()
+			if err != nil {
+				return err
+			}
+		}
+
+		if verifyRemoved {
+			summary.Reports = append(summary.Reports, verifyRemovedRules(base, current, entries)...)
+		}
+		summary.Reports = append(summary.Reports, verifyCostBudgets(ctx, meta.cfg, budgets, base, current, entries)...)
+	}
+
 	reps := []reporter.Reporter{
 		reporter.NewConsoleReporter(os.Stderr),
 	}
+	repoReps, err := repositoryReporters(meta.cfg)
+	if err != nil {
+		return err
+	}
+	reps = append(reps, repoReps...)
+
+	foundBugOrHigher := false
+	bySeverity := map[string]interface{}{} // interface{} is needed for log.Fields()
+	for s, c := range summary.CountBySeverity() {
+		if s >= checks.Bug {
+			foundBugOrHigher = true
+		}
+		bySeverity[s.String()] = c
+	}
+	if len(bySeverity) > 0 {
+		log.Info().Fields(bySeverity).Msg("Problems found")
+	}
+
+	if err := submitReports(reps, summary); err != nil {
+		return fmt.Errorf("submitting reports: %w", err)
+	}
+
+	if foundBugOrHigher {
+		return fmt.Errorf("problems found")
+	}
+
+	return nil
+}
+
+// verifyRemovedRules reports all rules in the repository that still use
+// metrics produced by recording rules removed or renamed on current branch.
+// Rules that weren't modified on current branch can't be commented on
+// directly, so those are reported as unmodified.
+func verifyRemovedRules(base, current, modified []discovery.Entry) (reports []reporter.Report) {
+	if len(base) == 0 {
+		return nil
+	}
+
+	modifiedLines := map[string][]int{}
+	for _, entry := range modified {
+		modifiedLines[entry.Path] = append(modifiedLines[entry.Path], entry.ModifiedLines...)
+	}
+
+	files := fileLines{}
+	for _, ref := range checks.FindRemovedRuleReferences(base, current) {
+		report := reporter.Report{
+			Path:          ref.Entry.Path,
+			ModifiedLines: modifiedLines[ref.Entry.Path],
+			Rule:          ref.Entry.Rule,
+			Problem:       ref.Problem,
+			Owner:         ref.Entry.Owner,
+		}
+		report.Unmodified = !isModifiedRule(report)
+		report.Problem.Position = files.problemPosition(report)
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// isModifiedRule returns true if any line of reported problem was modified.
+func isModifiedRule(report reporter.Report) bool {
+	for _, pl := range report.Problem.Lines {
+		for _, ml := range report.ModifiedLines {
+			if pl == ml {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verifyCostBudgets reports modified recording rules that push the total
+// number of series produced by all recording rules with the same owner,
+// or defined in the same file, over the configured budget.
+// Base entries only cover files modified on current branch, so rules from
+// all other files are taken from the repository.
+func verifyCostBudgets(ctx context.Context, cfg config.Config, budgets []checks.CostBudget, base, current, modified []discovery.Entry) (reports []reporter.Report) {
+	if len(budgets) == 0 {
+		return nil
+	}
+
+	changed := map[string]struct{}{}
+	for _, entry := range base {
+		changed[entry.Path] = struct{}{}
+	}
+	for _, entry := range modified {
+		changed[entry.Path] = struct{}{}
+	}
+	before := append([]discovery.Entry{}, base...)
+	for _, entry := range current {
+		if _, ok := changed[entry.Path]; !ok {
+			before = append(before, entry)
+		}
+	}
 
-	if meta.cfg.Repository != nil && meta.cfg.Repository.BitBucket != nil {
+	files := fileLines{}
+	for _, v := range checks.FindCostBudgetViolations(ctx, budgets, before, current, modified, cfg.GetPrometheusServersForEntry) {
+		report := reporter.Report{
+			Path:          v.Entry.Path,
+			ModifiedLines: v.Entry.ModifiedLines,
+			Rule:          v.Entry.Rule,
+			Problem:       v.Problem,
+			Owner:         v.Entry.Owner,
+		}
+		report.Problem.Position = files.problemPosition(report)
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// repositoryReporters returns reporters for all code hosting services
+// configured in the repository section of pint config.
+func repositoryReporters(cfg config.Config) (reps []reporter.Reporter, err error) {
+	if cfg.Repository != nil && cfg.Repository.BitBucket != nil {
 		token, ok := os.LookupEnv("BITBUCKET_AUTH_TOKEN")
 		if !ok {
-			return fmt.Errorf("BITBUCKET_AUTH_TOKEN env variable is required when reporting to BitBucket")
+			return nil, fmt.Errorf("BITBUCKET_AUTH_TOKEN env variable is required when reporting to BitBucket")
 		}
 
-		timeout, _ := time.ParseDuration(meta.cfg.Repository.BitBucket.Timeout)
+		timeout, _ := time.ParseDuration(cfg.Repository.BitBucket.Timeout)
 		br := reporter.NewBitBucketReporter(
 			version,
-			meta.cfg.Repository.BitBucket.URI,
+			cfg.Repository.BitBucket.URI,
 			timeout,
 			token,
-			meta.cfg.Repository.BitBucket.Project,
-			meta.cfg.Repository.BitBucket.Repository,
+			cfg.Repository.BitBucket.Project,
+			cfg.Repository.BitBucket.Repository,
 			git.RunGit,
 		)
 		reps = append(reps, br)
 	}
 
-	if meta.cfg.Repository != nil && meta.cfg.Repository.GitHub != nil {
+	if cfg.Repository != nil && cfg.Repository.GitHub != nil {
 		token, ok := os.LookupEnv("GITHUB_AUTH_TOKEN")
 		if !ok {
-			return fmt.Errorf("GITHUB_AUTH_TOKEN env variable is required when reporting to GitHub")
+			return nil, fmt.Errorf("GITHUB_AUTH_TOKEN env variable is required when reporting to GitHub")
 		}
 
-		prVal, ok := os.LookupEnv("GITHUB_PULL_REQUEST_NUMBER")
-		if !ok {
-			return fmt.Errorf("GITHUB_PULL_REQUEST_NUMBER env variable is required when reporting to GitHub")
+		mode := cfg.Repository.GitHub.Mode
+		if mode == "" {
+			mode = reporter.GithubModeReview
 		}
 
-		prNum, err := strconv.Atoi(prVal)
-		if err != nil {
-			return fmt.Errorf("got not a valid number via GITHUB_PULL_REQUEST_NUMBER: %w", err)
+		// Pull request number is only needed to post reviews, check runs are
+		// created for the HEAD commit.
+		var prNum int
+		if mode == reporter.GithubModeReview {
+			prVal, ok := os.LookupEnv("GITHUB_PULL_REQUEST_NUMBER")
+			if !ok {
+				return nil, fmt.Errorf("GITHUB_PULL_REQUEST_NUMBER env variable is required when reporting to GitHub")
+			}
+
+			prNum, err = strconv.Atoi(prVal)
+			if err != nil {
+				return nil, fmt.Errorf("got not a valid number via GITHUB_PULL_REQUEST_NUMBER: %w", err)
+			}
 		}
 
-		timeout, _ := time.ParseDuration(meta.cfg.Repository.GitHub.Timeout)
+		timeout, _ := time.ParseDuration(cfg.Repository.GitHub.Timeout)
 		gr := reporter.NewGithubReporter(
-			meta.cfg.Repository.GitHub.BaseURI,
-			meta.cfg.Repository.GitHub.UploadURI,
+			mode,
+			cfg.Repository.GitHub.BaseURI,
+			cfg.Repository.GitHub.UploadURI,
 			timeout,
 			token,
-			meta.cfg.Repository.GitHub.Owner,
-			meta.cfg.Repository.GitHub.Repo,
+			cfg.Repository.GitHub.Owner,
+			cfg.Repository.GitHub.Repo,
 			prNum,
 			git.RunGit,
 		)
 		reps = append(reps, gr)
 	}
 
-	foundBugOrHigher := false
-	bySeverity := map[string]interface{}{} // interface{} is needed for log.Fields()
-	for s, c := range summary.CountBySeverity() {
-		if s >= checks.Bug {
-			foundBugOrHigher = true
+	if cfg.Repository != nil && cfg.Repository.GitLab != nil {
+		token, ok := os.LookupEnv("GITLAB_AUTH_TOKEN")
+		if !ok {
+			return nil, fmt.Errorf("GITLAB_AUTH_TOKEN env variable is required when reporting to GitLab")
 		}
-		bySeverity[s.String()] = c
-	}
-	if len(bySeverity) > 0 {
-		log.Info().Fields(bySeverity).Msg("Problems found")
-	}
 
-	if err := submitReports(reps, summary); err != nil {
-		return fmt.Errorf("submitting reports: %w", err)
-	}
+		mrVal, ok := os.LookupEnv("GITLAB_MERGE_REQUEST_IID")
+		if !ok {
+			return nil, fmt.Errorf("GITLAB_MERGE_REQUEST_IID env variable is required when reporting to GitLab")
+		}
 
-	if foundBugOrHigher {
-		return fmt.Errorf("problems found")
+		mrIID, err := strconv.Atoi(mrVal)
+		if err != nil {
+			return nil, fmt.Errorf("got not a valid number via GITLAB_MERGE_REQUEST_IID: %w", err)
+		}
+
+		timeout, _ := time.ParseDuration(cfg.Repository.GitLab.Timeout)
+		gl := reporter.NewGitLabReporter(
+			version,
+			cfg.Repository.GitLab.URI,
+			timeout,
+			token,
+			cfg.Repository.GitLab.Project,
+			mrIID,
+		)
+		reps = append(reps, gl)
 	}
 
-	return nil
+	return reps, nil
 }
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/pkg/diff"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/config"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+var dryRunFlag = "dry-run"
+
+var fixCmd = &cli.Command{
+	Name:   "fix",
+	Usage:  "Automatically fix problems found in specified files",
+	Action: actionFix,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  dryRunFlag,
+			Value: false,
+			Usage: "Don't modify any files, print a diff of all changes instead",
+		},
+	},
+}
+
+func actionFix(c *cli.Context) error {
+	meta, err := actionSetup(c)
+	if err != nil {
+		return err
+	}
+
+	paths := c.Args().Slice()
+	if len(paths) == 0 {
+		return fmt.Errorf("at least one file or directory required")
+	}
+
+	finder := discovery.NewGlobFinder(paths, meta.cfg.Parser.CompileRelaxed())
+	entries, err := finder.Find()
+	if err != nil {
+		return err
+	}
+
+	for _, prom := range meta.cfg.PrometheusServers {
+		prom.StartWorkers()
+	}
+	defer meta.cleanup()
+
+	ctx := context.WithValue(context.Background(), config.CommandKey, config.FixCommand)
+	summary := checkRules(ctx, meta.workers, meta.cfg, entries)
+
+	fixes := fixesByPath(summary)
+	paths = make([]string, 0, len(fixes))
+	for path := range fixes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	dryRun := c.Bool(dryRunFlag)
+	var total int
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		fixed, applied := checks.ApplyFixes(content, fixes[path])
+		if applied == 0 {
+			continue
+		}
+		total += applied
+
+		if dryRun {
+			if err = diff.Text("a/"+path, "b/"+path, content, fixed, os.Stdout); err != nil {
+				return err
+			}
+			continue
+		}
+
+		log.Info().Str("path", path).Int("fixes", applied).Msg("Applying fixes")
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if err = os.WriteFile(path, fixed, info.Mode()); err != nil {
+			return err
+		}
+	}
+
+	log.Info().Int("fixes", total).Int("problems", len(summary.Reports)).Msg("Fix completed")
+
+	return nil
+}
+
+// fixesByPath returns fixes from all reports grouped by file path and
+// sorted by line, so the order of applying them doesn't depend on the
+// order in which checks completed.
+func fixesByPath(summary reporter.Summary) map[string][]checks.Fix {
+	reports := make([]reporter.Report, 0, len(summary.Reports))
+	for _, report := range summary.Reports {
+		if report.Problem.Fix != nil && len(report.Problem.Fix.Edits) > 0 {
+			reports = append(reports, report)
+		}
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		a, b := reports[i].Problem, reports[j].Problem
+		if a.Fix.Edits[0].Line != b.Fix.Edits[0].Line {
+			return a.Fix.Edits[0].Line < b.Fix.Edits[0].Line
+		}
+		if a.Reporter != b.Reporter {
+			return a.Reporter < b.Reporter
+		}
+		return a.Text < b.Text
+	})
+
+	fixes := map[string][]checks.Fix{}
+	for _, report := range reports {
+		fixes[report.Path] = append(fixes[report.Path], *report.Problem.Fix)
+	}
+	return fixes
+}
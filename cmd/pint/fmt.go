@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/formatter"
+)
+
+var checkFlag = "check"
+
+var fmtCmd = &cli.Command{
+	Name:   "fmt",
+	Usage:  "Format queries, labels and annotations in specified files",
+	Action: actionFmt,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  checkFlag,
+			Value: false,
+			Usage: "Don't modify any files, fail if any file needs formatting",
+		},
+	},
+}
+
+func actionFmt(c *cli.Context) error {
+	meta, err := actionSetup(c)
+	if err != nil {
+		return err
+	}
+
+	paths := c.Args().Slice()
+	if len(paths) == 0 {
+		return fmt.Errorf("at least one file or directory required")
+	}
+
+	finder := discovery.NewGlobFinder(paths, meta.cfg.Parser.CompileRelaxed())
+	entries, err := finder.Find()
+	if err != nil {
+		return err
+	}
+
+	check := c.Bool(checkFlag)
+	var unformatted int
+	for _, path := range formatPaths(entries) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		formatted, err := formatter.Format(content)
+		if err != nil {
+			return fmt.Errorf("failed to format %s: %w", path, err)
+		}
+		if bytes.Equal(content, formatted) {
+			continue
+		}
+
+		if check {
+			log.Error().Str("path", path).Msg("File is not formatted")
+			unformatted++
+			continue
+		}
+
+		log.Info().Str("path", path).Msg("Formatting file")
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if err = os.WriteFile(path, formatted, info.Mode()); err != nil {
+			return err
+		}
+	}
+
+	if unformatted > 0 {
+		return fmt.Errorf("%d file(s) not formatted, run pint fmt to fix them", unformatted)
+	}
+
+	return nil
+}
+
+// formatPaths returns the list of files that can be formatted.
+// Files that failed to parse are skipped, errors are already logged by finders.
+func formatPaths(entries []discovery.Entry) (paths []string) {
+	failed := map[string]struct{}{}
+	for _, entry := range entries {
+		if entry.PathError != nil {
+			failed[entry.Path] = struct{}{}
+		}
+	}
+
+	seen := map[string]struct{}{}
+	for _, entry := range entries {
+		if _, ok := failed[entry.Path]; ok {
+			continue
+		}
+		if _, ok := seen[entry.Path]; ok {
+			continue
+		}
+		seen[entry.Path] = struct{}{}
+		paths = append(paths, entry.Path)
+	}
+	return paths
+}
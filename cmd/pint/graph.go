@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/discovery"
+)
+
+var formatFlag = "format"
+
+var graphCmd = &cli.Command{
+	Name:   "graph",
+	Usage:  "Print dependency graph between rules in specified files",
+	Action: actionGraph,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    formatFlag,
+			Aliases: []string{"f"},
+			Value:   "dot",
+			Usage:   "Output format, one of: dot, json",
+		},
+	},
+}
+
+func actionGraph(c *cli.Context) error {
+	meta, err := actionSetup(c)
+	if err != nil {
+		return err
+	}
+
+	paths := c.Args().Slice()
+	if len(paths) == 0 {
+		return fmt.Errorf("at least one file or directory required")
+	}
+
+	format := c.String(formatFlag)
+	if format != "dot" && format != "json" {
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+
+	finder := discovery.NewGlobFinder(paths, meta.cfg.Parser.CompileRelaxed())
+	entries, err := finder.Find()
+	if err != nil {
+		return err
+	}
+
+	g := checks.NewDependencyGraph(entries)
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(g)
+	}
+	return g.WriteDOT(os.Stdout)
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/cloudflare/pint/internal/checks"
@@ -14,7 +15,12 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
-var requireOwnerFlag = "require-owner"
+var (
+	requireOwnerFlag  = "require-owner"
+	outputFlag        = "output"
+	baselineFlag      = "baseline"
+	baselineWriteFlag = "baseline-write"
+)
 
 var lintCmd = &cli.Command{
 	Name:   "lint",
@@ -27,6 +33,24 @@ var lintCmd = &cli.Command{
 			Value:   false,
 			Usage:   "Require all rules to have an owner set via comment",
 		},
+		&cli.StringFlag{
+			Name:    formatFlag,
+			Aliases: []string{"f"},
+			Value:   "console",
+			Usage:   "Report format, one of: console, json, sarif, checkstyle",
+		},
+		&cli.PathFlag{
+			Name:  outputFlag,
+			Usage: "Write reports to this file instead of stdout (or stderr for console format)",
+		},
+		&cli.PathFlag{
+			Name:  baselineFlag,
+			Usage: "Only report problems not recorded in this baseline file",
+		},
+		&cli.PathFlag{
+			Name:  baselineWriteFlag,
+			Usage: "Record all problems found in this baseline file and exit",
+		},
 	},
 }
 
@@ -41,6 +65,20 @@ func actionLint(c *cli.Context) error {
 		return fmt.Errorf("at least one file or directory required")
 	}
 
+	format := c.String(formatFlag)
+	if format != "console" && format != "json" && format != "sarif" && format != "checkstyle" {
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+
+	var baseline *reporter.Baseline
+	if path := c.Path(baselineFlag); path != "" {
+		b, err := reporter.LoadBaseline(path)
+		if err != nil {
+			return fmt.Errorf("failed to load baseline file: %w", err)
+		}
+		baseline = &b
+	}
+
 	finder := discovery.NewGlobFinder(paths, meta.cfg.Parser.CompileRelaxed())
 	entries, err := finder.Find()
 	if err != nil {
@@ -59,8 +97,38 @@ func actionLint(c *cli.Context) error {
 		summary.Reports = append(summary.Reports, verifyOwners(entries)...)
 	}
 
-	r := reporter.NewConsoleReporter(os.Stderr)
-	err = r.Submit(summary)
+	if path := c.Path(baselineWriteFlag); path != "" {
+		b := reporter.NewBaseline(summary)
+		if err = b.Write(path); err != nil {
+			return fmt.Errorf("failed to write baseline file: %w", err)
+		}
+		log.Info().Str("path", path).Int("problems", len(b.Problems)).Msg("Baseline file written")
+		return nil
+	}
+
+	if baseline != nil {
+		var suppressed, unused int
+		summary, suppressed, unused = baseline.Filter(summary)
+		log.Info().Str("path", c.Path(baselineFlag)).Int("suppressed", suppressed).Msg("Problems recorded in the baseline file will not be reported")
+		if unused > 0 {
+			log.Info().Int("unused", unused).Msg("Some problems recorded in the baseline file are no longer reported, baseline file can be updated")
+		}
+	}
+
+	dst := os.Stdout
+	if format == "console" {
+		dst = os.Stderr
+	}
+	if path := c.Path(outputFlag); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		dst = f
+	}
+
+	err = newLintReporter(format, dst).Submit(summary)
 	if err != nil {
 		return err
 	}
@@ -83,6 +151,19 @@ func actionLint(c *cli.Context) error {
 	return nil
 }
 
+func newLintReporter(format string, output io.Writer) reporter.Reporter {
+	switch format {
+	case "json":
+		return reporter.NewJSONReporter(output)
+	case "sarif":
+		return reporter.NewSARIFReporter(output, version)
+	case "checkstyle":
+		return reporter.NewCheckstyleReporter(output)
+	default:
+		return reporter.NewConsoleReporter(output)
+	}
+}
+
 func verifyOwners(entries []discovery.Entry) (reports []reporter.Report) {
 	for _, entry := range entries {
 		if entry.PathError != nil {
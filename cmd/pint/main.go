@@ -9,6 +9,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/cloudflare/pint/internal/config"
+	"github.com/cloudflare/pint/internal/promapi"
 )
 
 const (
@@ -18,6 +19,8 @@ const (
 	offlineFlag  = "offline"
 	noColorFlag  = "no-color"
 	workersFlag  = "workers"
+	recordFlag   = "prometheus-record"
+	replayFlag   = "prometheus-replay"
 )
 
 var (
@@ -65,6 +68,14 @@ func newApp() *cli.App {
 				Value:   false,
 				Usage:   "Disable all check that send live queries to Prometheus servers",
 			},
+			&cli.PathFlag{
+				Name:  recordFlag,
+				Usage: "Save all Prometheus responses to given directory",
+			},
+			&cli.PathFlag{
+				Name:  replayFlag,
+				Usage: "Use Prometheus responses saved with --prometheus-record instead of sending any query",
+			},
 		},
 		Commands: []*cli.Command{
 			versionCmd,
@@ -73,6 +84,10 @@ func newApp() *cli.App {
 			watchCmd,
 			configCmd,
 			parseCmd,
+			fixCmd,
+			fmtCmd,
+			testCmd,
+			graphCmd,
 		},
 	}
 }
@@ -108,6 +123,15 @@ func actionSetup(c *cli.Context) (meta actionMeta, err error) {
 		meta.cfg.DisableOnlineChecks()
 	}
 
+	switch {
+	case c.IsSet(recordFlag) && c.IsSet(replayFlag):
+		return meta, fmt.Errorf("--%s and --%s flags cannot be used together", recordFlag, replayFlag)
+	case c.IsSet(recordFlag):
+		meta.cfg.SetCassette(promapi.NewCassette(c.Path(recordFlag), promapi.CassetteRecord))
+	case c.IsSet(replayFlag):
+		meta.cfg.SetCassette(promapi.NewCassette(c.Path(replayFlag), promapi.CassetteReplay))
+	}
+
 	return meta, nil
 }
 
@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"os"
 	"regexp"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -82,7 +84,7 @@ func checkRules(ctx context.Context, workers int, cfg config.Config, entries []d
 						Msg("Found alerting rule")
 				}
 
-				checkList := cfg.GetChecksForRule(ctx, entry.Path, entry.Rule)
+				checkList := cfg.GetChecksForRule(ctx, entry)
 				for _, check := range checkList {
 					check := check
 					jobs <- scanJob{entry: entry, allEntries: entries, check: check}
@@ -102,7 +104,11 @@ func checkRules(ctx context.Context, workers int, cfg config.Config, entries []d
 		defer close(jobs)
 	}()
 
+	files := fileLines{}
 	for result := range results {
+		if result.Problem.Position == nil {
+			result.Problem.Position = files.problemPosition(result)
+		}
 		summary.Reports = append(summary.Reports, result)
 	}
 
@@ -171,6 +177,49 @@ func scanWorker(ctx context.Context, jobs <-chan scanJob, results chan<- reporte
 	}
 }
 
+// fileLines caches the content of rule files, split into lines.
+type fileLines map[string][]string
+
+// problemPosition returns the exact position of the problem fragment,
+// but only if it's a part of the rule query and it's located on one
+// of the lines the problem was reported for.
+func (fl fileLines) problemPosition(report reporter.Report) *checks.Position {
+	if report.Problem.Fragment == "" || report.Rule.Error.Err != nil {
+		return nil
+	}
+	if report.Rule.RecordingRule == nil && report.Rule.AlertingRule == nil {
+		return nil
+	}
+
+	lines, ok := fl[report.Path]
+	if !ok {
+		content, err := os.ReadFile(report.Path)
+		if err != nil {
+			log.Debug().Err(err).Str("path", report.Path).Msg("Failed to read file, problem positions won't be available")
+		} else {
+			lines = strings.Split(string(content), "\n")
+		}
+		fl[report.Path] = lines
+	}
+	if lines == nil {
+		return nil
+	}
+
+	pos := checks.FragmentPosition(lines, report.Rule.Expr(), report.Problem.Fragment)
+	if pos == nil {
+		return nil
+	}
+	var hasFirst, hasLast bool
+	for _, l := range report.Problem.Lines {
+		hasFirst = hasFirst || l == pos.FirstLine
+		hasLast = hasLast || l == pos.LastLine
+	}
+	if !hasFirst || !hasLast {
+		return nil
+	}
+	return pos
+}
+
 func submitReports(reps []reporter.Reporter, summary reporter.Summary) (err error) {
 	for _, rep := range reps {
 		err = rep.Submit(summary)
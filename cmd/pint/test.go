@@ -0,0 +1,136 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/git"
+	"github.com/cloudflare/pint/internal/reporter"
+	"github.com/cloudflare/pint/internal/ruletest"
+)
+
+var reportFlag = "report"
+
+var testCmd = &cli.Command{
+	Name:   "test",
+	Usage:  "Run rule unit tests from promtool compatible test files",
+	Action: actionTest,
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  reportFlag,
+			Value: false,
+			Usage: "Report test failures to BitBucket or GitHub using repository configuration",
+		},
+	},
+}
+
+func actionTest(c *cli.Context) error {
+	meta, err := actionSetup(c)
+	if err != nil {
+		return err
+	}
+
+	paths := c.Args().Slice()
+	if len(paths) == 0 {
+		return fmt.Errorf("at least one test file required")
+	}
+
+	reps := []reporter.Reporter{
+		reporter.NewConsoleReporter(os.Stderr),
+	}
+	if c.Bool(reportFlag) {
+		repoReps, err := repositoryReporters(meta.cfg)
+		if err != nil {
+			return err
+		}
+		reps = append(reps, repoReps...)
+	}
+
+	summary := reporter.Summary{}
+	for _, path := range paths {
+		log.Info().Str("path", path).Msg("Running unit tests")
+		failures, err := ruletest.RunFile(path)
+		if err != nil {
+			var pathErr *os.PathError
+			if errors.As(err, &pathErr) {
+				return err
+			}
+			summary.Reports = append(summary.Reports, testReport(path, 1, checks.Fatal,
+				fmt.Sprintf("Failed to run unit tests: %s", err)))
+			continue
+		}
+		for _, f := range failures {
+			summary.Reports = append(summary.Reports, testReport(path, f.Line, checks.Bug, f.Text))
+		}
+	}
+
+	log.Info().Int("files", len(paths)).Int("failures", len(summary.Reports)).Msg("Unit tests completed")
+
+	if c.Bool(reportFlag) && len(summary.Reports) > 0 {
+		if err = markUnmodifiedTests(meta.cfg.CI.BaseBranch, summary.Reports); err != nil {
+			return err
+		}
+	}
+
+	if err := submitReports(reps, summary); err != nil {
+		return fmt.Errorf("submitting reports: %w", err)
+	}
+
+	if len(summary.Reports) > 0 {
+		return fmt.Errorf("unit tests failed")
+	}
+
+	return nil
+}
+
+func testReport(path string, line int, severity checks.Severity, text string) reporter.Report {
+	return reporter.Report{
+		Path:          path,
+		ModifiedLines: []int{line},
+		Problem: checks.Problem{
+			Lines:    []int{line},
+			Reporter: "rule/test",
+			Text:     text,
+			Severity: severity,
+		},
+	}
+}
+
+// markUnmodifiedTests sets lines of test files modified on current branch
+// on all reports. Failures on lines that weren't modified can't be reported
+// as comments on those lines, so they are reported as unmodified.
+func markUnmodifiedTests(baseBranch string, reports []reporter.Report) error {
+	cr, err := git.CommitRange(git.RunGit, baseBranch)
+	if err != nil {
+		return fmt.Errorf("failed to get the list of commits to scan: %w", err)
+	}
+	commits := map[string]struct{}{}
+	for _, c := range cr.Commits {
+		commits[c] = struct{}{}
+	}
+
+	modified := map[string][]int{}
+	for i := range reports {
+		path := reports[i].Path
+		if _, ok := modified[path]; !ok {
+			lbs, err := git.Blame(path, git.RunGit)
+			if err != nil {
+				return fmt.Errorf("failed to run git blame for %s: %w", path, err)
+			}
+			modified[path] = []int{}
+			for _, lb := range lbs {
+				if _, ok := commits[lb.Commit]; ok {
+					modified[path] = append(modified[path], lb.Line)
+				}
+			}
+		}
+		reports[i].ModifiedLines = modified[path]
+		reports[i].Unmodified = !isModifiedRule(reports[i])
+	}
+	return nil
+}
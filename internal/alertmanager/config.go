@@ -0,0 +1,236 @@
+package alertmanager
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the subset of alertmanager configuration needed to
+// find out how alerts are routed and inhibited.
+type Config struct {
+	Route        *Route        `yaml:"route"`
+	Receivers    []Receiver    `yaml:"receivers"`
+	InhibitRules []InhibitRule `yaml:"inhibit_rules"`
+}
+
+// Receiver returns receiver with given name.
+func (cfg Config) Receiver(name string) (Receiver, bool) {
+	for _, r := range cfg.Receivers {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return Receiver{}, false
+}
+
+type Route struct {
+	Receiver string            `yaml:"receiver"`
+	Match    map[string]string `yaml:"match"`
+	MatchRE  map[string]string `yaml:"match_re"`
+	Matchers []string          `yaml:"matchers"`
+	Continue bool              `yaml:"continue"`
+	Routes   []*Route          `yaml:"routes"`
+
+	matchers []*labels.Matcher
+}
+
+// LabelMatchers returns all matchers alert labels need to match for
+// the alert to be routed using this route.
+func (r Route) LabelMatchers() []*labels.Matcher {
+	return r.matchers
+}
+
+type Receiver struct {
+	Name         string
+	Integrations []string
+}
+
+func (r *Receiver) UnmarshalYAML(value *yaml.Node) error {
+	var raw map[string]yaml.Node
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	for key, val := range raw {
+		if key == "name" {
+			if err := val.Decode(&r.Name); err != nil {
+				return err
+			}
+			continue
+		}
+		if strings.HasSuffix(key, "_configs") && val.Kind == yaml.SequenceNode && len(val.Content) > 0 {
+			r.Integrations = append(r.Integrations, strings.TrimSuffix(key, "_configs"))
+		}
+	}
+	sort.Strings(r.Integrations)
+	return nil
+}
+
+// IsNull returns true if this receiver doesn't have any integrations
+// configured, alerts routed to it are never sent anywhere.
+func (r Receiver) IsNull() bool {
+	return len(r.Integrations) == 0
+}
+
+type InhibitRule struct {
+	SourceMatch    map[string]string `yaml:"source_match"`
+	SourceMatchRE  map[string]string `yaml:"source_match_re"`
+	SourceMatchers []string          `yaml:"source_matchers"`
+	TargetMatch    map[string]string `yaml:"target_match"`
+	TargetMatchRE  map[string]string `yaml:"target_match_re"`
+	TargetMatchers []string          `yaml:"target_matchers"`
+	Equal          []string          `yaml:"equal"`
+
+	source []*labels.Matcher
+	target []*labels.Matcher
+}
+
+// SourceLabelMatchers returns matchers for alerts that will inhibit other alerts.
+func (ir InhibitRule) SourceLabelMatchers() []*labels.Matcher {
+	return ir.source
+}
+
+// TargetLabelMatchers returns matchers for alerts that will be inhibited.
+func (ir InhibitRule) TargetLabelMatchers() []*labels.Matcher {
+	return ir.target
+}
+
+// Load reads alertmanager configuration from given file.
+func Load(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse alertmanager config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse returns alertmanager configuration from given content.
+func Parse(content []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(content, &cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.Route == nil {
+		return nil, fmt.Errorf("no route provided in config")
+	}
+	if cfg.Route.Receiver == "" {
+		return nil, fmt.Errorf("root route must specify a default receiver")
+	}
+	if len(cfg.Route.Match) > 0 || len(cfg.Route.MatchRE) > 0 || len(cfg.Route.Matchers) > 0 {
+		return nil, fmt.Errorf("root route must not have any matchers")
+	}
+	if err := cfg.Route.parse(cfg); err != nil {
+		return nil, err
+	}
+
+	for i := range cfg.InhibitRules {
+		ir := &cfg.InhibitRules[i]
+		var err error
+		if ir.source, err = parseMatchers(ir.SourceMatch, ir.SourceMatchRE, ir.SourceMatchers); err != nil {
+			return nil, err
+		}
+		if ir.target, err = parseMatchers(ir.TargetMatch, ir.TargetMatchRE, ir.TargetMatchers); err != nil {
+			return nil, err
+		}
+	}
+
+	return &cfg, nil
+}
+
+func (r *Route) parse(cfg Config) (err error) {
+	if r.Receiver != "" {
+		if _, ok := cfg.Receiver(r.Receiver); !ok {
+			return fmt.Errorf("undefined receiver %q used in route", r.Receiver)
+		}
+	}
+	if r.matchers, err = parseMatchers(r.Match, r.MatchRE, r.Matchers); err != nil {
+		return err
+	}
+	for _, child := range r.Routes {
+		if err = child.parse(cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseMatchers(match, matchRE map[string]string, matchers []string) (ms []*labels.Matcher, err error) {
+	for _, name := range sortedKeys(match) {
+		m, err := labels.NewMatcher(labels.MatchEqual, name, match[name])
+		if err != nil {
+			return nil, err
+		}
+		ms = append(ms, m)
+	}
+	for _, name := range sortedKeys(matchRE) {
+		m, err := labels.NewMatcher(labels.MatchRegexp, name, matchRE[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid regexp for %s: %w", name, err)
+		}
+		ms = append(ms, m)
+	}
+	for _, s := range matchers {
+		m, err := parseMatcher(s)
+		if err != nil {
+			return nil, err
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
+
+var matcherRe = regexp.MustCompile(`^\s*([a-zA-Z_:][a-zA-Z0-9_:]*)\s*(=~|!~|!=|=)\s*(.*?)\s*$`)
+
+// parseMatcher parses a single matcher using alertmanager syntax,
+// example: severity=~"warning|critical".
+func parseMatcher(s string) (*labels.Matcher, error) {
+	parts := matcherRe.FindStringSubmatch(s)
+	if parts == nil {
+		return nil, fmt.Errorf("invalid matcher: %s", s)
+	}
+
+	value := parts[3]
+	if strings.HasPrefix(value, `"`) {
+		var err error
+		if value, err = strconv.Unquote(value); err != nil {
+			return nil, fmt.Errorf("invalid matcher value in %s: %w", s, err)
+		}
+	}
+
+	var mt labels.MatchType
+	switch parts[2] {
+	case "=":
+		mt = labels.MatchEqual
+	case "!=":
+		mt = labels.MatchNotEqual
+	case "=~":
+		mt = labels.MatchRegexp
+	case "!~":
+		mt = labels.MatchNotRegexp
+	}
+
+	m, err := labels.NewMatcher(mt, parts[1], value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid matcher %s: %w", s, err)
+	}
+	return m, nil
+}
+
+func sortedKeys(m map[string]string) (keys []string) {
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,154 @@
+package alertmanager_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/alertmanager"
+)
+
+func TestParse(t *testing.T) {
+	type testCaseT struct {
+		config string
+		err    string
+	}
+
+	testCases := []testCaseT{
+		{
+			config: "route: [",
+			err:    "yaml: line 1: did not find expected node content",
+		},
+		{
+			config: "receivers: []\n",
+			err:    "no route provided in config",
+		},
+		{
+			config: "route:\n  group_by: [job]\n",
+			err:    "root route must specify a default receiver",
+		},
+		{
+			config: "route:\n  receiver: default\n  matchers: [job=foo]\nreceivers:\n- name: default\n",
+			err:    "root route must not have any matchers",
+		},
+		{
+			config: "route:\n  receiver: default\nreceivers:\n- name: other\n",
+			err:    `undefined receiver "default" used in route`,
+		},
+		{
+			config: "route:\n  receiver: default\n  routes:\n  - receiver: other\nreceivers:\n- name: default\n",
+			err:    `undefined receiver "other" used in route`,
+		},
+		{
+			config: "route:\n  receiver: default\n  routes:\n  - matchers: [job]\nreceivers:\n- name: default\n",
+			err:    "invalid matcher: job",
+		},
+		{
+			config: "route:\n  receiver: default\n  routes:\n  - matchers: ['job=\"foo']\nreceivers:\n- name: default\n",
+			err:    `invalid matcher value in job="foo: invalid syntax`,
+		},
+		{
+			config: "route:\n  receiver: default\n  routes:\n  - match_re:\n      job: '('\nreceivers:\n- name: default\n",
+			err:    "invalid regexp for job: error parsing regexp: missing closing ): `^(?:()$`",
+		},
+		{
+			config: "route:\n  receiver: default\nreceivers:\n- name: default\ninhibit_rules:\n- source_matchers: [foo]\n",
+			err:    "invalid matcher: foo",
+		},
+		{
+			config: "route:\n  receiver: default\nreceivers:\n- name: default\ninhibit_rules:\n- target_matchers: [foo]\n",
+			err:    "invalid matcher: foo",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.config, func(t *testing.T) {
+			_, err := alertmanager.Parse([]byte(tc.config))
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	p := path.Join(dir, "alertmanager.yml")
+
+	_, err := alertmanager.Load(p)
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(p, []byte("route: {}\n"), 0o644))
+	_, err = alertmanager.Load(p)
+	require.EqualError(t, err, "failed to parse alertmanager config "+p+": root route must specify a default receiver")
+
+	require.NoError(t, os.WriteFile(p, []byte(`
+route:
+  receiver: default
+  routes:
+  - receiver: blackhole
+    match:
+      severity: none
+    match_re:
+      job: foo|bar
+    matchers:
+    - team != "db"
+    - env=~prod.+
+    continue: true
+    routes:
+    - match:
+        instance: foo
+receivers:
+- name: default
+  email_configs:
+  - to: foo@example.com
+  webhook_configs:
+  - url: http://localhost
+- name: blackhole
+- name: empty
+  slack_configs: []
+inhibit_rules:
+- source_matchers: [severity="critical"]
+  target_match:
+    severity: warning
+  equal: [cluster]
+`), 0o644))
+	cfg, err := alertmanager.Load(p)
+	require.NoError(t, err)
+
+	require.Equal(t, "default", cfg.Route.Receiver)
+	require.Len(t, cfg.Route.LabelMatchers(), 0)
+	require.Len(t, cfg.Route.Routes, 1)
+
+	route := cfg.Route.Routes[0]
+	require.Equal(t, "blackhole", route.Receiver)
+	require.True(t, route.Continue)
+	var matchers []string
+	for _, m := range route.LabelMatchers() {
+		matchers = append(matchers, m.String())
+	}
+	require.Equal(t, []string{`severity="none"`, `job=~"foo|bar"`, `team!="db"`, `env=~"prod.+"`}, matchers)
+	require.Len(t, route.Routes, 1)
+	require.Equal(t, "", route.Routes[0].Receiver)
+	require.Len(t, route.Routes[0].LabelMatchers(), 1)
+
+	r, ok := cfg.Receiver("default")
+	require.True(t, ok)
+	require.Equal(t, []string{"email", "webhook"}, r.Integrations)
+	require.False(t, r.IsNull())
+	r, ok = cfg.Receiver("blackhole")
+	require.True(t, ok)
+	require.True(t, r.IsNull())
+	r, ok = cfg.Receiver("empty")
+	require.True(t, ok)
+	require.True(t, r.IsNull())
+	_, ok = cfg.Receiver("missing")
+	require.False(t, ok)
+
+	require.Len(t, cfg.InhibitRules, 1)
+	require.Len(t, cfg.InhibitRules[0].SourceLabelMatchers(), 1)
+	require.Equal(t, `severity="critical"`, cfg.InhibitRules[0].SourceLabelMatchers()[0].String())
+	require.Len(t, cfg.InhibitRules[0].TargetLabelMatchers(), 1)
+	require.Equal(t, `severity="warning"`, cfg.InhibitRules[0].TargetLabelMatchers()[0].String())
+	require.Equal(t, []string{"cluster"}, cfg.InhibitRules[0].Equal)
+}
@@ -72,7 +72,7 @@ func (c AlertsCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 		var isAlerting, isNew bool
 		var firstTime, lastTime time.Time
 		for _, value := range sample.Values {
-			isNew = value.Timestamp.Time().After(lastTime.Add(c.step))
+			isNew = value.Timestamp.Time().After(lastTime.Add(qr.Step))
 			if isNew {
 				if rule.AlertingRule.For != nil {
 					isAlerting = false
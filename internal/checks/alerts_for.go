@@ -54,6 +54,21 @@ func (c AlertsForChecksFor) Check(ctx context.Context, rule parser.Rule, entries
 				rule.AlertingRule.For.Value.Value, rule.AlertingRule.For.Key.Value),
 			Severity: Information,
 		})
+		return
+	}
+
+	if rule.Group != nil && rule.Group.Interval != nil {
+		interval, err := model.ParseDuration(rule.Group.Interval.Value.Value)
+		if err == nil && d < interval {
+			problems = append(problems, Problem{
+				Fragment: rule.AlertingRule.For.Value.Value,
+				Lines:    rule.AlertingRule.For.Lines(),
+				Reporter: c.Reporter(),
+				Text: fmt.Sprintf("%q is lower than the evaluation interval of group %q (%s), alert will only be checked every %s",
+					rule.AlertingRule.For.Value.Value, rule.Group.Name.Value.Value, interval, interval),
+				Severity: Warning,
+			})
+		}
 	}
 
 	return
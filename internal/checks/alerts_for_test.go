@@ -78,6 +78,37 @@ func TestAlertsForCheck(t *testing.T) {
 				}
 			},
 		},
+		{
+			description: "for lower than group interval",
+			content:     "groups:\n- name: foo\n  interval: 2m\n  rules:\n  - alert: foo\n    expr: foo\n    for: 1m\n",
+			checker:     newAlertsForCheck,
+			prometheus:  noProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "1m",
+						Lines:    []int{7},
+						Reporter: "alerts/for",
+						Text:     `"1m" is lower than the evaluation interval of group "foo" (2m), alert will only be checked every 2m`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "for equal to group interval",
+			content:     "groups:\n- name: foo\n  interval: 2m\n  rules:\n  - alert: foo\n    expr: foo\n    for: 2m\n",
+			checker:     newAlertsForCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "group without interval",
+			content:     "groups:\n- name: foo\n  rules:\n  - alert: foo\n    expr: foo\n    for: 10s\n",
+			checker:     newAlertsForCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
 	}
 	runTests(t, testCases)
 }
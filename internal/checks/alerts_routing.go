@@ -0,0 +1,311 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/cloudflare/pint/internal/alertmanager"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/parser/utils"
+)
+
+const (
+	RoutingCheckName = "alerts/routing"
+)
+
+func NewRoutingCheck(cfg *alertmanager.Config) RoutingCheck {
+	return RoutingCheck{cfg: cfg}
+}
+
+type RoutingCheck struct {
+	cfg *alertmanager.Config
+}
+
+func (c RoutingCheck) String() string {
+	return RoutingCheckName
+}
+
+func (c RoutingCheck) Reporter() string {
+	return RoutingCheckName
+}
+
+func (c RoutingCheck) Check(ctx context.Context, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
+	if rule.AlertingRule == nil || rule.AlertingRule.Expr.SyntaxError != nil {
+		return nil
+	}
+
+	al := newAlertLabels(rule.AlertingRule)
+	fragment := fmt.Sprintf("alert: %s", rule.AlertingRule.Alert.Value.Value)
+	lines := rule.AlertingRule.Alert.Lines()
+
+	for _, dst := range routeAlert(c.cfg.Route, "", al, true, true) {
+		if !dst.certain {
+			continue
+		}
+		if dst.isRoot && len(c.cfg.Route.Routes) > 0 {
+			problems = append(problems, Problem{
+				Fragment: fragment,
+				Lines:    lines,
+				Reporter: c.Reporter(),
+				Text:     fmt.Sprintf("alert doesn't match any alertmanager route and it will be sent to the default receiver %q", dst.receiver),
+				Severity: Warning,
+			})
+		}
+		if r, ok := c.cfg.Receiver(dst.receiver); ok && r.IsNull() {
+			problems = append(problems, Problem{
+				Fragment: fragment,
+				Lines:    lines,
+				Reporter: c.Reporter(),
+				Text:     fmt.Sprintf("alert will be routed to alertmanager receiver %q that doesn't have any integrations configured, notifications for it will never be sent", dst.receiver),
+				Severity: Warning,
+			})
+		}
+	}
+
+	for i, ir := range c.cfg.InhibitRules {
+		if al.matchAll(ir.TargetLabelMatchers()) != matchYes {
+			continue
+		}
+		for _, name := range ir.Equal {
+			if !al.isMissing(name) {
+				continue
+			}
+			problems = append(problems, Problem{
+				Fragment: fragment,
+				Lines:    lines,
+				Reporter: c.Reporter(),
+				Text: fmt.Sprintf("alert matches target matchers of alertmanager inhibit rule #%d, but it will never have %q label listed in equal, so it will be inhibited by any alert matching %s",
+					i+1, name, formatMatchers(ir.SourceLabelMatchers())),
+				Severity: Warning,
+			})
+		}
+	}
+
+	return problems
+}
+
+type matchResult int
+
+const (
+	matchNo matchResult = iota
+	matchMaybe
+	matchYes
+)
+
+// alertLabels describes labels that alerts generated by a rule will have.
+type alertLabels struct {
+	// known are labels with values that can be read from the rule.
+	known map[string]string
+	// possible are labels that might be present, but their value is unknown.
+	possible map[string]struct{}
+	// closed is true if alerts will never have labels other than known and possible ones.
+	closed bool
+}
+
+func newAlertLabels(rule *parser.AlertingRule) alertLabels {
+	al := alertLabels{
+		known:    map[string]string{},
+		possible: map[string]struct{}{},
+	}
+
+	// If the query is aggregated using by(...) then only labels listed there
+	// will be present on all results.
+	aggrs := utils.HasOuterAggregation(rule.Expr.Query)
+	if len(aggrs) > 0 {
+		al.closed = true
+		for _, aggr := range aggrs {
+			if aggr.Without {
+				al.closed = false
+				break
+			}
+			for _, name := range aggr.Grouping {
+				al.possible[name] = struct{}{}
+			}
+		}
+	}
+
+	// Some functions and binary operators with vector matching can drop,
+	// add or rewrite labels, so we can't tell which labels will be present.
+	rewritten := isRewritingLabels(rule.Expr.Query)
+	if rewritten {
+		al.closed = false
+	}
+
+	// Labels with value set by equality matchers will have this value
+	// if they are present, unless different selectors set different values.
+	conflicts := map[string]struct{}{}
+	for _, vs := range utils.HasVectorSelector(rule.Expr.Query) {
+		for _, lm := range vs.LabelMatchers {
+			if lm.Type != labels.MatchEqual || lm.Name == labels.MetricName {
+				continue
+			}
+			if rewritten {
+				al.possible[lm.Name] = struct{}{}
+				continue
+			}
+			if al.closed {
+				if _, ok := al.possible[lm.Name]; !ok {
+					continue
+				}
+			}
+			if v, ok := al.known[lm.Name]; ok && v != lm.Value {
+				conflicts[lm.Name] = struct{}{}
+			}
+			al.known[lm.Name] = lm.Value
+		}
+	}
+	for name := range conflicts {
+		delete(al.known, name)
+		al.possible[name] = struct{}{}
+	}
+
+	if rule.Labels != nil {
+		for _, label := range rule.Labels.Items {
+			if strings.Contains(label.Value.Value, "{{") {
+				delete(al.known, label.Key.Value)
+				al.possible[label.Key.Value] = struct{}{}
+				continue
+			}
+			al.known[label.Key.Value] = label.Value.Value
+		}
+	}
+	al.known[labels.AlertName] = rule.Alert.Value.Value
+
+	for name := range al.known {
+		delete(al.possible, name)
+	}
+	return al
+}
+
+// isRewritingLabels returns true if given query uses any function or
+// binary operator that can drop, add or rewrite labels of its results.
+func isRewritingLabels(node *parser.PromQLNode) bool {
+	switch n := node.Node.(type) {
+	case *promParser.BinaryExpr:
+		if n.Op == promParser.LOR {
+			return true
+		}
+		if vm := n.VectorMatching; vm != nil && (vm.On || len(vm.MatchingLabels) > 0 || vm.Card == promParser.CardManyToOne || vm.Card == promParser.CardOneToMany) {
+			return true
+		}
+	case *promParser.Call:
+		switch n.Func.Name {
+		case "label_replace", "label_join", "absent", "absent_over_time", "vector", "scalar":
+			return true
+		}
+	}
+
+	for _, child := range node.Children {
+		if isRewritingLabels(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMissing returns true if alerts will never have given label.
+func (al alertLabels) isMissing(name string) bool {
+	if !al.closed {
+		return false
+	}
+	if _, ok := al.known[name]; ok {
+		return false
+	}
+	if _, ok := al.possible[name]; ok {
+		return false
+	}
+	return true
+}
+
+func (al alertLabels) match(m *labels.Matcher) matchResult {
+	if v, ok := al.known[m.Name]; ok {
+		if m.Matches(v) {
+			return matchYes
+		}
+		return matchNo
+	}
+	if !al.isMissing(m.Name) {
+		return matchMaybe
+	}
+	if m.Matches("") {
+		return matchYes
+	}
+	return matchNo
+}
+
+func (al alertLabels) matchAll(ms []*labels.Matcher) matchResult {
+	result := matchYes
+	for _, m := range ms {
+		switch al.match(m) {
+		case matchNo:
+			return matchNo
+		case matchMaybe:
+			result = matchMaybe
+		}
+	}
+	return result
+}
+
+// routeDestination is the receiver alert can be routed to.
+type routeDestination struct {
+	receiver string
+	// isRoot is true if alert didn't match any route and it's sent
+	// to the receiver of the root route.
+	isRoot bool
+	// certain is false if the alert might not be routed to this receiver,
+	// depending on values of labels we don't know.
+	certain bool
+}
+
+// routeAlert follows alertmanager routing logic and returns all receivers
+// alert can be sent to.
+func routeAlert(route *alertmanager.Route, receiver string, al alertLabels, certain, isRoot bool) (dsts []routeDestination) {
+	if route.Receiver != "" {
+		receiver = route.Receiver
+	}
+
+	// shadowed is set if any previous route might match the alert and stop
+	// routing, maybe is set if any route might match the alert.
+	var matched, shadowed, maybe bool
+	for _, child := range route.Routes {
+		result := al.matchAll(child.LabelMatchers())
+		if result == matchNo {
+			continue
+		}
+		dsts = append(dsts, routeAlert(child, receiver, al, certain && !shadowed && result == matchYes, false)...)
+		if result == matchMaybe {
+			maybe = true
+			shadowed = shadowed || !child.Continue
+			continue
+		}
+		matched = true
+		if !child.Continue {
+			break
+		}
+	}
+
+	if !matched {
+		dsts = append(dsts, routeDestination{
+			receiver: receiver,
+			isRoot:   isRoot,
+			certain:  certain && !maybe,
+		})
+	}
+	return dsts
+}
+
+func formatMatchers(ms []*labels.Matcher) string {
+	if len(ms) == 0 {
+		return "{}"
+	}
+	parts := make([]string, 0, len(ms))
+	for _, m := range ms {
+		parts = append(parts, m.String())
+	}
+	return fmt.Sprintf("{%s}", strings.Join(parts, ", "))
+}
@@ -0,0 +1,226 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/cloudflare/pint/internal/alertmanager"
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+const alertmanagerConfig = `
+route:
+  receiver: default
+  routes:
+  - receiver: blackhole
+    matchers: [severity="none"]
+  - receiver: db
+    matchers: [team="db"]
+    continue: true
+  - receiver: default
+    matchers: [service="api"]
+  - receiver: pager
+    matchers: [severity="page"]
+    routes:
+    - receiver: blackhole
+      matchers: [env="dev"]
+receivers:
+- name: default
+  email_configs:
+  - to: default@example.com
+- name: blackhole
+- name: db
+  email_configs:
+  - to: db@example.com
+- name: pager
+  pagerduty_configs:
+  - service_key: xxx
+inhibit_rules:
+- source_matchers: [severity="page"]
+  target_matchers: [severity="warning"]
+  equal: [cluster, job]
+`
+
+func newRoutingCheck(_ *promapi.FailoverGroup) checks.RuleChecker {
+	cfg, err := alertmanager.Parse([]byte(alertmanagerConfig))
+	if err != nil {
+		panic(err)
+	}
+	return checks.NewRoutingCheck(cfg)
+}
+
+func TestRoutingCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "ignores rules with syntax errors",
+			content:     "- alert: foo\n  expr: sum(foo) without(\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "ignores recording rules",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "routed to pager",
+			content:     "- alert: foo\n  expr: up == 0\n  labels:\n    severity: page\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "unknown labels might match a route",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "labels from query selectors might match a route",
+			content:     "- alert: foo\n  expr: sum(up{team=\"db\"}) by(team) == 0\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "templated labels might match a route",
+			content:     "- alert: foo\n  expr: sum(up) == 0\n  labels:\n    severity: '{{ $labels.severity }}'\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "falls through to default receiver",
+			content:     "- alert: foo\n  expr: sum(up) by(job) == 0\n  labels:\n    severity: warning\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "alert: foo",
+						Lines:    []int{1},
+						Reporter: checks.RoutingCheckName,
+						Text:     `alert doesn't match any alertmanager route and it will be sent to the default receiver "default"`,
+						Severity: checks.Warning,
+					},
+					{
+						Fragment: "alert: foo",
+						Lines:    []int{1},
+						Reporter: checks.RoutingCheckName,
+						Text:     `alert matches target matchers of alertmanager inhibit rule #1, but it will never have "cluster" label listed in equal, so it will be inhibited by any alert matching {severity="page"}`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "routed to blackhole",
+			content:     "- alert: foo\n  expr: up == 0\n  labels:\n    severity: none\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "alert: foo",
+						Lines:    []int{1},
+						Reporter: checks.RoutingCheckName,
+						Text:     `alert will be routed to alertmanager receiver "blackhole" that doesn't have any integrations configured, notifications for it will never be sent`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "routed to nested blackhole",
+			content:     "- alert: foo\n  expr: sum(up{env=\"dev\"}) by(env) == 0\n  labels:\n    severity: page\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "alert: foo",
+						Lines:    []int{1},
+						Reporter: checks.RoutingCheckName,
+						Text:     `alert will be routed to alertmanager receiver "blackhole" that doesn't have any integrations configured, notifications for it will never be sent`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "blackhole might be shadowed by an earlier route",
+			content:     "- alert: foo\n  expr: up == 0\n  labels:\n    severity: page\n    env: dev\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "continue route and default",
+			content:     "- alert: foo\n  expr: sum(up) by(cluster, job) == 0\n  labels:\n    team: db\n    severity: warning\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "aggregation without() keeps unknown labels",
+			content:     "- alert: foo\n  expr: sum(up) without(instance) == 0\n  labels:\n    severity: warning\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "on() vector matching drops labels from selectors",
+			content:     "- alert: foo\n  expr: sum(up{env=\"dev\"}) by(env) * on(job) group_left() foo > 0\n  labels:\n    severity: page\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "ignoring() vector matching might drop labels from selectors",
+			content:     "- alert: foo\n  expr: sum(up{env=\"dev\"} / ignoring(env) up) by(env) > 0\n  labels:\n    severity: page\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "label_replace() might rewrite labels from selectors",
+			content:     "- alert: foo\n  expr: sum(label_replace(up{env=\"dev\"}, \"env\", \"prod\", \"\", \"\")) by(env) == 0\n  labels:\n    severity: page\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "label_join() might rewrite labels from selectors",
+			content:     "- alert: foo\n  expr: sum(label_join(up{env=\"dev\"}, \"env\", \",\", \"job\")) by(env) == 0\n  labels:\n    severity: page\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "absent() might not have labels from selectors",
+			content:     "- alert: foo\n  expr: sum(absent(up{env=\"dev\", job=~\".+\"})) by(env)\n  labels:\n    severity: page\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "vector() doesn't have labels from selectors",
+			content:     "- alert: foo\n  expr: sum(vector(scalar(sum(up{env=\"dev\"})))) by(env) > 0\n  labels:\n    severity: page\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "or might return results from other selectors",
+			content:     "- alert: foo\n  expr: sum(up{env=\"dev\"} == 0 or foo > 0) by(env)\n  labels:\n    severity: page\n",
+			checker:     newRoutingCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+	}
+	runTests(t, testCases)
+}
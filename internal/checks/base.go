@@ -27,6 +27,11 @@ var (
 		SeriesCheckName,
 		LabelCheckName,
 		RejectCheckName,
+		DuplicateCheckName,
+		DependencyCheckName,
+		RoutingCheckName,
+		SnoozeCheckName,
+		RemovedCheckName,
 	}
 	OnlineChecks = []string{
 		AlertsCheckName,
@@ -89,6 +94,12 @@ type Problem struct {
 	Reporter string
 	Text     string
 	Severity Severity
+	// Fix is optional and only set when the problem can be
+	// automatically fixed by `pint fix`.
+	Fix *Fix
+	// Position is optional and only set when the exact location
+	// of the problem fragment in the file is known.
+	Position *Position
 }
 
 func (p Problem) LineRange() (int, int) {
@@ -105,6 +116,7 @@ type exprProblem struct {
 	expr     string
 	text     string
 	severity Severity
+	fix      *Fix
 }
 
 func textAndSeverityFromError(err error, reporter, prom string, s Severity) (text string, severity Severity) {
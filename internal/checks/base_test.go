@@ -62,7 +62,7 @@ func simpleProm(name, uri string, timeout time.Duration, required bool) *promapi
 	return promapi.NewFailoverGroup(
 		name,
 		[]*promapi.Prometheus{
-			promapi.NewPrometheus(name, uri, timeout, 16),
+			promapi.NewPrometheus(name, uri, nil, timeout, 16, nil),
 		},
 		required,
 	)
@@ -273,25 +273,26 @@ func (pe promError) respond(w http.ResponseWriter, r *http.Request) {
 
 type vectorResponse struct {
 	samples model.Vector
+	stats   *promapi.QueryStats
 }
 
 func (vr vectorResponse) respond(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(200)
 	w.Header().Set("Content-Type", "application/json")
+	type data struct {
+		ResultType string              `json:"resultType"`
+		Result     model.Vector        `json:"result"`
+		Stats      *promapi.QueryStats `json:"stats,omitempty"`
+	}
 	result := struct {
 		Status string `json:"status"`
-		Data   struct {
-			ResultType string       `json:"resultType"`
-			Result     model.Vector `json:"result"`
-		} `json:"data"`
+		Data   data   `json:"data"`
 	}{
 		Status: "success",
-		Data: struct {
-			ResultType string       `json:"resultType"`
-			Result     model.Vector `json:"result"`
-		}{
+		Data: data{
 			ResultType: "vector",
 			Result:     vr.samples,
+			Stats:      vr.stats,
 		},
 	}
 	d, err := json.MarshalIndent(result, "", "  ")
@@ -0,0 +1,140 @@
+package checks
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+// Edit describes a single change to a line in a rule file.
+// Old must be present on given line for the edit to be applied, if it's
+// found the first occurrence of Old will be replaced with New.
+// If RemoveLine is set then the whole line is removed instead.
+type Edit struct {
+	Line       int
+	Old        string
+	New        string
+	RemoveLine bool
+}
+
+// Fix is a set of edits that will resolve reported problem.
+// All edits are applied together or not at all.
+type Fix struct {
+	Edits []Edit
+}
+
+func (f Fix) apply(lines []string, removed map[int]struct{}) (changed map[int]string, drop map[int]struct{}, ok bool) {
+	changed = map[int]string{}
+	drop = map[int]struct{}{}
+	for _, edit := range f.Edits {
+		idx := edit.Line - 1
+		if idx < 0 || idx >= len(lines) {
+			return nil, nil, false
+		}
+		if _, ok := removed[idx]; ok {
+			return nil, nil, false
+		}
+		if _, ok := drop[idx]; ok {
+			return nil, nil, false
+		}
+		line, ok := changed[idx]
+		if !ok {
+			line = lines[idx]
+		}
+		if edit.Old == "" || !strings.Contains(line, edit.Old) {
+			return nil, nil, false
+		}
+		if edit.RemoveLine {
+			drop[idx] = struct{}{}
+			continue
+		}
+		changed[idx] = strings.Replace(line, edit.Old, edit.New, 1)
+	}
+	return changed, drop, true
+}
+
+// ApplyFixes will apply all fixes to given file content and return modified
+// content with the number of fixes that were applied.
+// Fixes are applied in order and any fix with edits that no longer match
+// the content, because an earlier fix already modified it, will be skipped.
+func ApplyFixes(content []byte, fixes []Fix) (out []byte, applied int) {
+	lines := strings.Split(string(content), "\n")
+	removed := map[int]struct{}{}
+	for _, fix := range fixes {
+		changed, drop, ok := fix.apply(lines, removed)
+		if !ok {
+			continue
+		}
+		for idx, line := range changed {
+			lines[idx] = line
+		}
+		for idx := range drop {
+			removed[idx] = struct{}{}
+		}
+		applied++
+	}
+
+	kept := make([]string, 0, len(lines))
+	for idx, line := range lines {
+		if _, ok := removed[idx]; ok {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return []byte(strings.Join(kept, "\n")), applied
+}
+
+// exprEdit returns an edit replacing old with new in the rule query.
+// It will return nil if old cannot be found on a single line of the query or
+// if query lines cannot be mapped to file lines.
+func exprEdit(expr parser.PromQLExpr, old, new string) *Edit {
+	lines := strings.Split(strings.TrimSuffix(expr.Value.Value, "\n"), "\n")
+	if len(lines) != len(expr.Value.Position.Lines) {
+		return nil
+	}
+	for i, line := range lines {
+		if strings.Contains(line, old) {
+			return &Edit{Line: expr.Value.Position.Lines[i], Old: old, New: new}
+		}
+	}
+	return nil
+}
+
+type quoteStyle struct {
+	quote    string
+	replacer *strings.Replacer
+}
+
+var quoteStyles = []quoteStyle{
+	{quote: `"`, replacer: strings.NewReplacer(`\`, `\\`, `"`, `\"`)},
+	{quote: `'`, replacer: strings.NewReplacer(`\`, `\\`, `'`, `\'`)},
+	{quote: "`", replacer: strings.NewReplacer()},
+}
+
+func (qs quoteStyle) format(s string) string {
+	return qs.quote + qs.replacer.Replace(s) + qs.quote
+}
+
+// matcherFix returns a fix that will replace a label matcher in the query
+// with a new one using given operator and value, keeping the original
+// quoting style and whitespace.
+func matcherFix(expr parser.PromQLExpr, name, op, value, newOp, newValue string) *Fix {
+	for _, qs := range quoteStyles {
+		if qs.quote == "`" && (strings.Contains(value, "`") || strings.Contains(newValue, "`")) {
+			continue
+		}
+		re := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `([ \t]*)` + regexp.QuoteMeta(op) + `([ \t]*)` + regexp.QuoteMeta(qs.format(value)))
+		for _, line := range strings.Split(expr.Value.Value, "\n") {
+			m := re.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			if edit := exprEdit(expr, m[0], name+m[1]+newOp+m[2]+qs.format(newValue)); edit != nil {
+				return &Fix{Edits: []Edit{*edit}}
+			}
+			return nil
+		}
+	}
+	return nil
+}
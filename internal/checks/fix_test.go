@@ -0,0 +1,90 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func TestApplyFixes(t *testing.T) {
+	type testCaseT struct {
+		content string
+		fixes   []checks.Fix
+		output  string
+		applied int
+	}
+
+	testCases := []testCaseT{
+		{
+			content: "- record: foo\n  expr: foo{job=~\"bar\"}\n",
+			output:  "- record: foo\n  expr: foo{job=~\"bar\"}\n",
+		},
+		{
+			content: "# comment\n- record: foo\n  expr: foo{job=~\"bar\"} # another comment\n",
+			fixes: []checks.Fix{
+				{Edits: []checks.Edit{{Line: 3, Old: `job=~"bar"`, New: `job="bar"`}}},
+			},
+			output:  "# comment\n- record: foo\n  expr: foo{job=\"bar\"} # another comment\n",
+			applied: 1,
+		},
+		{
+			content: "- record: foo\n  expr: foo{job=~\"bar\"} / bar{job=~\"bar\"}\n",
+			fixes: []checks.Fix{
+				{Edits: []checks.Edit{{Line: 2, Old: `job=~"bar"`, New: `job="bar"`}}},
+				{Edits: []checks.Edit{{Line: 2, Old: `job=~"bar"`, New: `job="bar"`}}},
+			},
+			output:  "- record: foo\n  expr: foo{job=\"bar\"} / bar{job=\"bar\"}\n",
+			applied: 2,
+		},
+		{
+			content: "- record: foo\n  expr: rate(foo[1m])\n",
+			fixes: []checks.Fix{
+				{Edits: []checks.Edit{{Line: 2, Old: `foo[1m]`, New: `foo[2m]`}}},
+				{Edits: []checks.Edit{{Line: 2, Old: `foo[1m]`, New: `foo[4m]`}}},
+			},
+			output:  "- record: foo\n  expr: rate(foo[2m])\n",
+			applied: 1,
+		},
+		{
+			content: "- record: foo\n  expr: sum(foo)\n  labels:\n    bad: bar\n    foo: bar\n",
+			fixes: []checks.Fix{
+				{Edits: []checks.Edit{{Line: 4, Old: "bad", RemoveLine: true}}},
+			},
+			output:  "- record: foo\n  expr: sum(foo)\n  labels:\n    foo: bar\n",
+			applied: 1,
+		},
+		{
+			content: "- record: foo\n  expr: sum(foo)\n  labels:\n    bad: bar\n",
+			fixes: []checks.Fix{
+				{Edits: []checks.Edit{
+					{Line: 4, Old: "bad", RemoveLine: true},
+					{Line: 3, Old: "labels", RemoveLine: true},
+				}},
+				{Edits: []checks.Edit{{Line: 4, Old: "bad", New: "good"}}},
+			},
+			output:  "- record: foo\n  expr: sum(foo)\n",
+			applied: 1,
+		},
+		{
+			content: "- record: foo\n  expr: sum(foo)\n",
+			fixes: []checks.Fix{
+				{Edits: []checks.Edit{
+					{Line: 1, Old: "foo", New: "bar"},
+					{Line: 2, Old: "bar", New: "foo"},
+				}},
+				{Edits: []checks.Edit{{Line: 5, Old: "foo", New: "bar"}}},
+			},
+			output: "- record: foo\n  expr: sum(foo)\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.content, func(t *testing.T) {
+			output, applied := checks.ApplyFixes([]byte(tc.content), tc.fixes)
+			require.Equal(t, tc.output, string(output))
+			require.Equal(t, tc.applied, applied)
+		})
+	}
+}
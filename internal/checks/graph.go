@@ -0,0 +1,193 @@
+package checks
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/prometheus/prometheus/model/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+// DependencyGraph describes dependencies between rules.
+// There's an edge from every recording rule to all rules that use
+// metrics produced by it in their queries.
+type DependencyGraph struct {
+	Nodes []DependencyNode `json:"nodes"`
+	Edges []DependencyEdge `json:"edges"`
+}
+
+type DependencyNode struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Path  string `json:"path"`
+	Line  int    `json:"line"`
+	Group string `json:"group,omitempty"`
+}
+
+type DependencyEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// NewDependencyGraph returns the dependency graph for all valid rules
+// from given entries.
+func NewDependencyGraph(entries []discovery.Entry) (g DependencyGraph) {
+	g.Nodes = []DependencyNode{}
+	g.Edges = []DependencyEdge{}
+
+	index := newRecordingRulesIndex(entries)
+	for _, entry := range entries {
+		if !isValidEntry(entry) {
+			continue
+		}
+		node := newDependencyNode(entry)
+		g.Nodes = append(g.Nodes, node)
+		for _, dep := range index.dependencies(entry.Rule) {
+			g.Edges = append(g.Edges, DependencyEdge{From: dependencyNodeID(dep.entry), To: node.ID})
+		}
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		if g.Nodes[i].Path != g.Nodes[j].Path {
+			return g.Nodes[i].Path < g.Nodes[j].Path
+		}
+		return g.Nodes[i].Line < g.Nodes[j].Line
+	})
+	order := make(map[string]int, len(g.Nodes))
+	for i, node := range g.Nodes {
+		order[node.ID] = i
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return order[g.Edges[i].From] < order[g.Edges[j].From]
+		}
+		return order[g.Edges[i].To] < order[g.Edges[j].To]
+	})
+	return g
+}
+
+// WriteDOT writes the graph in Graphviz DOT format.
+func (g DependencyGraph) WriteDOT(w io.Writer) (err error) {
+	write := func(format string, a ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, a...)
+		}
+	}
+
+	write("digraph rules {\n")
+	for _, node := range g.Nodes {
+		shape := "box"
+		if node.Type == "alerting" {
+			shape = "ellipse"
+		}
+		write("  %s [label=%s shape=%s tooltip=%s];\n",
+			strconv.Quote(node.ID), strconv.Quote(node.Name), shape, strconv.Quote(node.ID))
+	}
+	for _, edge := range g.Edges {
+		write("  %s -> %s;\n", strconv.Quote(edge.From), strconv.Quote(edge.To))
+	}
+	write("}\n")
+	return err
+}
+
+func isValidEntry(entry discovery.Entry) bool {
+	if entry.PathError != nil || entry.Rule.Error.Err != nil {
+		return false
+	}
+	return entry.Rule.RecordingRule != nil || entry.Rule.AlertingRule != nil
+}
+
+func newDependencyNode(entry discovery.Entry) DependencyNode {
+	node := DependencyNode{
+		ID:   dependencyNodeID(entry),
+		Name: entry.Rule.Name(),
+		Path: entry.Path,
+		Line: ruleFirstLine(entry.Rule),
+	}
+	if entry.Rule.RecordingRule != nil {
+		node.Type = "recording"
+	} else {
+		node.Type = "alerting"
+	}
+	if entry.Rule.Group != nil {
+		node.Group = entry.Rule.Group.Name.Value.Value
+	}
+	return node
+}
+
+func dependencyNodeID(entry discovery.Entry) string {
+	return fmt.Sprintf("%s:%d", entry.Path, ruleFirstLine(entry.Rule))
+}
+
+func ruleFirstLine(rule parser.Rule) int {
+	if rule.RecordingRule != nil {
+		return rule.RecordingRule.Record.Key.Position.FirstLine()
+	}
+	return rule.AlertingRule.Alert.Key.Position.FirstLine()
+}
+
+// dependency is a recording rule that produces metrics used by another rule.
+type dependency struct {
+	entry    discovery.Entry
+	selector promParser.VectorSelector
+}
+
+// recordingRulesIndex maps metric names to recording rules producing them.
+type recordingRulesIndex map[string][]discovery.Entry
+
+func newRecordingRulesIndex(entries []discovery.Entry) recordingRulesIndex {
+	index := recordingRulesIndex{}
+	for _, entry := range entries {
+		if !isValidEntry(entry) || entry.Rule.RecordingRule == nil {
+			continue
+		}
+		name := entry.Rule.RecordingRule.Record.Value.Value
+		index[name] = append(index[name], entry)
+	}
+	return index
+}
+
+// dependencies returns all recording rules that produce metrics
+// used in the query of given rule.
+func (index recordingRulesIndex) dependencies(rule parser.Rule) (deps []dependency) {
+	expr := rule.Expr()
+	if expr.SyntaxError != nil || expr.Query == nil {
+		return nil
+	}
+
+	seen := map[*parser.RecordingRule]struct{}{}
+	for _, selector := range getSelectors(expr.Query) {
+		name := stripLabels(selector).Name
+		for _, entry := range index[name] {
+			if _, ok := seen[entry.Rule.RecordingRule]; ok {
+				continue
+			}
+			if !selectorMatchesLabels(selector, staticLabels(entry.Rule.RecordingRule.Labels)) {
+				continue
+			}
+			seen[entry.Rule.RecordingRule] = struct{}{}
+			deps = append(deps, dependency{entry: entry, selector: selector})
+		}
+	}
+	return deps
+}
+
+// selectorMatchesLabels returns false if any matcher of the selector
+// will never match a static label set on the recording rule.
+func selectorMatchesLabels(selector promParser.VectorSelector, static map[string]string) bool {
+	for _, lm := range selector.LabelMatchers {
+		if lm.Name == labels.MetricName {
+			continue
+		}
+		if val, ok := static[lm.Name]; ok && !lm.Matches(val) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package checks_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	entries := mustParseContent(`groups:
+- name: foo
+  rules:
+  - record: job:up:sum
+    expr: sum(up) by(job)
+  - record: job:up:sum
+    expr: count(up) by(job)
+    labels:
+      kind: count
+  - alert: JobDown
+    expr: job:up:sum{kind!="count"} == 0
+  - alert: Broken
+    expr: sum(
+`)
+
+	g := checks.NewDependencyGraph(entries)
+	require.Equal(t, checks.DependencyGraph{
+		Nodes: []checks.DependencyNode{
+			{ID: "fake.yml:4", Name: "job:up:sum", Type: "recording", Path: "fake.yml", Line: 4, Group: "foo"},
+			{ID: "fake.yml:6", Name: "job:up:sum", Type: "recording", Path: "fake.yml", Line: 6, Group: "foo"},
+			{ID: "fake.yml:10", Name: "JobDown", Type: "alerting", Path: "fake.yml", Line: 10, Group: "foo"},
+			{ID: "fake.yml:12", Name: "Broken", Type: "alerting", Path: "fake.yml", Line: 12, Group: "foo"},
+		},
+		Edges: []checks.DependencyEdge{
+			{From: "fake.yml:4", To: "fake.yml:10"},
+		},
+	}, g)
+
+	var buf bytes.Buffer
+	require.NoError(t, g.WriteDOT(&buf))
+	require.Equal(t, `digraph rules {
+  "fake.yml:4" [label="job:up:sum" shape=box tooltip="fake.yml:4"];
+  "fake.yml:6" [label="job:up:sum" shape=box tooltip="fake.yml:6"];
+  "fake.yml:10" [label="JobDown" shape=ellipse tooltip="fake.yml:10"];
+  "fake.yml:12" [label="Broken" shape=ellipse tooltip="fake.yml:12"];
+  "fake.yml:4" -> "fake.yml:10";
+}
+`, buf.String())
+}
@@ -0,0 +1,111 @@
+package checks
+
+import (
+	"strings"
+
+	promParser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+// Position is the exact location of the problem fragment in a rule file.
+// Lines and columns start at 1, both first and last column are inclusive.
+type Position struct {
+	FirstLine   int
+	FirstColumn int
+	LastLine    int
+	LastColumn  int
+}
+
+// FragmentPosition returns the position of a PromQL fragment in the query of given rule.
+// lines must be the content of the file the rule was read from.
+// It will return nil if the fragment isn't a part of the query, if it's
+// the whole query, if it's found more than once or if query lines cannot
+// be mapped to file lines.
+func FragmentPosition(lines []string, expr parser.PromQLExpr, fragment string) *Position {
+	if fragment == "" || expr.Value == nil || expr.SyntaxError != nil {
+		return nil
+	}
+
+	query := expr.Value.Value
+	root, err := promParser.ParseExpr(query)
+	if err != nil {
+		return nil
+	}
+
+	// Fragments matching the whole query or more than one part of it
+	// don't point at anything more specific than problem lines.
+	if strings.TrimSpace(query) == fragment {
+		return nil
+	}
+	var pr *promParser.PositionRange
+	var matches int
+	promParser.Inspect(root, func(node promParser.Node, _ []promParser.Node) error {
+		if node == nil || node.String() != fragment {
+			return nil
+		}
+		r := node.PositionRange()
+		if pr == nil || *pr != r {
+			matches++
+		}
+		pr = &r
+		return nil
+	})
+	if matches != 1 || pr.End <= pr.Start || (pr.Start == 0 && int(pr.End) >= len(strings.TrimRight(query, "\n "))) {
+		return nil
+	}
+
+	first, ok := queryOffsetPosition(lines, expr, int(pr.Start))
+	if !ok {
+		return nil
+	}
+	last, ok := queryOffsetPosition(lines, expr, int(pr.End)-1)
+	if !ok {
+		return nil
+	}
+	return &Position{
+		FirstLine:   first.line,
+		FirstColumn: first.column,
+		LastLine:    last.line,
+		LastColumn:  last.column,
+	}
+}
+
+type fileOffset struct {
+	line   int
+	column int
+}
+
+// queryOffsetPosition maps an offset in the rule query to the file line and column.
+func queryOffsetPosition(lines []string, expr parser.PromQLExpr, offset int) (pos fileOffset, ok bool) {
+	queryLines := strings.Split(strings.TrimSuffix(expr.Value.Value, "\n"), "\n")
+	if len(queryLines) != len(expr.Value.Position.Lines) {
+		return pos, false
+	}
+
+	for i, ql := range queryLines {
+		if offset > len(ql) {
+			offset -= len(ql) + 1
+			continue
+		}
+		lineNo := expr.Value.Position.Lines[i]
+		if lineNo < 1 || lineNo > len(lines) {
+			return pos, false
+		}
+		line := lines[lineNo-1]
+		// Skip the key if the query starts on the same line.
+		var skip int
+		if lineNo == expr.Key.Position.FirstLine() {
+			if skip = strings.Index(line, expr.Key.Value+":"); skip < 0 {
+				return pos, false
+			}
+			skip += len(expr.Key.Value) + 1
+		}
+		idx := strings.Index(line[skip:], ql)
+		if idx < 0 {
+			return pos, false
+		}
+		return fileOffset{line: lineNo, column: skip + idx + offset + 1}, true
+	}
+	return pos, false
+}
@@ -0,0 +1,77 @@
+package checks_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+func TestFragmentPosition(t *testing.T) {
+	type testCaseT struct {
+		content  string
+		fragment string
+		position *checks.Position
+	}
+
+	testCases := []testCaseT{
+		{
+			content:  "- record: foo\n  expr: sum(rate(foo[1m]))\n",
+			fragment: "foo[1m]",
+			position: &checks.Position{FirstLine: 2, FirstColumn: 18, LastLine: 2, LastColumn: 24},
+		},
+		{
+			content:  "- record: foo\n  expr: sum(rate(foo[1m]))\n",
+			fragment: "sum(rate(foo[1m]))",
+		},
+		{
+			content:  "- record: foo\n  expr: sum(foo) without(job)\n",
+			fragment: "sum without(job) (foo)",
+		},
+		{
+			content:  "- record: foo\n  expr: sum(foo) without(job) > 0\n",
+			fragment: "sum without(job) (foo)",
+			position: &checks.Position{FirstLine: 2, FirstColumn: 9, LastLine: 2, LastColumn: 29},
+		},
+		{
+			content:  "- record: foo\n  expr: sum(foo) / sum(foo)\n",
+			fragment: "sum(foo)",
+		},
+		{
+			content:  "- record: foo\n  expr: |\n    sum(\n      foo{job=~\"bar\"}\n    )\n    / bar\n",
+			fragment: `foo{job=~"bar"}`,
+			position: &checks.Position{FirstLine: 4, FirstColumn: 7, LastLine: 4, LastColumn: 21},
+		},
+		{
+			content:  "- record: foo\n  expr: |\n    sum(\n      foo{job=~\"bar\"}\n    )\n    / bar\n",
+			fragment: `sum(foo{job=~"bar"})`,
+			position: &checks.Position{FirstLine: 3, FirstColumn: 5, LastLine: 5, LastColumn: 5},
+		},
+		{
+			content:  "- record: foo\n  expr: foo\n",
+			fragment: "bar",
+		},
+		{
+			content:  "- record: foo\n  expr: foo +\n    bar\n",
+			fragment: "bar",
+		},
+		{
+			content:  "- record: foo\n  expr: sum(foo\n",
+			fragment: "foo",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			rules, err := parser.NewParser().Parse([]byte(tc.content))
+			require.NoError(t, err)
+			require.Len(t, rules, 1)
+			lines := strings.Split(tc.content, "\n")
+			require.Equal(t, tc.position, checks.FragmentPosition(lines, rules[0].Expr(), tc.fragment))
+		})
+	}
+}
@@ -3,6 +3,7 @@ package checks
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/cloudflare/pint/internal/discovery"
@@ -11,6 +12,7 @@ import (
 	"github.com/cloudflare/pint/internal/promapi"
 
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
 	promParser "github.com/prometheus/prometheus/promql/parser"
 )
 
@@ -54,20 +56,21 @@ func (c RateCheck) Check(ctx context.Context, rule parser.Rule, entries []discov
 		return
 	}
 
-	for _, problem := range c.checkNode(ctx, expr.Query, cfg) {
+	for _, problem := range c.checkNode(ctx, expr, expr.Query, cfg) {
 		problems = append(problems, Problem{
 			Fragment: problem.expr,
 			Lines:    expr.Lines(),
 			Reporter: c.Reporter(),
 			Text:     problem.text,
 			Severity: problem.severity,
+			Fix:      problem.fix,
 		})
 	}
 
 	return
 }
 
-func (c RateCheck) checkNode(ctx context.Context, node *parser.PromQLNode, cfg *promapi.ConfigResult) (problems []exprProblem) {
+func (c RateCheck) checkNode(ctx context.Context, expr parser.PromQLExpr, node *parser.PromQLNode, cfg *promapi.ConfigResult) (problems []exprProblem) {
 	if n, ok := node.Node.(*promParser.Call); ok && (n.Func.Name == "rate" || n.Func.Name == "irate") {
 		var minIntervals int
 		switch n.Func.Name {
@@ -78,12 +81,13 @@ func (c RateCheck) checkNode(ctx context.Context, node *parser.PromQLNode, cfg *
 		}
 		for _, arg := range n.Args {
 			if m, ok := arg.(*promParser.MatrixSelector); ok {
-				if m.Range < cfg.Config.Global.ScrapeInterval*time.Duration(minIntervals) {
+				if minRange := cfg.Config.Global.ScrapeInterval * time.Duration(minIntervals); m.Range < minRange {
 					p := exprProblem{
 						expr: node.Expr,
 						text: fmt.Sprintf("duration for %s() must be at least %d x scrape_interval, %s is using %s scrape_interval",
 							n.Func.Name, minIntervals, promText(c.prom.Name(), cfg.URI), output.HumanizeDuration(cfg.Config.Global.ScrapeInterval)),
 						severity: Bug,
+						fix:      rangeFix(expr, m, minRange),
 					}
 					problems = append(problems, p)
 				}
@@ -114,8 +118,38 @@ func (c RateCheck) checkNode(ctx context.Context, node *parser.PromQLNode, cfg *
 	}
 
 	for _, child := range node.Children {
-		problems = append(problems, c.checkNode(ctx, child, cfg)...)
+		problems = append(problems, c.checkNode(ctx, expr, child, cfg)...)
 	}
 
 	return
 }
+
+// rangeFix finds given range selector in the original query and returns
+// a fix that will change its range to minRange.
+// Child nodes are decoded from their string representation so we need to
+// parse the query again to get positions matching the rule file.
+func rangeFix(expr parser.PromQLExpr, m *promParser.MatrixSelector, minRange time.Duration) *Fix {
+	root, err := promParser.ParseExpr(expr.Value.Value)
+	if err != nil {
+		return nil
+	}
+
+	var fix *Fix
+	promParser.Inspect(root, func(node promParser.Node, _ []promParser.Node) error {
+		ms, ok := node.(*promParser.MatrixSelector)
+		if !ok || fix != nil || ms.String() != m.String() {
+			return nil
+		}
+		pos := ms.PositionRange()
+		old := expr.Value.Value[pos.Start:pos.End]
+		idx := strings.LastIndex(old, "[")
+		if idx < 0 || strings.Contains(old, "\n") {
+			return nil
+		}
+		if edit := exprEdit(expr, old, old[:idx]+"["+model.Duration(minRange).String()+"]"); edit != nil {
+			fix = &Fix{Edits: []Edit{*edit}}
+		}
+		return nil
+	})
+	return fix
+}
@@ -45,6 +45,9 @@ func TestRateCheck(t *testing.T) {
 						Reporter: "promql/rate",
 						Text:     durationMustText("prom", uri, "rate", "2", "1m"),
 						Severity: checks.Bug,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{{Line: 2, Old: `foo[1m]`, New: `foo[2m]`}},
+						},
 					},
 				}
 			},
@@ -112,6 +115,9 @@ func TestRateCheck(t *testing.T) {
 						Reporter: "promql/rate",
 						Text:     durationMustText("prom", uri, "irate", "2", "1m"),
 						Severity: checks.Bug,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{{Line: 2, Old: `foo[1m]`, New: `foo[2m]`}},
+						},
 					},
 				}
 			},
@@ -280,6 +286,9 @@ func TestRateCheck(t *testing.T) {
 						Reporter: "promql/rate",
 						Text:     durationMustText("prom", uri, "rate", "2", "1m"),
 						Severity: checks.Bug,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{{Line: 2, Old: `bar[1m]`, New: `bar[2m]`}},
+						},
 					},
 				}
 			},
@@ -417,6 +426,9 @@ func TestRateCheck(t *testing.T) {
 						Reporter: "promql/rate",
 						Text:     durationMustText("prom", uri, "rate", "2", "1m"),
 						Severity: checks.Bug,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{{Line: 2, Old: `foo{job="xxx"}[1m]`, New: `foo{job="xxx"}[2m]`}},
+						},
 					},
 					{
 						Fragment: "foo",
@@ -468,6 +480,9 @@ func TestRateCheck(t *testing.T) {
 						Reporter: "promql/rate",
 						Text:     durationMustText("prom", uri, "rate", "2", "1m"),
 						Severity: checks.Bug,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{{Line: 2, Old: `foo{job="xxx"}[1m]`, New: `foo{job="xxx"}[2m]`}},
+						},
 					},
 					{
 						Fragment: "foo",
@@ -3,9 +3,13 @@ package checks
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"regexp/syntax"
+	"strings"
 
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
+	promParser "github.com/prometheus/prometheus/promql/parser"
 
 	"github.com/cloudflare/pint/internal/discovery"
 	"github.com/cloudflare/pint/internal/parser"
@@ -36,6 +40,26 @@ func (c RegexpCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 	}
 
 	for _, selector := range getSelectors(expr.Query) {
+		if selector.Name == "" {
+			var nameMatchers []*labels.Matcher
+			for _, lm := range selector.LabelMatchers {
+				if lm.Name == labels.MetricName && lm.Type == labels.MatchEqual {
+					nameMatchers = append(nameMatchers, lm)
+				}
+			}
+			if len(nameMatchers) == 1 && model.IsValidMetricName(model.LabelValue(nameMatchers[0].Value)) {
+				named := promParser.VectorSelector{Name: nameMatchers[0].Value, LabelMatchers: selector.LabelMatchers}
+				problems = append(problems, Problem{
+					Fragment: selector.String(),
+					Lines:    expr.Lines(),
+					Reporter: c.Reporter(),
+					Text:     fmt.Sprintf(`redundant %s matcher, use %s instead`, nameMatchers[0], named.String()),
+					Severity: Information,
+					Fix:      nameMatcherFix(expr, nameMatchers[0].Value),
+				})
+			}
+		}
+
 		for _, lm := range selector.LabelMatchers {
 			if re := lm.GetRegexString(); re != "" {
 				var isUseful bool
@@ -71,6 +95,7 @@ func (c RegexpCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 						Reporter: c.Reporter(),
 						Text:     fmt.Sprintf(`unnecessary regexp match on static string %s, use %s%s%q instead`, lm, lm.Name, op, lm.Value),
 						Severity: Bug,
+						Fix:      staticMatcherFix(expr, lm, op),
 					})
 				}
 				if beginText > 1 || endText > 1 {
@@ -82,6 +107,7 @@ func (c RegexpCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 							lm, lm.Name, lm.Type, lm.Value,
 						),
 						Severity: Bug,
+						Fix:      anchorsFix(expr, lm, beginText > 1, endText > 1),
 					})
 				}
 			}
@@ -90,3 +116,64 @@ func (c RegexpCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 
 	return
 }
+
+// staticMatcherFix replaces a regexp matcher with a plain string matcher,
+// but only if the regexp value doesn't use any escaped characters.
+func staticMatcherFix(expr parser.PromQLExpr, lm *labels.Matcher, op labels.MatchType) *Fix {
+	if regexp.QuoteMeta(lm.Value) != lm.Value {
+		return nil
+	}
+	return matcherFix(expr, lm.Name, lm.Type.String(), lm.Value, op.String(), lm.Value)
+}
+
+// anchorsFix removes redundant ^ and $ anchors from a regexp matcher.
+func anchorsFix(expr parser.PromQLExpr, lm *labels.Matcher, begin, end bool) *Fix {
+	value := lm.Value
+	if begin {
+		value = strings.TrimPrefix(value, "^")
+	}
+	if end && !strings.HasSuffix(value, `\$`) {
+		value = strings.TrimSuffix(value, "$")
+	}
+	if value == lm.Value {
+		return nil
+	}
+	return matcherFix(expr, lm.Name, lm.Type.String(), lm.Value, lm.Type.String(), value)
+}
+
+// nameMatcherFix replaces an explicit __name__ matcher with the metric name
+// placed in front of the selector.
+func nameMatcherFix(expr parser.PromQLExpr, name string) *Fix {
+	for _, qs := range quoteStyles {
+		matcher := labels.MetricName + `[ \t]*=[ \t]*` + regexp.QuoteMeta(qs.format(name))
+		for _, pattern := range []struct {
+			re  *regexp.Regexp
+			new func(m []string) string
+		}{
+			{
+				re:  regexp.MustCompile(`\{[ \t]*` + matcher + `[ \t]*,?[ \t]*\}`),
+				new: func(m []string) string { return name },
+			},
+			{
+				re:  regexp.MustCompile(`\{[ \t]*` + matcher + `[ \t]*,[ \t]*`),
+				new: func(m []string) string { return name + "{" },
+			},
+			{
+				re:  regexp.MustCompile(`\{([^{}]*?)[ \t]*,[ \t]*` + matcher + `([^{}]*)\}`),
+				new: func(m []string) string { return name + "{" + m[1] + m[2] + "}" },
+			},
+		} {
+			for _, line := range strings.Split(expr.Value.Value, "\n") {
+				m := pattern.re.FindStringSubmatch(line)
+				if m == nil {
+					continue
+				}
+				if edit := exprEdit(expr, m[0], pattern.new(m)); edit != nil {
+					return &Fix{Edits: []Edit{*edit}}
+				}
+				return nil
+			}
+		}
+	}
+	return nil
+}
@@ -61,6 +61,9 @@ func TestRegexpCheck(t *testing.T) {
 						Reporter: checks.RegexpCheckName,
 						Text:     `unnecessary regexp match on static string job=~"bar", use job="bar" instead`,
 						Severity: checks.Bug,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{{Line: 2, Old: `job=~"bar"`, New: `job="bar"`}},
+						},
 					},
 				}
 			},
@@ -78,6 +81,9 @@ func TestRegexpCheck(t *testing.T) {
 						Reporter: checks.RegexpCheckName,
 						Text:     `unnecessary regexp match on static string job!~"bar", use job!="bar" instead`,
 						Severity: checks.Bug,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{{Line: 2, Old: `job!~"bar"`, New: `job!="bar"`}},
+						},
 					},
 				}
 			},
@@ -95,10 +101,80 @@ func TestRegexpCheck(t *testing.T) {
 						Reporter: checks.RegexpCheckName,
 						Text:     `unnecessary regexp match on static string job=~"", use job="" instead`,
 						Severity: checks.Bug,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{{Line: 2, Old: `job=~""`, New: `job=""`}},
+						},
 					},
 				}
 			},
 		},
+		{
+			description: "redundant name matcher",
+			content:     "- record: foo\n  expr: 'sum({__name__=\"foo\"})'\n",
+			checker:     newRegexpCheck,
+			prometheus:  noProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: `{__name__="foo"}`,
+						Lines:    []int{2},
+						Reporter: checks.RegexpCheckName,
+						Text:     `redundant __name__="foo" matcher, use foo instead`,
+						Severity: checks.Information,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{{Line: 2, Old: `{__name__="foo"}`, New: `foo`}},
+						},
+					},
+				}
+			},
+		},
+		{
+			description: "redundant name matcher before other matchers",
+			content:     "- record: foo\n  expr: '{__name__=\"foo\", job=\"bar\"}'\n",
+			checker:     newRegexpCheck,
+			prometheus:  noProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: `{__name__="foo",job="bar"}`,
+						Lines:    []int{2},
+						Reporter: checks.RegexpCheckName,
+						Text:     `redundant __name__="foo" matcher, use foo{job="bar"} instead`,
+						Severity: checks.Information,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{{Line: 2, Old: `{__name__="foo", `, New: `foo{`}},
+						},
+					},
+				}
+			},
+		},
+		{
+			description: "redundant name matcher after other matchers",
+			content:     "- record: foo\n  expr: '{job=\"bar\", __name__=\"foo\", env=\"prod\"}'\n",
+			checker:     newRegexpCheck,
+			prometheus:  noProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: `{__name__="foo",env="prod",job="bar"}`,
+						Lines:    []int{2},
+						Reporter: checks.RegexpCheckName,
+						Text:     `redundant __name__="foo" matcher, use foo{env="prod",job="bar"} instead`,
+						Severity: checks.Information,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{{Line: 2, Old: `{job="bar", __name__="foo", env="prod"}`, New: `foo{job="bar", env="prod"}`}},
+						},
+					},
+				}
+			},
+		},
+		{
+			description: "name matcher with invalid metric name",
+			content:     "- record: foo\n  expr: '{__name__=\"foo-bar\", job=\"bar\"}'\n",
+			checker:     newRegexpCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
 		{
 			description: "unnecessary regexp anchor",
 			content:     "- record: foo\n  expr: foo{job=~\"^.+$\"}\n",
@@ -112,6 +188,9 @@ func TestRegexpCheck(t *testing.T) {
 						Reporter: checks.RegexpCheckName,
 						Text:     `prometheus regexp matchers are automatically fully anchored so match for job=~"^.+$" will result in job=~"^^.+$$", remove regexp anchors ^ and/or $`,
 						Severity: checks.Bug,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{{Line: 2, Old: `job=~"^.+$"`, New: `job=~".+"`}},
+						},
 					},
 				}
 			},
@@ -129,6 +208,66 @@ func TestRegexpCheck(t *testing.T) {
 						Reporter: checks.RegexpCheckName,
 						Text:     `prometheus regexp matchers are automatically fully anchored so match for job=~"(foo|^.+)$" will result in job=~"^(foo|^.+)$$", remove regexp anchors ^ and/or $`,
 						Severity: checks.Bug,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{{Line: 2, Old: `job=~"(foo|^.+)$"`, New: `job=~"(foo|^.+)"`}},
+						},
+					},
+				}
+			},
+		},
+		{
+			description: "unnecessary regexp with single quotes",
+			content:     "- record: foo\n  expr: foo{job =~ 'bar'}\n",
+			checker:     newRegexpCheck,
+			prometheus:  noProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: `foo{job=~"bar"}`,
+						Lines:    []int{2},
+						Reporter: checks.RegexpCheckName,
+						Text:     `unnecessary regexp match on static string job=~"bar", use job="bar" instead`,
+						Severity: checks.Bug,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{{Line: 2, Old: `job =~ 'bar'`, New: `job = 'bar'`}},
+						},
+					},
+				}
+			},
+		},
+		{
+			description: "unnecessary regexp in multi-line query",
+			content:     "- record: foo\n  expr: |\n    sum(\n      foo{job=~\"bar\"}\n    )\n",
+			checker:     newRegexpCheck,
+			prometheus:  noProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: `foo{job=~"bar"}`,
+						Lines:    []int{2, 3, 4, 5},
+						Reporter: checks.RegexpCheckName,
+						Text:     `unnecessary regexp match on static string job=~"bar", use job="bar" instead`,
+						Severity: checks.Bug,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{{Line: 4, Old: `job=~"bar"`, New: `job="bar"`}},
+						},
+					},
+				}
+			},
+		},
+		{
+			description: "unnecessary regexp with escaped characters",
+			content:     "- record: foo\n  expr: foo{job=~\"bar\\\\.baz\"}\n",
+			checker:     newRegexpCheck,
+			prometheus:  noProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: `foo{job=~"bar\\.baz"}`,
+						Lines:    []int{2},
+						Reporter: checks.RegexpCheckName,
+						Text:     `unnecessary regexp match on static string job=~"bar\\.baz", use job="bar\\.baz" instead`,
+						Severity: checks.Bug,
 					},
 				}
 			},
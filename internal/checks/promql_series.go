@@ -47,6 +47,7 @@ func (c SeriesCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 	rangeLookback := time.Hour * 24 * 7
 	rangeStep := time.Minute * 5
 
+	now := time.Now()
 	done := map[string]bool{}
 	for _, selector := range getSelectors(expr.Query) {
 		if _, ok := done[selector.String()]; ok {
@@ -58,7 +59,9 @@ func (c SeriesCheck) Check(ctx context.Context, rule parser.Rule, entries []disc
 		bareSelector := stripLabels(selector)
 		c1 := fmt.Sprintf("disable %s(%s)", SeriesCheckName, selector.String())
 		c2 := fmt.Sprintf("disable %s(%s)", SeriesCheckName, bareSelector.String())
-		if rule.HasComment(c1) || rule.HasComment(c2) {
+		if rule.HasComment(c1) || rule.HasComment(c2) ||
+			rule.IsSnoozed(fmt.Sprintf("%s(%s)", SeriesCheckName, selector.String()), now) ||
+			rule.IsSnoozed(fmt.Sprintf("%s(%s)", SeriesCheckName, bareSelector.String()), now) {
 			done[selector.String()] = true
 			continue
 		}
@@ -405,7 +408,7 @@ func (c SeriesCheck) seriesTimeRanges(ctx context.Context, query string, lookbac
 		uri:   qr.URI,
 		from:  qr.Start.Round(time.Second),
 		until: qr.End.Round(time.Second),
-		step:  step,
+		step:  qr.Step,
 	}
 
 	var ts time.Time
@@ -417,8 +420,8 @@ func (c SeriesCheck) seriesTimeRanges(ctx context.Context, query string, lookbac
 			for i := range tr.ranges {
 				if tr.ranges[i].labels.Equal(model.LabelSet(s.Metric)) &&
 					!ts.Before(tr.ranges[i].start) &&
-					!ts.After(tr.ranges[i].end.Add(step)) {
-					tr.ranges[i].end = ts.Add(step)
+					!ts.After(tr.ranges[i].end.Add(tr.step)) {
+					tr.ranges[i].end = ts.Add(tr.step)
 					found = true
 					break
 				}
@@ -427,7 +430,7 @@ func (c SeriesCheck) seriesTimeRanges(ctx context.Context, query string, lookbac
 				tr.ranges = append(tr.ranges, timeRange{
 					labels: model.LabelSet(s.Metric),
 					start:  ts,
-					end:    ts.Add(step),
+					end:    ts.Add(tr.step),
 				})
 			}
 		}
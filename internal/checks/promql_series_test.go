@@ -2180,6 +2180,17 @@ func TestSeriesCheck(t *testing.T) {
 # pint disable promql/series(notfound)
 - record: foo
   expr: count(notfound{job!="foo"}) == 0
+`,
+			checker:    newSeriesCheck,
+			prometheus: newSimpleProm,
+			problems:   noProblems,
+		},
+		{
+			description: "series missing but check snoozed",
+			content: `
+# pint disable promql/series(notfound) until 2099-01-01 waiting for new exporter
+- record: foo
+  expr: count(notfound) == 0
 `,
 			checker:    newSeriesCheck,
 			prometheus: newSimpleProm,
@@ -3,6 +3,7 @@ package checks
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/cloudflare/pint/internal/discovery"
 	"github.com/cloudflare/pint/internal/output"
@@ -14,20 +15,24 @@ const (
 	CostCheckName = "query/cost"
 )
 
-func NewCostCheck(prom *promapi.FailoverGroup, bps, maxSeries int, severity Severity) CostCheck {
+func NewCostCheck(prom *promapi.FailoverGroup, bps, maxSeries, maxPeakSamples int, maxEvaluationDuration time.Duration, severity Severity) CostCheck {
 	return CostCheck{
-		prom:           prom,
-		bytesPerSample: bps,
-		maxSeries:      maxSeries,
-		severity:       severity,
+		prom:                  prom,
+		bytesPerSample:        bps,
+		maxSeries:             maxSeries,
+		maxPeakSamples:        maxPeakSamples,
+		maxEvaluationDuration: maxEvaluationDuration,
+		severity:              severity,
 	}
 }
 
 type CostCheck struct {
-	prom           *promapi.FailoverGroup
-	bytesPerSample int
-	maxSeries      int
-	severity       Severity
+	prom                  *promapi.FailoverGroup
+	bytesPerSample        int
+	maxSeries             int
+	maxPeakSamples        int
+	maxEvaluationDuration time.Duration
+	severity              Severity
 }
 
 func (c CostCheck) String() string {
@@ -49,7 +54,7 @@ func (c CostCheck) Check(ctx context.Context, rule parser.Rule, entries []discov
 	}
 
 	query := fmt.Sprintf("count(%s)", expr.Value.Value)
-	qr, err := c.prom.Query(ctx, query)
+	qr, err := c.prom.QueryWithStats(ctx, query)
 	if err != nil {
 		text, severity := textAndSeverityFromError(err, c.Reporter(), c.prom.Name(), Bug)
 		problems = append(problems, Problem{
@@ -72,18 +77,32 @@ func (c CostCheck) Check(ctx context.Context, rule parser.Rule, entries []discov
 		estimate = fmt.Sprintf(" with %s estimated memory usage", output.HumanizeBytes(c.bytesPerSample*series))
 	}
 
+	var stats string
+	if qr.Stats != nil {
+		stats = fmt.Sprintf(", query loaded %d sample(s) with %d peak sample(s) and took %s to evaluate",
+			qr.Stats.Samples.TotalQueryableSamples, qr.Stats.Samples.PeakSamples, output.HumanizeDuration(qr.Stats.EvalDuration()))
+	}
+
 	var above string
 	severity := Information
 	if c.maxSeries > 0 && series > c.maxSeries {
 		severity = c.severity
-		above = fmt.Sprintf(", maximum allowed series is %d", c.maxSeries)
+		above += fmt.Sprintf(", maximum allowed series is %d", c.maxSeries)
+	}
+	if qr.Stats != nil && c.maxPeakSamples > 0 && qr.Stats.Samples.PeakSamples > c.maxPeakSamples {
+		severity = c.severity
+		above += fmt.Sprintf(", maximum allowed peak samples is %d", c.maxPeakSamples)
+	}
+	if qr.Stats != nil && c.maxEvaluationDuration > 0 && qr.Stats.EvalDuration() > c.maxEvaluationDuration {
+		severity = c.severity
+		above += fmt.Sprintf(", maximum allowed evaluation duration is %s", output.HumanizeDuration(c.maxEvaluationDuration))
 	}
 
 	problems = append(problems, Problem{
 		Fragment: expr.Value.Value,
 		Lines:    expr.Lines(),
 		Reporter: c.Reporter(),
-		Text:     fmt.Sprintf("%s returned %d result(s)%s%s", promText(c.prom.Name(), qr.URI), series, estimate, above),
+		Text:     fmt.Sprintf("%s returned %d result(s)%s%s%s", promText(c.prom.Name(), qr.URI), series, estimate, stats, above),
 		Severity: severity,
 	})
 	return
@@ -0,0 +1,238 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/output"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+// CostBudget limits the total number of series produced by all recording
+// rules with the same owner, or all recording rules defined in the same file,
+// on a single Prometheus server.
+// Only one of Owner or Path should be set.
+type CostBudget struct {
+	Owner          *regexp.Regexp
+	Path           *regexp.Regexp
+	BytesPerSample int
+	MaxSeries      int
+	MaxBytes       int
+	Severity       Severity
+}
+
+// scope returns the name of the owner or file given entry is counted
+// towards, or false if this budget doesn't apply to it.
+func (cb CostBudget) scope(entry discovery.Entry) (string, bool) {
+	switch {
+	case cb.Owner != nil:
+		if entry.Owner == "" || !cb.Owner.MatchString(entry.Owner) {
+			return "", false
+		}
+		return entry.Owner, true
+	case cb.Path != nil:
+		if !cb.Path.MatchString(entry.Path) {
+			return "", false
+		}
+		return entry.Path, true
+	}
+	return "", false
+}
+
+func (cb CostBudget) scopeText(scope string) string {
+	if cb.Owner != nil {
+		return fmt.Sprintf("owned by %q", scope)
+	}
+	return fmt.Sprintf("in %q", scope)
+}
+
+func (cb CostBudget) seriesText(series int) string {
+	if cb.BytesPerSample > 0 {
+		return fmt.Sprintf("%d series (%s estimated memory usage)", series, output.HumanizeBytes(cb.BytesPerSample*series))
+	}
+	return fmt.Sprintf("%d series", series)
+}
+
+// CostBudgetViolation is a modified recording rule that pushed the total
+// cost of all recording rules it's counted towards over the budget.
+type CostBudgetViolation struct {
+	Entry   discovery.Entry
+	Problem Problem
+}
+
+type costBudgetKey struct {
+	budget int
+	scope  string
+	prom   string
+}
+
+type costBudgetTotal struct {
+	before int
+	after  int
+	failed bool
+}
+
+// FindCostBudgetViolations calculates the total number of series produced by
+// recording rules before and after changes on current branch and reports
+// modified rules that push the total over any of the budgets.
+// Base entries should include all rules from the base branch, current entries
+// all rules from the repository and modified entries only rules modified on
+// current branch. Servers is used to find Prometheus servers for each rule.
+// Only budgets that any of modified rules are counted towards are checked.
+func FindCostBudgetViolations(
+	ctx context.Context,
+	budgets []CostBudget,
+	base, current, modified []discovery.Entry,
+	servers func(discovery.Entry) []*promapi.FailoverGroup,
+) (violations []CostBudgetViolation) {
+	totals := map[costBudgetKey]*costBudgetTotal{}
+	for _, entry := range modified {
+		if !isBudgetEntry(entry) {
+			continue
+		}
+		for i, budget := range budgets {
+			scope, ok := budget.scope(entry)
+			if !ok {
+				continue
+			}
+			for _, prom := range servers(entry) {
+				totals[costBudgetKey{budget: i, scope: scope, prom: prom.Name()}] = &costBudgetTotal{}
+			}
+		}
+	}
+	if len(totals) == 0 {
+		return nil
+	}
+
+	counter := costBudgetCounter{}
+	count := func(entries []discovery.Entry, isBase bool) {
+		for _, entry := range entries {
+			if !isBudgetEntry(entry) {
+				continue
+			}
+			for i, budget := range budgets {
+				scope, ok := budget.scope(entry)
+				if !ok {
+					continue
+				}
+				for _, prom := range servers(entry) {
+					total, ok := totals[costBudgetKey{budget: i, scope: scope, prom: prom.Name()}]
+					if !ok || total.failed {
+						continue
+					}
+					series, err := counter.series(ctx, prom, entry)
+					if err != nil {
+						log.Warn().
+							Err(err).
+							Str("path", entry.Path).
+							Str("record", entry.Rule.RecordingRule.Record.Value.Value).
+							Str("prometheus", prom.Name()).
+							Msg("Failed to query series count, cost budget won't be checked")
+						total.failed = true
+						continue
+					}
+					if isBase {
+						total.before += series
+					} else {
+						total.after += series
+					}
+				}
+			}
+		}
+	}
+	count(base, true)
+	count(current, false)
+
+	unchanged := map[string]struct{}{}
+	for _, entry := range base {
+		if isBudgetEntry(entry) {
+			unchanged[costBudgetRuleKey(entry)] = struct{}{}
+		}
+	}
+
+	for _, entry := range modified {
+		if !isBudgetEntry(entry) {
+			continue
+		}
+		// Only report rules that were added or had their query modified.
+		if _, ok := unchanged[costBudgetRuleKey(entry)]; ok {
+			continue
+		}
+		if entry.Rule.HasComment(fmt.Sprintf("disable %s", CostCheckName)) {
+			continue
+		}
+		for i, budget := range budgets {
+			scope, ok := budget.scope(entry)
+			if !ok {
+				continue
+			}
+			for _, prom := range servers(entry) {
+				total := totals[costBudgetKey{budget: i, scope: scope, prom: prom.Name()}]
+				if total.failed || total.after <= total.before {
+					continue
+				}
+
+				var above string
+				if budget.MaxSeries > 0 && total.after > budget.MaxSeries {
+					above += fmt.Sprintf(", maximum allowed series is %d", budget.MaxSeries)
+				}
+				if budget.MaxBytes > 0 && budget.BytesPerSample*total.after > budget.MaxBytes {
+					above += fmt.Sprintf(", maximum allowed memory usage is %s", output.HumanizeBytes(budget.MaxBytes))
+				}
+				if above == "" {
+					continue
+				}
+
+				expr := entry.Rule.Expr()
+				violations = append(violations, CostBudgetViolation{
+					Entry: entry,
+					Problem: Problem{
+						Fragment: expr.Value.Value,
+						Lines:    expr.Lines(),
+						Reporter: CostCheckName,
+						Text: fmt.Sprintf("total cost of recording rules %s on prometheus %q would increase from %s to %s%s",
+							budget.scopeText(scope), prom.Name(), budget.seriesText(total.before), budget.seriesText(total.after), above),
+						Severity: budget.Severity,
+					},
+				})
+			}
+		}
+	}
+	return violations
+}
+
+func isBudgetEntry(entry discovery.Entry) bool {
+	return isValidEntry(entry) && entry.Rule.RecordingRule != nil && entry.Rule.Expr().SyntaxError == nil
+}
+
+func costBudgetRuleKey(entry discovery.Entry) string {
+	return entry.Path + "\n" + entry.Rule.RecordingRule.Record.Value.Value + "\n" + entry.Rule.Expr().Value.Value
+}
+
+// costBudgetCounter remembers the number of series returned for each query,
+// so every recording rule is only queried once per Prometheus server.
+type costBudgetCounter map[string]int
+
+func (cc costBudgetCounter) series(ctx context.Context, prom *promapi.FailoverGroup, entry discovery.Entry) (int, error) {
+	query := fmt.Sprintf("count(%s)", entry.Rule.Expr().Value.Value)
+	key := prom.Name() + "\n" + entry.Tenant + "\n" + query
+	if series, ok := cc[key]; ok {
+		return series, nil
+	}
+
+	qr, err := prom.Query(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	var series int
+	for _, s := range qr.Series {
+		series += int(s.Value)
+	}
+	cc[key] = series
+	return series, nil
+}
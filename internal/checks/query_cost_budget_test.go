@@ -0,0 +1,176 @@
+package checks_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestFindCostBudgetViolations(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+
+	series := map[string]int{
+		"count(sum(foo))": 100,
+		"count(sum(bar))": 50,
+		"count(sum(baz))": 200,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		count, ok := series[r.Form.Get("query")]
+		if !ok {
+			w.WriteHeader(400)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"unhandled query"}`))
+			return
+		}
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1614859502.068,"%d"]}]}}`, count)))
+	}))
+	defer srv.Close()
+
+	prom := simpleProm("prom", srv.URL, time.Second, true)
+	prom.StartWorkers()
+	defer prom.Close()
+	servers := func(discovery.Entry) []*promapi.FailoverGroup {
+		return []*promapi.FailoverGroup{prom}
+	}
+
+	withOwner := func(owner string, entries []discovery.Entry) []discovery.Entry {
+		for i := range entries {
+			entries[i].Owner = owner
+		}
+		return entries
+	}
+
+	ownerBudget := checks.CostBudget{
+		Owner:     regexp.MustCompile("^team-.+$"),
+		MaxSeries: 120,
+		Severity:  checks.Bug,
+	}
+
+	type testCaseT struct {
+		description string
+		budgets     []checks.CostBudget
+		base        []discovery.Entry
+		current     []discovery.Entry
+		modified    []discovery.Entry
+		problems    []checks.Problem
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "no budgets",
+			base:        withOwner("team-a", mustParseContent("- record: foo\n  expr: sum(foo)\n")),
+			current:     withOwner("team-a", mustParseContent("- record: foo\n  expr: sum(foo)\n- record: bar\n  expr: sum(bar)\n")),
+			modified:    withOwner("team-a", mustParseContent("- record: bar\n  expr: sum(bar)\n")),
+		},
+		{
+			description: "owner below budget",
+			budgets:     []checks.CostBudget{ownerBudget},
+			base:        withOwner("team-a", mustParseContent("- record: bar\n  expr: sum(bar)\n")),
+			current:     withOwner("team-a", mustParseContent("- record: bar\n  expr: sum(bar)\n- record: bar:sum\n  expr: sum(bar)\n")),
+			modified:    withOwner("team-a", mustParseContent("- record: bar:sum\n  expr: sum(bar)\n")),
+		},
+		{
+			description: "owner pushed over budget",
+			budgets:     []checks.CostBudget{ownerBudget},
+			base:        withOwner("team-a", mustParseContent("- record: foo\n  expr: sum(foo)\n")),
+			current:     withOwner("team-a", mustParseContent("- record: foo\n  expr: sum(foo)\n- record: bar\n  expr: sum(bar)\n")),
+			modified:    withOwner("team-a", mustParseContent("- record: foo\n  expr: sum(foo)\n- record: bar\n  expr: sum(bar)\n")),
+			problems: []checks.Problem{
+				{
+					Fragment: "sum(bar)",
+					Lines:    []int{4},
+					Reporter: checks.CostCheckName,
+					Text:     `total cost of recording rules owned by "team-a" on prometheus "prom" would increase from 100 series to 150 series, maximum allowed series is 120`,
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "owner already over budget and total decreased",
+			budgets:     []checks.CostBudget{ownerBudget},
+			base:        withOwner("team-a", mustParseContent("- record: baz\n  expr: sum(baz)\n")),
+			current:     withOwner("team-a", mustParseContent("- record: baz\n  expr: sum(foo)\n")),
+			modified:    withOwner("team-a", mustParseContent("- record: baz\n  expr: sum(foo)\n")),
+		},
+		{
+			description: "rules without owner are not counted",
+			budgets:     []checks.CostBudget{ownerBudget},
+			base:        mustParseContent("- record: foo\n  expr: sum(foo)\n"),
+			current:     mustParseContent("- record: foo\n  expr: sum(foo)\n- record: bar\n  expr: sum(bar)\n"),
+			modified:    mustParseContent("- record: bar\n  expr: sum(bar)\n"),
+		},
+		{
+			description: "other owners are not counted",
+			budgets:     []checks.CostBudget{ownerBudget},
+			base: append(
+				withOwner("team-b", mustParseContent("- record: foo\n  expr: sum(foo)\n")),
+				withOwner("team-a", mustParseContent("- alert: foo\n  expr: foo == 0\n"))...,
+			),
+			current: append(
+				withOwner("team-b", mustParseContent("- record: foo\n  expr: sum(foo)\n")),
+				withOwner("team-a", mustParseContent("- record: bar\n  expr: sum(bar)\n"))...,
+			),
+			modified: withOwner("team-a", mustParseContent("- record: bar\n  expr: sum(bar)\n")),
+		},
+		{
+			description: "file pushed over memory budget",
+			budgets: []checks.CostBudget{
+				{
+					Path:           regexp.MustCompile("^fake.yml$"),
+					BytesPerSample: 1024,
+					MaxBytes:       1024 * 200,
+					Severity:       checks.Warning,
+				},
+			},
+			base:     mustParseContent("- record: foo\n  expr: sum(foo)\n"),
+			current:  mustParseContent("- record: foo\n  expr: sum(foo)\n- record: baz\n  expr: sum(baz)\n"),
+			modified: mustParseContent("- record: baz\n  expr: sum(baz)\n"),
+			problems: []checks.Problem{
+				{
+					Fragment: "sum(baz)",
+					Lines:    []int{2},
+					Reporter: checks.CostCheckName,
+					Text:     `total cost of recording rules in "fake.yml" on prometheus "prom" would increase from 100 series (100.0KiB estimated memory usage) to 300 series (300.0KiB estimated memory usage), maximum allowed memory usage is 200.0KiB`,
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "query error",
+			budgets:     []checks.CostBudget{ownerBudget},
+			base:        withOwner("team-a", mustParseContent("- record: foo\n  expr: sum(foo)\n")),
+			current:     withOwner("team-a", mustParseContent("- record: foo\n  expr: sum(foo)\n- record: bar\n  expr: sum(bar)\n- record: unknown\n  expr: sum(unknown)\n")),
+			modified:    withOwner("team-a", mustParseContent("- record: bar\n  expr: sum(bar)\n")),
+		},
+		{
+			description: "disabled by comment",
+			budgets:     []checks.CostBudget{ownerBudget},
+			base:        withOwner("team-a", mustParseContent("- record: foo\n  expr: sum(foo)\n")),
+			current:     withOwner("team-a", mustParseContent("- record: foo\n  expr: sum(foo)\n# pint disable query/cost\n- record: bar\n  expr: sum(bar)\n")),
+			modified:    withOwner("team-a", mustParseContent("# pint disable query/cost\n- record: bar\n  expr: sum(bar)\n")),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var problems []checks.Problem
+			for _, v := range checks.FindCostBudgetViolations(context.Background(), tc.budgets, tc.base, tc.current, tc.modified, servers) {
+				problems = append(problems, v.Problem)
+			}
+			require.Equal(t, tc.problems, problems)
+		})
+	}
+}
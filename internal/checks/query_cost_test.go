@@ -23,6 +23,18 @@ func maxSeriesText(m int) string {
 	return fmt.Sprintf(", maximum allowed series is %d", m)
 }
 
+func statsText(total, peak int, dur string) string {
+	return fmt.Sprintf(", query loaded %d sample(s) with %d peak sample(s) and took %s to evaluate", total, peak, dur)
+}
+
+func maxPeakSamplesText(m int) string {
+	return fmt.Sprintf(", maximum allowed peak samples is %d", m)
+}
+
+func maxEvaluationDurationText(d string) string {
+	return fmt.Sprintf(", maximum allowed evaluation duration is %s", d)
+}
+
 func TestCostCheck(t *testing.T) {
 	content := "- record: foo\n  expr: sum(foo)\n"
 
@@ -31,7 +43,7 @@ func TestCostCheck(t *testing.T) {
 			description: "ignores rules with syntax errors",
 			content:     "- record: foo\n  expr: sum(foo) without(\n",
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 4096, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 4096, 0, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems:   noProblems,
@@ -40,7 +52,7 @@ func TestCostCheck(t *testing.T) {
 			description: "empty response",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 4096, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 4096, 0, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -68,7 +80,7 @@ func TestCostCheck(t *testing.T) {
 			description: "response timeout",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 4096, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 4096, 0, 0, 0, checks.Bug)
 			},
 			prometheus: func(uri string) *promapi.FailoverGroup {
 				return simpleProm("prom", uri, time.Millisecond*50, true)
@@ -98,7 +110,7 @@ func TestCostCheck(t *testing.T) {
 			description: "bad request",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 4096, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 4096, 0, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -126,7 +138,7 @@ func TestCostCheck(t *testing.T) {
 			description: "connection refused",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 4096, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 4096, 0, 0, 0, checks.Bug)
 			},
 			prometheus: func(s string) *promapi.FailoverGroup {
 				return simpleProm("prom", "http://127.0.0.1:1111", time.Second*5, false)
@@ -147,7 +159,7 @@ func TestCostCheck(t *testing.T) {
 			description: "1 result",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 4096, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 4096, 0, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -175,7 +187,7 @@ func TestCostCheck(t *testing.T) {
 			description: "7 results",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 101, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 101, 0, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -213,7 +225,7 @@ func TestCostCheck(t *testing.T) {
 			description: "7 result with MB",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 1024*1024, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 1024*1024, 0, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -251,7 +263,7 @@ func TestCostCheck(t *testing.T) {
 			description: "7 results with 1 series max (1KB bps)",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 1024, 1, checks.Bug)
+				return checks.NewCostCheck(prom, 1024, 1, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -289,7 +301,7 @@ func TestCostCheck(t *testing.T) {
 			description: "6 results with 5 series max",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 0, 5, checks.Bug)
+				return checks.NewCostCheck(prom, 0, 5, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -326,7 +338,7 @@ func TestCostCheck(t *testing.T) {
 			description: "7 results with 5 series max / infi",
 			content:     content,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 0, 5, checks.Information)
+				return checks.NewCostCheck(prom, 0, 5, 0, 0, checks.Information)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -367,7 +379,7 @@ func TestCostCheck(t *testing.T) {
   expr: 'sum({__name__="foo"})'
 `,
 			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
-				return checks.NewCostCheck(prom, 101, 0, checks.Bug)
+				return checks.NewCostCheck(prom, 101, 0, 0, 0, checks.Bug)
 			},
 			prometheus: newSimpleProm,
 			problems: func(uri string) []checks.Problem {
@@ -401,6 +413,139 @@ func TestCostCheck(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "1 result with stats",
+			content:     content,
+			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCostCheck(prom, 0, 0, 0, 0, checks.Bug)
+			},
+			prometheus: newSimpleProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "sum(foo)",
+						Lines:    []int{2},
+						Reporter: "query/cost",
+						Text:     costText("prom", uri, 1) + statsText(5000, 300, "1s500ms"),
+						Severity: checks.Information,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: `count(sum(foo))`},
+						formCond{key: "stats", value: "all"},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSample(map[string]string{})},
+						stats: &promapi.QueryStats{
+							Timings: promapi.QueryTimings{EvalTotalTime: 1.5},
+							Samples: promapi.QuerySamples{TotalQueryableSamples: 5000, PeakSamples: 300},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "1 result with stats above limits",
+			content:     content,
+			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCostCheck(prom, 0, 0, 200, time.Second, checks.Warning)
+			},
+			prometheus: newSimpleProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "sum(foo)",
+						Lines:    []int{2},
+						Reporter: "query/cost",
+						Text:     costText("prom", uri, 1) + statsText(5000, 300, "1s500ms") + maxPeakSamplesText(200) + maxEvaluationDurationText("1s"),
+						Severity: checks.Warning,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: `count(sum(foo))`},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSample(map[string]string{})},
+						stats: &promapi.QueryStats{
+							Timings: promapi.QueryTimings{EvalTotalTime: 1.5},
+							Samples: promapi.QuerySamples{TotalQueryableSamples: 5000, PeakSamples: 300},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "1 result with stats below limits",
+			content:     content,
+			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCostCheck(prom, 0, 0, 500, time.Second*2, checks.Warning)
+			},
+			prometheus: newSimpleProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "sum(foo)",
+						Lines:    []int{2},
+						Reporter: "query/cost",
+						Text:     costText("prom", uri, 1) + statsText(5000, 300, "1s500ms"),
+						Severity: checks.Information,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: `count(sum(foo))`},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSample(map[string]string{})},
+						stats: &promapi.QueryStats{
+							Timings: promapi.QueryTimings{EvalTotalTime: 1.5},
+							Samples: promapi.QuerySamples{TotalQueryableSamples: 5000, PeakSamples: 300},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "peak samples limit without stats",
+			content:     content,
+			checker: func(prom *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewCostCheck(prom, 0, 0, 1, time.Millisecond, checks.Warning)
+			},
+			prometheus: newSimpleProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "sum(foo)",
+						Lines:    []int{2},
+						Reporter: "query/cost",
+						Text:     costText("prom", uri, 1),
+						Severity: checks.Information,
+					},
+				}
+			},
+			mocks: []*prometheusMock{
+				{
+					conds: []requestCondition{
+						requireQueryPath,
+						formCond{key: "query", value: `count(sum(foo))`},
+					},
+					resp: vectorResponse{
+						samples: []*model.Sample{generateSample(map[string]string{})},
+					},
+				},
+			},
+		},
 	}
 
 	runTests(t, testCases)
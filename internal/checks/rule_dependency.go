@@ -0,0 +1,139 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+const (
+	DependencyCheckName = "rule/dependency"
+)
+
+func NewDependencyCheck() DependencyCheck {
+	return DependencyCheck{}
+}
+
+type DependencyCheck struct{}
+
+func (c DependencyCheck) String() string {
+	return DependencyCheckName
+}
+
+func (c DependencyCheck) Reporter() string {
+	return DependencyCheckName
+}
+
+func (c DependencyCheck) Check(ctx context.Context, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
+	expr := rule.Expr()
+	if expr.SyntaxError != nil {
+		return nil
+	}
+
+	index := dependencyIndexes.get(entries)
+	for _, dep := range index.dependencies(rule) {
+		if rule.RecordingRule != nil && dep.entry.Rule.RecordingRule == rule.RecordingRule {
+			continue
+		}
+		if text := evaluationLag(rule, dep); text != "" {
+			problems = append(problems, Problem{
+				Fragment: dep.selector.String(),
+				Lines:    expr.Lines(),
+				Reporter: c.Reporter(),
+				Text:     text,
+				Severity: Warning,
+			})
+		}
+	}
+
+	if rule.RecordingRule != nil {
+		if cycle := index.findCycle(rule); len(cycle) > 0 {
+			names := []string{rule.Name()}
+			for _, dep := range cycle {
+				names = append(names, dep.entry.Rule.Name())
+			}
+			problems = append(problems, Problem{
+				Fragment: cycle[0].selector.String(),
+				Lines:    expr.Lines(),
+				Reporter: c.Reporter(),
+				Text: fmt.Sprintf("recording rule %q depends on its own results, each rule here uses metrics produced by the next one: %s",
+					rule.Name(), strings.Join(names, " -> ")),
+				Severity: Bug,
+			})
+		}
+	}
+
+	return problems
+}
+
+// evaluationLag checks if the recording rule from dep is evaluated after given rule.
+// Rules in a group are evaluated in order, so if a rule uses metrics produced
+// by a recording rule defined after it, then it will always use results from
+// the previous evaluation of that recording rule.
+// Groups are evaluated concurrently, so there's no order between rules from
+// different groups.
+func evaluationLag(rule parser.Rule, dep dependency) string {
+	if rule.Group == nil || dep.entry.Rule.Group != rule.Group {
+		return ""
+	}
+	if dep.entry.Rule.GroupIndex > rule.GroupIndex {
+		return fmt.Sprintf("%q is produced by a recording rule defined later in the same group at %s:%d, this rule will always use results from its previous evaluation",
+			dep.entry.Rule.Name(), dep.entry.Path, ruleFirstLine(dep.entry.Rule))
+	}
+	return ""
+}
+
+// dependencyIndexes keeps the index built for the most recently used list
+// of entries. All rules checked during a single run are passed the same list,
+// so the index only needs to be built once.
+var dependencyIndexes = &recordingRulesIndexCache{}
+
+type recordingRulesIndexCache struct {
+	mu      sync.Mutex
+	entries []discovery.Entry
+	index   recordingRulesIndex
+}
+
+func (cache *recordingRulesIndexCache) get(entries []discovery.Entry) recordingRulesIndex {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.index == nil || !isSameEntries(cache.entries, entries) {
+		cache.entries = entries
+		cache.index = newRecordingRulesIndex(entries)
+	}
+	return cache.index
+}
+
+func isSameEntries(a, b []discovery.Entry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	return len(a) == 0 || &a[0] == &b[0]
+}
+
+// findCycle returns a chain of dependencies that leads back to given recording rule.
+func (index recordingRulesIndex) findCycle(rule parser.Rule) []dependency {
+	visited := map[*parser.RecordingRule]struct{}{}
+	var walk func(r parser.Rule, chain []dependency) []dependency
+	walk = func(r parser.Rule, chain []dependency) []dependency {
+		for _, dep := range index.dependencies(r) {
+			if dep.entry.Rule.RecordingRule == rule.RecordingRule {
+				return append(chain, dep)
+			}
+			if _, ok := visited[dep.entry.Rule.RecordingRule]; ok {
+				continue
+			}
+			visited[dep.entry.Rule.RecordingRule] = struct{}{}
+			if cycle := walk(dep.entry.Rule, append(chain, dep)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return walk(rule, nil)
+}
@@ -0,0 +1,170 @@
+package checks_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func TestDependencyCheck(t *testing.T) {
+	type testCaseT struct {
+		description string
+		content     string
+		problems    []checks.Problem
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "ignores rules with syntax errors",
+			content:     "- record: foo\n  expr: sum(foo) without(\n",
+		},
+		{
+			description: "no dependencies",
+			content:     "- record: foo\n  expr: sum(bar)\n- alert: foo\n  expr: up == 0\n",
+		},
+		{
+			description: "dependency in earlier group",
+			content: `groups:
+- name: first
+  rules:
+  - record: job:up:sum
+    expr: sum(up) by(job)
+- name: second
+  rules:
+  - alert: down
+    expr: job:up:sum == 0
+`,
+		},
+		{
+			description: "dependency defined earlier in the same group",
+			content: `groups:
+- name: first
+  rules:
+  - record: job:up:sum
+    expr: sum(up) by(job)
+  - alert: down
+    expr: job:up:sum == 0
+`,
+		},
+		{
+			description: "dependency defined later in the same group",
+			content: `groups:
+- name: first
+  rules:
+  - alert: down
+    expr: job:up:sum == 0
+  - record: job:up:sum
+    expr: sum(up) by(job)
+`,
+			problems: []checks.Problem{
+				{
+					Fragment: "job:up:sum",
+					Lines:    []int{5},
+					Reporter: checks.DependencyCheckName,
+					Text:     `"job:up:sum" is produced by a recording rule defined later in the same group at fake.yml:6, this rule will always use results from its previous evaluation`,
+					Severity: checks.Warning,
+				},
+			},
+		},
+		{
+			description: "dependency in later group",
+			content: `groups:
+- name: first
+  rules:
+  - alert: down
+    expr: job:up:sum{job="foo"} == 0
+- name: second
+  rules:
+  - record: job:up:sum
+    expr: sum(up) by(job)
+`,
+		},
+		{
+			description: "dependency defined later in the same group with mismatched static labels",
+			content: `groups:
+- name: first
+  rules:
+  - alert: down
+    expr: job:up:sum{env="dev"} == 0
+  - record: job:up:sum
+    expr: sum(up) by(job)
+    labels:
+      env: prod
+`,
+		},
+		{
+			description: "dependency defined later in another group with the same name",
+			content: `groups:
+- name: first
+  rules:
+  - alert: down
+    expr: job:up:sum == 0
+- name: first
+  rules:
+  - record: job:up:sum
+    expr: sum(up) by(job)
+`,
+		},
+		{
+			description: "dependency cycle",
+			content: `groups:
+- name: first
+  rules:
+  - record: foo
+    expr: sum(bar)
+  - record: bar
+    expr: sum(foo)
+`,
+			problems: []checks.Problem{
+				{
+					Fragment: "bar",
+					Lines:    []int{5},
+					Reporter: checks.DependencyCheckName,
+					Text:     `"bar" is produced by a recording rule defined later in the same group at fake.yml:6, this rule will always use results from its previous evaluation`,
+					Severity: checks.Warning,
+				},
+				{
+					Fragment: "bar",
+					Lines:    []int{5},
+					Reporter: checks.DependencyCheckName,
+					Text:     `recording rule "foo" depends on its own results, each rule here uses metrics produced by the next one: foo -> bar -> foo`,
+					Severity: checks.Bug,
+				},
+				{
+					Fragment: "foo",
+					Lines:    []int{7},
+					Reporter: checks.DependencyCheckName,
+					Text:     `recording rule "bar" depends on its own results, each rule here uses metrics produced by the next one: bar -> foo -> bar`,
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "self reference",
+			content:     "- record: foo\n  expr: foo offset 1h\n",
+			problems: []checks.Problem{
+				{
+					Fragment: "foo",
+					Lines:    []int{2},
+					Reporter: checks.DependencyCheckName,
+					Text:     `recording rule "foo" depends on its own results, each rule here uses metrics produced by the next one: foo -> foo`,
+					Severity: checks.Bug,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			entries := mustParseContent(tc.content)
+			var problems []checks.Problem
+			for _, entry := range entries {
+				problems = append(problems, checks.NewDependencyCheck().Check(context.Background(), entry.Rule, entries)...)
+			}
+			require.Equal(t, tc.problems, problems)
+		})
+	}
+}
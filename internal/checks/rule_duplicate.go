@@ -0,0 +1,148 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+const (
+	DuplicateCheckName = "rule/duplicate"
+)
+
+// NewDuplicateCheck creates a new rule/duplicate check.
+// proms is the list of Prometheus servers the checked rule is deployed to
+// and serversFor is used to find Prometheus servers for all other rules.
+// Only rules deployed to at least one common server are compared.
+func NewDuplicateCheck(proms []*promapi.FailoverGroup, serversFor func(discovery.Entry) []*promapi.FailoverGroup) DuplicateCheck {
+	return DuplicateCheck{proms: proms, serversFor: serversFor}
+}
+
+type DuplicateCheck struct {
+	serversFor func(discovery.Entry) []*promapi.FailoverGroup
+	proms      []*promapi.FailoverGroup
+}
+
+func (c DuplicateCheck) String() string {
+	return DuplicateCheckName
+}
+
+func (c DuplicateCheck) Reporter() string {
+	return DuplicateCheckName
+}
+
+func (c DuplicateCheck) Check(ctx context.Context, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
+	if rule.RecordingRule != nil {
+		for _, entry := range entries {
+			if entry.PathError != nil || entry.Rule.RecordingRule == nil || entry.Rule.RecordingRule == rule.RecordingRule {
+				continue
+			}
+			if entry.Rule.RecordingRule.Record.Value.Value != rule.RecordingRule.Record.Value.Value {
+				continue
+			}
+			if !isSameLabels(entry.Rule.RecordingRule.Labels, rule.RecordingRule.Labels) {
+				continue
+			}
+			if !c.isSameServer(entry) {
+				continue
+			}
+			problems = append(problems, Problem{
+				Fragment: rule.RecordingRule.Record.Value.Value,
+				Lines:    rule.RecordingRule.Record.Lines(),
+				Reporter: c.Reporter(),
+				Text: fmt.Sprintf("duplicated recording rule, %q with identical labels is also recorded at %s:%d",
+					rule.RecordingRule.Record.Value.Value, entry.Path, entry.Rule.RecordingRule.Record.Key.Position.FirstLine()),
+				Severity: Warning,
+			})
+			break
+		}
+	}
+
+	if rule.AlertingRule != nil {
+		for _, entry := range entries {
+			if entry.PathError != nil || entry.Rule.AlertingRule == nil || entry.Rule.AlertingRule == rule.AlertingRule {
+				continue
+			}
+			if entry.Rule.AlertingRule.Alert.Value.Value != rule.AlertingRule.Alert.Value.Value {
+				continue
+			}
+			if !isSameExpr(entry.Rule.AlertingRule.Expr, rule.AlertingRule.Expr) {
+				continue
+			}
+			if !isSameLabels(entry.Rule.AlertingRule.Labels, rule.AlertingRule.Labels) {
+				continue
+			}
+			if !c.isSameServer(entry) {
+				continue
+			}
+			problems = append(problems, Problem{
+				Fragment: rule.AlertingRule.Alert.Value.Value,
+				Lines:    rule.AlertingRule.Alert.Lines(),
+				Reporter: c.Reporter(),
+				Text: fmt.Sprintf("duplicated alerting rule, %q with identical query and labels is also defined at %s:%d",
+					rule.AlertingRule.Alert.Value.Value, entry.Path, entry.Rule.AlertingRule.Alert.Key.Position.FirstLine()),
+				Severity: Warning,
+			})
+			break
+		}
+	}
+
+	return
+}
+
+// isSameServer returns true if given entry is deployed to at least one
+// of the Prometheus servers the checked rule is deployed to.
+// Rules that are not deployed to any server are only compared with
+// other rules that are not deployed to any server.
+func (c DuplicateCheck) isSameServer(entry discovery.Entry) bool {
+	var servers []*promapi.FailoverGroup
+	if c.serversFor != nil {
+		servers = c.serversFor(entry)
+	}
+	if len(c.proms) == 0 && len(servers) == 0 {
+		return true
+	}
+	for _, a := range c.proms {
+		for _, b := range servers {
+			if a.Name() == b.Name() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isSameExpr(a, b parser.PromQLExpr) bool {
+	if a.SyntaxError != nil || b.SyntaxError != nil {
+		return a.Value.Value == b.Value.Value
+	}
+	return a.Query.Node.String() == b.Query.Node.String()
+}
+
+func isSameLabels(a, b *parser.YamlMap) bool {
+	al := staticLabels(a)
+	bl := staticLabels(b)
+	if len(al) != len(bl) {
+		return false
+	}
+	for k, v := range al {
+		if bv, ok := bl[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+func staticLabels(m *parser.YamlMap) map[string]string {
+	labels := map[string]string{}
+	if m == nil {
+		return labels
+	}
+	for _, item := range m.Items {
+		labels[item.Key.Value] = item.Value.Value
+	}
+	return labels
+}
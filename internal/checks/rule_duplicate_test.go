@@ -0,0 +1,192 @@
+package checks_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func newDuplicateCheck(_ *promapi.FailoverGroup) checks.RuleChecker {
+	return checks.NewDuplicateCheck(nil, nil)
+}
+
+func newDuplicateCheckWithServers(rule, other []string) func(*promapi.FailoverGroup) checks.RuleChecker {
+	servers := func(names []string) (proms []*promapi.FailoverGroup) {
+		for _, name := range names {
+			proms = append(proms, simpleProm(name, "http://localhost", time.Second, true))
+		}
+		return proms
+	}
+	return func(_ *promapi.FailoverGroup) checks.RuleChecker {
+		return checks.NewDuplicateCheck(servers(rule), func(_ discovery.Entry) []*promapi.FailoverGroup {
+			return servers(other)
+		})
+	}
+}
+
+func TestDuplicateCheck(t *testing.T) {
+	testCases := []checkTest{
+		{
+			description: "ignores rules with syntax errors",
+			content:     "- record: foo\n  expr: sum(foo) without(\n",
+			checker:     newDuplicateCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "no other rules",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newDuplicateCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "recording rule with different name",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newDuplicateCheck,
+			prometheus:  noProm,
+			entries:     mustParseContent("- record: bar\n  expr: sum(foo)\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "recording rule with different labels",
+			content:     "- record: foo\n  expr: sum(foo)\n  labels:\n    job: foo\n",
+			checker:     newDuplicateCheck,
+			prometheus:  noProm,
+			entries:     mustParseContent("- record: foo\n  expr: sum(foo)\n  labels:\n    job: bar\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "recording rule with extra labels",
+			content:     "- record: foo\n  expr: sum(foo)\n  labels:\n    job: foo\n",
+			checker:     newDuplicateCheck,
+			prometheus:  noProm,
+			entries:     mustParseContent("- record: foo\n  expr: sum(foo)\n  labels:\n    job: foo\n    env: prod\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "duplicated recording rule",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newDuplicateCheck,
+			prometheus:  noProm,
+			entries:     mustParseContent("\n\n- record: foo\n  expr: sum(bar)\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "foo",
+						Lines:    []int{1},
+						Reporter: checks.DuplicateCheckName,
+						Text:     `duplicated recording rule, "foo" with identical labels is also recorded at fake.yml:3`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "duplicated recording rule with labels",
+			content:     "- record: foo\n  expr: sum(foo)\n  labels:\n    job: foo\n    env: prod\n",
+			checker:     newDuplicateCheck,
+			prometheus:  noProm,
+			entries:     mustParseContent("- record: foo\n  expr: sum(foo)\n  labels:\n    env: prod\n    job: foo\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "foo",
+						Lines:    []int{1},
+						Reporter: checks.DuplicateCheckName,
+						Text:     `duplicated recording rule, "foo" with identical labels is also recorded at fake.yml:1`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "alerting rule with different query",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker:     newDuplicateCheck,
+			prometheus:  noProm,
+			entries:     mustParseContent("- alert: foo\n  expr: up{job=\"foo\"} == 0\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "alerting rule with different labels",
+			content:     "- alert: foo\n  expr: up == 0\n  labels:\n    severity: page\n",
+			checker:     newDuplicateCheck,
+			prometheus:  noProm,
+			entries:     mustParseContent("- alert: foo\n  expr: up == 0\n  labels:\n    severity: ticket\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "duplicated alerting rule",
+			content:     "- alert: foo\n  expr: up == 0\n  for: 5m\n  labels:\n    severity: page\n",
+			checker:     newDuplicateCheck,
+			prometheus:  noProm,
+			entries:     mustParseContent("- alert: foo\n  expr: up   ==   0\n  labels:\n    severity: page\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "foo",
+						Lines:    []int{1},
+						Reporter: checks.DuplicateCheckName,
+						Text:     `duplicated alerting rule, "foo" with identical query and labels is also defined at fake.yml:1`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "ignores alerting rule matching recording rule",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker:     newDuplicateCheck,
+			prometheus:  noProm,
+			entries:     mustParseContent("- record: foo\n  expr: up == 0\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "duplicated recording rule on a different server",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newDuplicateCheckWithServers([]string{"prom1"}, []string{"prom2"}),
+			prometheus:  noProm,
+			entries:     mustParseContent("- record: foo\n  expr: sum(foo)\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "duplicated recording rule only deployed to some server",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newDuplicateCheckWithServers(nil, []string{"prom2"}),
+			prometheus:  noProm,
+			entries:     mustParseContent("- record: foo\n  expr: sum(foo)\n"),
+			problems:    noProblems,
+		},
+		{
+			description: "duplicated recording rule on a shared server",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newDuplicateCheckWithServers([]string{"prom1", "prom2"}, []string{"prom2", "prom3"}),
+			prometheus:  noProm,
+			entries:     mustParseContent("- record: foo\n  expr: sum(foo)\n"),
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "foo",
+						Lines:    []int{1},
+						Reporter: checks.DuplicateCheckName,
+						Text:     `duplicated recording rule, "foo" with identical labels is also recorded at fake.yml:1`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "duplicated alerting rule on a different server",
+			content:     "- alert: foo\n  expr: up == 0\n",
+			checker:     newDuplicateCheckWithServers([]string{"prom1"}, []string{"prom2"}),
+			prometheus:  noProm,
+			entries:     mustParseContent("- alert: foo\n  expr: up == 0\n"),
+			problems:    noProblems,
+		},
+	}
+	runTests(t, testCases)
+}
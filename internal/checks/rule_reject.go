@@ -43,23 +43,23 @@ func (c Reject) Reporter() string {
 func (c Reject) Check(ctx context.Context, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
 	if c.checkLabels && rule.AlertingRule != nil && rule.AlertingRule.Labels != nil {
 		for _, label := range rule.AlertingRule.Labels.Items {
-			problems = append(problems, c.reject(rule, label, "label")...)
+			problems = append(problems, c.reject(rule, rule.AlertingRule.Labels, label, "label")...)
 		}
 	}
 	if c.checkLabels && rule.RecordingRule != nil && rule.RecordingRule.Labels != nil {
 		for _, label := range rule.RecordingRule.Labels.Items {
-			problems = append(problems, c.reject(rule, label, "label")...)
+			problems = append(problems, c.reject(rule, rule.RecordingRule.Labels, label, "label")...)
 		}
 	}
 	if c.checkAnnotations && rule.AlertingRule != nil && rule.AlertingRule.Annotations != nil {
 		for _, ann := range rule.AlertingRule.Annotations.Items {
-			problems = append(problems, c.reject(rule, ann, "annotation")...)
+			problems = append(problems, c.reject(rule, rule.AlertingRule.Annotations, ann, "annotation")...)
 		}
 	}
 	return
 }
 
-func (c Reject) reject(rule parser.Rule, label *parser.YamlKeyValue, kind string) (problems []Problem) {
+func (c Reject) reject(rule parser.Rule, parent *parser.YamlMap, label *parser.YamlKeyValue, kind string) (problems []Problem) {
 	if c.keyRe != nil && c.keyRe.MustExpand(rule).MatchString(label.Key.Value) {
 		problems = append(problems, Problem{
 			Fragment: label.Key.Value,
@@ -67,6 +67,7 @@ func (c Reject) reject(rule parser.Rule, label *parser.YamlKeyValue, kind string
 			Reporter: c.Reporter(),
 			Text:     fmt.Sprintf("%s key %s is not allowed to match %q", kind, label.Key.Value, c.keyRe.anchored),
 			Severity: c.severity,
+			Fix:      removeKeyFix(parent, label),
 		})
 	}
 	if c.valueRe != nil && c.valueRe.MustExpand(rule).MatchString(label.Value.Value) {
@@ -80,3 +81,18 @@ func (c Reject) reject(rule parser.Rule, label *parser.YamlKeyValue, kind string
 	}
 	return
 }
+
+// removeKeyFix removes given key from a map, if it's the only key
+// then the map itself is removed too.
+// It only works for keys with values on the same line.
+func removeKeyFix(parent *parser.YamlMap, item *parser.YamlKeyValue) *Fix {
+	lines := item.Lines()
+	if len(lines) != 1 || lines[0] == parent.Key.Position.FirstLine() {
+		return nil
+	}
+	fix := Fix{Edits: []Edit{{Line: lines[0], Old: item.Key.Value, RemoveLine: true}}}
+	if len(parent.Items) == 1 {
+		fix.Edits = append(fix.Edits, Edit{Line: parent.Key.Position.FirstLine(), Old: parent.Key.Value, RemoveLine: true})
+	}
+	return &fix
+}
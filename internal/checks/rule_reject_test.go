@@ -88,6 +88,12 @@ func TestRejectCheck(t *testing.T) {
 						Reporter: "rule/reject",
 						Text:     `label key bad is not allowed to match "^bad$"`,
 						Severity: checks.Bug,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{
+								{Line: 4, Old: "bad", RemoveLine: true},
+								{Line: 3, Old: "labels", RemoveLine: true},
+							},
+						},
 					},
 				}
 			},
@@ -126,6 +132,53 @@ func TestRejectCheck(t *testing.T) {
 						Reporter: "rule/reject",
 						Text:     `label key bad is not allowed to match "^bad$"`,
 						Severity: checks.Bug,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{
+								{Line: 4, Old: "bad", RemoveLine: true},
+								{Line: 3, Old: "labels", RemoveLine: true},
+							},
+						},
+					},
+				}
+			},
+		},
+		{
+			description: "rejected key with other labels",
+			content:     "- record: foo\n  expr: sum(foo)\n  labels:\n    foo: bar\n    bad: bar\n",
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewRejectCheck(true, true, badRe, nil, checks.Bug)
+			},
+			prometheus: noProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: `bad`,
+						Lines:    []int{5},
+						Reporter: "rule/reject",
+						Text:     `label key bad is not allowed to match "^bad$"`,
+						Severity: checks.Bug,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{{Line: 5, Old: "bad", RemoveLine: true}},
+						},
+					},
+				}
+			},
+		},
+		{
+			description: "rejected key in flow style map",
+			content:     "- record: foo\n  expr: sum(foo)\n  labels: {foo: bar, bad: bar}\n",
+			checker: func(_ *promapi.FailoverGroup) checks.RuleChecker {
+				return checks.NewRejectCheck(true, true, badRe, nil, checks.Bug)
+			},
+			prometheus: noProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: `bad`,
+						Lines:    []int{3},
+						Reporter: "rule/reject",
+						Text:     `label key bad is not allowed to match "^bad$"`,
+						Severity: checks.Bug,
 					},
 				}
 			},
@@ -183,6 +236,12 @@ func TestRejectCheck(t *testing.T) {
 						Reporter: "rule/reject",
 						Text:     `annotation key bad is not allowed to match "^bad$"`,
 						Severity: checks.Information,
+						Fix: &checks.Fix{
+							Edits: []checks.Edit{
+								{Line: 4, Old: "bad", RemoveLine: true},
+								{Line: 3, Old: "annotations", RemoveLine: true},
+							},
+						},
 					},
 				}
 			},
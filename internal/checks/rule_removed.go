@@ -0,0 +1,67 @@
+package checks
+
+import (
+	"fmt"
+
+	"github.com/cloudflare/pint/internal/discovery"
+)
+
+const (
+	RemovedCheckName = "rule/removed"
+)
+
+// RemovedRuleReference is a rule that still uses metrics produced by
+// a recording rule that was removed or renamed.
+type RemovedRuleReference struct {
+	Entry   discovery.Entry
+	Problem Problem
+}
+
+// FindRemovedRuleReferences compares recording rules from base entries with
+// current entries and returns all current rules that query metrics that are
+// no longer produced by any recording rule.
+// Base entries are rules from the base branch version of modified files,
+// current entries should include all rules from the repository.
+func FindRemovedRuleReferences(base, current []discovery.Entry) (refs []RemovedRuleReference) {
+	recorded := map[string]struct{}{}
+	for _, entry := range current {
+		if isValidEntry(entry) && entry.Rule.RecordingRule != nil {
+			recorded[entry.Rule.RecordingRule.Record.Value.Value] = struct{}{}
+		}
+	}
+
+	removed := []discovery.Entry{}
+	for _, entry := range base {
+		if !isValidEntry(entry) || entry.Rule.RecordingRule == nil {
+			continue
+		}
+		if _, ok := recorded[entry.Rule.RecordingRule.Record.Value.Value]; ok {
+			continue
+		}
+		removed = append(removed, entry)
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	index := newRecordingRulesIndex(removed)
+	for _, entry := range current {
+		if !isValidEntry(entry) || entry.Rule.HasComment(fmt.Sprintf("disable %s", RemovedCheckName)) {
+			continue
+		}
+		for _, dep := range index.dependencies(entry.Rule) {
+			refs = append(refs, RemovedRuleReference{
+				Entry: entry,
+				Problem: Problem{
+					Fragment: dep.selector.String(),
+					Lines:    entry.Rule.Expr().Lines(),
+					Reporter: RemovedCheckName,
+					Text: fmt.Sprintf("%q is no longer produced by any recording rule, it was previously recorded at %s:%d, but it's still used here",
+						dep.entry.Rule.Name(), dep.entry.Path, ruleFirstLine(dep.entry.Rule)),
+					Severity: Bug,
+				},
+			})
+		}
+	}
+	return refs
+}
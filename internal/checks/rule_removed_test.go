@@ -0,0 +1,84 @@
+package checks_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func TestFindRemovedRuleReferences(t *testing.T) {
+	type testCaseT struct {
+		description string
+		base        string
+		current     string
+		problems    []checks.Problem
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "no rules removed",
+			base:        "- record: foo\n  expr: sum(bar)\n",
+			current:     "- record: foo\n  expr: sum(bar)\n- alert: foo\n  expr: foo == 0\n",
+		},
+		{
+			description: "removed rule not used",
+			base:        "- record: foo\n  expr: sum(bar)\n",
+			current:     "- alert: foo\n  expr: up == 0\n",
+		},
+		{
+			description: "alerting rule removed",
+			base:        "- alert: foo\n  expr: up == 0\n",
+			current:     "- alert: bar\n  expr: foo == 0\n",
+		},
+		{
+			description: "removed rule still used",
+			base:        "- record: foo\n  expr: sum(bar)\n",
+			current:     "- record: bar\n  expr: sum(up)\n- alert: foo\n  expr: sum(foo{job=\"a\"}) == 0\n",
+			problems: []checks.Problem{
+				{
+					Fragment: `foo{job="a"}`,
+					Lines:    []int{4},
+					Reporter: checks.RemovedCheckName,
+					Text:     `"foo" is no longer produced by any recording rule, it was previously recorded at fake.yml:1, but it's still used here`,
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "renamed rule still used",
+			base:        "- record: foo\n  expr: sum(bar)\n- record: foo:sum\n  expr: sum(foo)\n",
+			current:     "- record: bar\n  expr: sum(bar)\n- record: foo:sum\n  expr: sum(foo)\n",
+			problems: []checks.Problem{
+				{
+					Fragment: "foo",
+					Lines:    []int{4},
+					Reporter: checks.RemovedCheckName,
+					Text:     `"foo" is no longer produced by any recording rule, it was previously recorded at fake.yml:1, but it's still used here`,
+					Severity: checks.Bug,
+				},
+			},
+		},
+		{
+			description: "removed rule with mismatched static labels",
+			base:        "- record: foo\n  expr: sum(bar)\n  labels:\n    env: prod\n",
+			current:     "- alert: foo\n  expr: foo{env=\"dev\"} == 0\n",
+		},
+		{
+			description: "disabled by comment",
+			base:        "- record: foo\n  expr: sum(bar)\n",
+			current:     "# pint disable rule/removed\n- alert: foo\n  expr: foo == 0\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var problems []checks.Problem
+			for _, ref := range checks.FindRemovedRuleReferences(mustParseContent(tc.base), mustParseContent(tc.current)) {
+				problems = append(problems, ref.Problem)
+			}
+			require.Equal(t, tc.problems, problems)
+		})
+	}
+}
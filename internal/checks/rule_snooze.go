@@ -0,0 +1,72 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+const (
+	SnoozeCheckName = "rule/snooze"
+)
+
+func NewSnoozeCheck(maxDuration time.Duration) SnoozeCheck {
+	return SnoozeCheck{maxDuration: maxDuration}
+}
+
+type SnoozeCheck struct {
+	maxDuration time.Duration
+}
+
+func (c SnoozeCheck) String() string {
+	return SnoozeCheckName
+}
+
+func (c SnoozeCheck) Reporter() string {
+	return SnoozeCheckName
+}
+
+func (c SnoozeCheck) Check(ctx context.Context, rule parser.Rule, entries []discovery.Entry) (problems []Problem) {
+	now := time.Now()
+	for _, s := range rule.Snoozes {
+		switch {
+		case s.Err != nil:
+			problems = append(problems, Problem{
+				Fragment: s.Comment,
+				Lines:    []int{s.Line},
+				Reporter: c.Reporter(),
+				Text:     fmt.Sprintf("failed to parse pint comment: %s", s.Err),
+				Severity: Warning,
+			})
+		case !s.IsActive(now):
+			problems = append(problems, Problem{
+				Fragment: s.Comment,
+				Lines:    []int{s.Line},
+				Reporter: c.Reporter(),
+				Text:     fmt.Sprintf("%s was snoozed until %s%s, this comment has expired and should be removed", s.Match, s.Until.Format(time.RFC3339), snoozeReason(s)),
+				Severity: Warning,
+			})
+		case c.maxDuration > 0 && s.Until.Sub(now) > c.maxDuration:
+			problems = append(problems, Problem{
+				Fragment: s.Comment,
+				Lines:    []int{s.Line},
+				Reporter: c.Reporter(),
+				Text:     fmt.Sprintf("%s is snoozed until %s%s, which is more than %s from now", s.Match, s.Until.Format(time.RFC3339), snoozeReason(s), model.Duration(c.maxDuration)),
+				Severity: Information,
+			})
+		}
+	}
+	return problems
+}
+
+func snoozeReason(s parser.Snooze) string {
+	if s.Reason == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", s.Reason)
+}
@@ -0,0 +1,123 @@
+package checks_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func newSnoozeCheck(_ *promapi.FailoverGroup) checks.RuleChecker {
+	return checks.NewSnoozeCheck(0)
+}
+
+func newSnoozeCheckWithLimit(_ *promapi.FailoverGroup) checks.RuleChecker {
+	return checks.NewSnoozeCheck(time.Hour * 24 * 30)
+}
+
+func TestSnoozeCheck(t *testing.T) {
+	future := time.Now().AddDate(1, 0, 0).Format("2006-01-02")
+	soon := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+
+	testCases := []checkTest{
+		{
+			description: "no comments",
+			content:     "- record: foo\n  expr: sum(foo)\n",
+			checker:     newSnoozeCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "permanent disable comment",
+			content:     "# pint disable promql/series\n- record: foo\n  expr: sum(foo)\n",
+			checker:     newSnoozeCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "active snooze",
+			content:     fmt.Sprintf("# pint disable promql/series until %s\n- record: foo\n  expr: sum(foo)\n", future),
+			checker:     newSnoozeCheck,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "active snooze within limit",
+			content:     fmt.Sprintf("# pint disable promql/series until %s\n- record: foo\n  expr: sum(foo)\n", soon),
+			checker:     newSnoozeCheckWithLimit,
+			prometheus:  noProm,
+			problems:    noProblems,
+		},
+		{
+			description: "expired snooze",
+			content:     "# pint disable promql/series until 2020-01-01\n- record: foo\n  expr: sum(foo)\n",
+			checker:     newSnoozeCheck,
+			prometheus:  noProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "disable promql/series until 2020-01-01",
+						Lines:    []int{1},
+						Reporter: checks.SnoozeCheckName,
+						Text:     "promql/series was snoozed until 2020-01-01T00:00:00Z, this comment has expired and should be removed",
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "expired snooze with reason",
+			content:     "- record: foo\n  # pint disable promql/series(foo) until 2020-01-01T12:00:00Z INC-123 outage\n  expr: sum(foo)\n",
+			checker:     newSnoozeCheck,
+			prometheus:  noProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "disable promql/series(foo) until 2020-01-01T12:00:00Z INC-123 outage",
+						Lines:    []int{2},
+						Reporter: checks.SnoozeCheckName,
+						Text:     "promql/series(foo) was snoozed until 2020-01-01T12:00:00Z (INC-123 outage), this comment has expired and should be removed",
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "invalid date",
+			content:     "# pint disable promql/series until tomorrow\n- record: foo\n  expr: sum(foo)\n",
+			checker:     newSnoozeCheck,
+			prometheus:  noProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: "disable promql/series until tomorrow",
+						Lines:    []int{1},
+						Reporter: checks.SnoozeCheckName,
+						Text:     `failed to parse pint comment: invalid snooze date "tomorrow", expected YYYY-MM-DD or RFC3339 timestamp`,
+						Severity: checks.Warning,
+					},
+				}
+			},
+		},
+		{
+			description: "snooze over the limit",
+			content:     fmt.Sprintf("# pint disable promql/series until %s\n- record: foo\n  expr: sum(foo)\n", future),
+			checker:     newSnoozeCheckWithLimit,
+			prometheus:  noProm,
+			problems: func(uri string) []checks.Problem {
+				return []checks.Problem{
+					{
+						Fragment: fmt.Sprintf("disable promql/series until %s", future),
+						Lines:    []int{1},
+						Reporter: checks.SnoozeCheckName,
+						Text:     fmt.Sprintf("promql/series is snoozed until %sT00:00:00Z, which is more than 30d from now", future),
+						Severity: checks.Information,
+					},
+				}
+			},
+		},
+	}
+	runTests(t, testCases)
+}
@@ -0,0 +1,16 @@
+package config
+
+import (
+	"fmt"
+)
+
+type Alertmanager struct {
+	Config string `hcl:"config" json:"config"`
+}
+
+func (am Alertmanager) validate() error {
+	if am.Config == "" {
+		return fmt.Errorf("config cannot be empty")
+	}
+	return nil
+}
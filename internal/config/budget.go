@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/alecthomas/units"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+type Budget struct {
+	BytesPerSample int           `hcl:"bytesPerSample,optional" json:"bytesPerSample,omitempty"`
+	Severity       string        `hcl:"severity,optional" json:"severity,omitempty"`
+	Owners         []BudgetLimit `hcl:"owner,block" json:"owner,omitempty"`
+	Files          []BudgetLimit `hcl:"file,block" json:"file,omitempty"`
+}
+
+func (b Budget) validate() error {
+	if b.Severity != "" {
+		if _, err := checks.ParseSeverity(b.Severity); err != nil {
+			return err
+		}
+	}
+	if b.BytesPerSample < 0 {
+		return fmt.Errorf("bytesPerSample value must be >= 0")
+	}
+	for _, limits := range [][]BudgetLimit{b.Owners, b.Files} {
+		for _, bl := range limits {
+			if err := bl.validate(b.BytesPerSample); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b Budget) costBudgets() (budgets []checks.CostBudget) {
+	severity := checks.Bug
+	if b.Severity != "" {
+		severity, _ = checks.ParseSeverity(b.Severity)
+	}
+	for _, bl := range b.Owners {
+		cb := bl.costBudget(b.BytesPerSample, severity)
+		cb.Owner = strictRegex(bl.Pattern)
+		budgets = append(budgets, cb)
+	}
+	for _, bl := range b.Files {
+		cb := bl.costBudget(b.BytesPerSample, severity)
+		cb.Path = strictRegex(bl.Pattern)
+		budgets = append(budgets, cb)
+	}
+	return budgets
+}
+
+type BudgetLimit struct {
+	Pattern   string `hcl:",label" json:"pattern"`
+	MaxSeries int    `hcl:"maxSeries,optional" json:"maxSeries,omitempty"`
+	MaxMemory string `hcl:"maxMemory,optional" json:"maxMemory,omitempty"`
+}
+
+func (bl BudgetLimit) validate(bytesPerSample int) error {
+	if _, err := regexp.Compile("^" + bl.Pattern + "$"); err != nil {
+		return err
+	}
+	if bl.MaxSeries < 0 {
+		return fmt.Errorf("maxSeries value must be >= 0")
+	}
+	if bl.MaxMemory != "" {
+		if _, err := units.ParseBase2Bytes(bl.MaxMemory); err != nil {
+			return fmt.Errorf("invalid budget maxMemory: %w", err)
+		}
+		if bytesPerSample == 0 {
+			return fmt.Errorf("budget for %q sets maxMemory but bytesPerSample is not set", bl.Pattern)
+		}
+	}
+	if bl.MaxSeries == 0 && bl.MaxMemory == "" {
+		return fmt.Errorf("budget for %q must set maxSeries and/or maxMemory", bl.Pattern)
+	}
+	return nil
+}
+
+func (bl BudgetLimit) costBudget(bytesPerSample int, severity checks.Severity) checks.CostBudget {
+	maxBytes, _ := units.ParseBase2Bytes(bl.MaxMemory)
+	return checks.CostBudget{
+		BytesPerSample: bytesPerSample,
+		MaxSeries:      bl.MaxSeries,
+		MaxBytes:       int(maxBytes),
+		Severity:       severity,
+	}
+}
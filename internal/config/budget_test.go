@@ -0,0 +1,95 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func TestBudgetSettings(t *testing.T) {
+	type testCaseT struct {
+		conf Budget
+		err  error
+	}
+
+	testCases := []testCaseT{
+		{
+			conf: Budget{},
+		},
+		{
+			conf: Budget{
+				BytesPerSample: 4096,
+				Severity:       "warning",
+				Owners:         []BudgetLimit{{Pattern: "team-.+", MaxSeries: 1000, MaxMemory: "1GiB"}},
+				Files:          []BudgetLimit{{Pattern: "rules/.+", MaxSeries: 500}},
+			},
+		},
+		{
+			conf: Budget{Severity: "foo"},
+			err:  errors.New("unknown severity: foo"),
+		},
+		{
+			conf: Budget{BytesPerSample: -1},
+			err:  errors.New("bytesPerSample value must be >= 0"),
+		},
+		{
+			conf: Budget{Owners: []BudgetLimit{{Pattern: "team-(.+", MaxSeries: 1}}},
+			err:  errors.New("error parsing regexp: missing closing ): `^team-(.+$`"),
+		},
+		{
+			conf: Budget{Owners: []BudgetLimit{{Pattern: "team-a", MaxSeries: -1}}},
+			err:  errors.New("maxSeries value must be >= 0"),
+		},
+		{
+			conf: Budget{Files: []BudgetLimit{{Pattern: "rules/.+"}}},
+			err:  errors.New(`budget for "rules/.+" must set maxSeries and/or maxMemory`),
+		},
+		{
+			conf: Budget{BytesPerSample: 1024, Files: []BudgetLimit{{Pattern: "rules/.+", MaxMemory: "1giga"}}},
+			err:  errors.New("invalid budget maxMemory: units: unknown unit giga in 1giga"),
+		},
+		{
+			conf: Budget{Files: []BudgetLimit{{Pattern: "rules/.+", MaxMemory: "1GiB"}}},
+			err:  errors.New(`budget for "rules/.+" sets maxMemory but bytesPerSample is not set`),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.conf), func(t *testing.T) {
+			assert := assert.New(t)
+			err := tc.conf.validate()
+			if err == nil || tc.err == nil {
+				assert.Equal(err, tc.err)
+			} else {
+				assert.EqualError(err, tc.err.Error())
+			}
+		})
+	}
+}
+
+func TestBudgetCostBudgets(t *testing.T) {
+	b := Budget{
+		BytesPerSample: 1024,
+		Owners:         []BudgetLimit{{Pattern: "team-.+", MaxMemory: "1MiB"}},
+		Files:          []BudgetLimit{{Pattern: "rules/.+", MaxSeries: 500}},
+	}
+	assert.Equal(t, []checks.CostBudget{
+		{
+			Owner:          regexp.MustCompile("^team-.+$"),
+			BytesPerSample: 1024,
+			MaxBytes:       1024 * 1024,
+			Severity:       checks.Bug,
+		},
+		{
+			Path:           regexp.MustCompile("^rules/.+$"),
+			BytesPerSample: 1024,
+			MaxSeries:      500,
+			Severity:       checks.Bug,
+		},
+	}, b.costBudgets())
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alecthomas/units"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+type Cache struct {
+	Dir         string `hcl:"dir" json:"dir"`
+	MaxSize     string `hcl:"maxSize,optional" json:"maxSize,omitempty"`
+	QueryTTL    string `hcl:"queryTTL,optional" json:"queryTTL,omitempty"`
+	RangeTTL    string `hcl:"rangeTTL,optional" json:"rangeTTL,omitempty"`
+	MetadataTTL string `hcl:"metadataTTL,optional" json:"metadataTTL,omitempty"`
+	ConfigTTL   string `hcl:"configTTL,optional" json:"configTTL,omitempty"`
+}
+
+func (c *Cache) setDefaults() {
+	if c.MaxSize == "" {
+		c.MaxSize = "1GiB"
+	}
+	if c.QueryTTL == "" {
+		c.QueryTTL = "5m"
+	}
+	if c.RangeTTL == "" {
+		c.RangeTTL = "1h"
+	}
+	if c.MetadataTTL == "" {
+		c.MetadataTTL = "1h"
+	}
+	if c.ConfigTTL == "" {
+		c.ConfigTTL = "1h"
+	}
+}
+
+func (c Cache) validate() error {
+	if c.Dir == "" {
+		return errors.New("cache dir cannot be empty")
+	}
+	if c.MaxSize != "" {
+		if _, err := units.ParseBase2Bytes(c.MaxSize); err != nil {
+			return fmt.Errorf("invalid cache maxSize: %w", err)
+		}
+	}
+	for _, ttl := range []string{c.QueryTTL, c.RangeTTL, c.MetadataTTL, c.ConfigTTL} {
+		if ttl == "" {
+			continue
+		}
+		if _, err := parseDuration(ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c Cache) diskCache() *promapi.DiskCache {
+	maxSize, _ := units.ParseBase2Bytes(c.MaxSize)
+	ttls := map[string]time.Duration{}
+	for endpoint, ttl := range map[string]string{
+		"/api/v1/query":         c.QueryTTL,
+		"/api/v1/query/range":   c.RangeTTL,
+		"/api/v1/metadata":      c.MetadataTTL,
+		"/api/v1/status/config": c.ConfigTTL,
+	} {
+		ttls[endpoint], _ = parseDuration(ttl)
+	}
+	return promapi.NewDiskCache(c.Dir, int64(maxSize), ttls)
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheSettings(t *testing.T) {
+	type testCaseT struct {
+		conf Cache
+		err  error
+	}
+
+	testCases := []testCaseT{
+		{
+			conf: Cache{Dir: ".pint-cache"},
+		},
+		{
+			conf: Cache{
+				Dir:         ".pint-cache",
+				MaxSize:     "256MiB",
+				QueryTTL:    "1m",
+				RangeTTL:    "2h",
+				MetadataTTL: "1d",
+				ConfigTTL:   "1d",
+			},
+		},
+		{
+			conf: Cache{},
+			err:  errors.New("cache dir cannot be empty"),
+		},
+		{
+			conf: Cache{Dir: ".pint-cache", MaxSize: "1giga"},
+			err:  errors.New(`invalid cache maxSize: units: unknown unit giga in 1giga`),
+		},
+		{
+			conf: Cache{Dir: ".pint-cache", RangeTTL: "1x"},
+			err:  errors.New(`not a valid duration string: "1x"`),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%v", tc.conf), func(t *testing.T) {
+			assert := assert.New(t)
+			err := tc.conf.validate()
+			if err == nil || tc.err == nil {
+				assert.Equal(err, tc.err)
+			} else {
+				assert.EqualError(err, tc.err.Error())
+			}
+		})
+	}
+}
+
+func TestCacheDefaults(t *testing.T) {
+	c := Cache{Dir: ".pint-cache", QueryTTL: "1m"}
+	c.setDefaults()
+	assert.Equal(t, Cache{
+		Dir:         ".pint-cache",
+		MaxSize:     "1GiB",
+		QueryTTL:    "1m",
+		RangeTTL:    "1h",
+		MetadataTTL: "1h",
+		ConfigTTL:   "1h",
+	}, c)
+}
@@ -7,8 +7,9 @@ import (
 )
 
 type Checks struct {
-	Enabled  []string `hcl:"enabled,optional" json:"enabled,omitempty"`
-	Disabled []string `hcl:"disabled,optional" json:"disabled,omitempty"`
+	Enabled   []string `hcl:"enabled,optional" json:"enabled,omitempty"`
+	Disabled  []string `hcl:"disabled,optional" json:"disabled,omitempty"`
+	MaxSnooze string   `hcl:"maxSnooze,optional" json:"maxSnooze,omitempty"`
 }
 
 func (c Checks) validate() error {
@@ -22,6 +23,11 @@ func (c Checks) validate() error {
 			return err
 		}
 	}
+	if c.MaxSnooze != "" {
+		if _, err := parseDuration(c.MaxSnooze); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -30,6 +30,17 @@ func TestChecksSettings(t *testing.T) {
 			},
 			err: errors.New("unknown check name foo"),
 		},
+		{
+			conf: Checks{
+				MaxSnooze: "30d",
+			},
+		},
+		{
+			conf: Checks{
+				MaxSnooze: "1 month",
+			},
+			err: errors.New(`not a valid duration string: "1 month"`),
+		},
 		{
 			conf: Checks{
 				Enabled:  []string{"promql/syntax"},
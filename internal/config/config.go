@@ -2,13 +2,15 @@ package config
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"os"
 	"time"
 
+	"github.com/cloudflare/pint/internal/alertmanager"
 	"github.com/cloudflare/pint/internal/checks"
-	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/discovery"
 	"github.com/cloudflare/pint/internal/promapi"
 
 	"github.com/hashicorp/hcl/v2/hclsimple"
@@ -17,13 +19,17 @@ import (
 )
 
 type Config struct {
-	CI                *CI                `hcl:"ci,block" json:"ci,omitempty"`
-	Parser            *Parser            `hcl:"parser,block" json:"parser,omitempty"`
-	Repository        *Repository        `hcl:"repository,block" json:"repository,omitempty"`
-	Prometheus        []PrometheusConfig `hcl:"prometheus,block" json:"prometheus,omitempty"`
-	Checks            *Checks            `hcl:"checks,block" json:"checks,omitempty"`
-	Rules             []Rule             `hcl:"rule,block" json:"rules,omitempty"`
-	PrometheusServers []*promapi.FailoverGroup
+	CI                 *CI                `hcl:"ci,block" json:"ci,omitempty"`
+	Parser             *Parser            `hcl:"parser,block" json:"parser,omitempty"`
+	Repository         *Repository        `hcl:"repository,block" json:"repository,omitempty"`
+	Prometheus         []PrometheusConfig `hcl:"prometheus,block" json:"prometheus,omitempty"`
+	Cache              *Cache             `hcl:"cache,block" json:"cache,omitempty"`
+	Alertmanager       *Alertmanager      `hcl:"alertmanager,block" json:"alertmanager,omitempty"`
+	Budget             *Budget            `hcl:"budget,block" json:"budget,omitempty"`
+	Checks             *Checks            `hcl:"checks,block" json:"checks,omitempty"`
+	Rules              []Rule             `hcl:"rule,block" json:"rules,omitempty"`
+	PrometheusServers  []*promapi.FailoverGroup
+	AlertmanagerConfig *alertmanager.Config `json:"-"`
 }
 
 func (cfg *Config) DisableOnlineChecks() {
@@ -64,14 +70,23 @@ func (cfg *Config) SetDisabledChecks(l []string) {
 	}
 }
 
+// SetCassette makes all Prometheus servers record or replay responses.
+func (cfg *Config) SetCassette(c *promapi.Cassette) {
+	for _, prom := range cfg.PrometheusServers {
+		prom.SetCassette(c)
+	}
+}
+
 func (cfg Config) String() string {
 	content, _ := json.MarshalIndent(cfg, "", "  ")
 	return string(content)
 }
 
-func (cfg *Config) GetChecksForRule(ctx context.Context, path string, r parser.Rule) []checks.RuleChecker {
+func (cfg *Config) GetChecksForRule(ctx context.Context, entry discovery.Entry) []checks.RuleChecker {
 	enabled := []checks.RuleChecker{}
 
+	proms := cfg.GetPrometheusServersForEntry(entry)
+
 	allChecks := []checkMeta{
 		{
 			name:  checks.SyntaxCheckName,
@@ -97,19 +112,30 @@ func (cfg *Config) GetChecksForRule(ctx context.Context, path string, r parser.R
 			name:  checks.RegexpCheckName,
 			check: checks.NewRegexpCheck(),
 		},
+		{
+			name:  checks.DuplicateCheckName,
+			check: checks.NewDuplicateCheck(proms, cfg.GetPrometheusServersForEntry),
+		},
+		{
+			name:  checks.DependencyCheckName,
+			check: checks.NewDependencyCheck(),
+		},
 	}
 
-	proms := []*promapi.FailoverGroup{}
-	for _, prom := range cfg.Prometheus {
-		if !prom.isEnabledForPath(path) {
-			continue
-		}
-		for _, p := range cfg.PrometheusServers {
-			if p.Name() == prom.Name {
-				proms = append(proms, p)
-				break
-			}
-		}
+	var maxSnooze time.Duration
+	if cfg.Checks.MaxSnooze != "" {
+		maxSnooze, _ = parseDuration(cfg.Checks.MaxSnooze)
+	}
+	allChecks = append(allChecks, checkMeta{
+		name:  checks.SnoozeCheckName,
+		check: checks.NewSnoozeCheck(maxSnooze),
+	})
+
+	if cfg.AlertmanagerConfig != nil {
+		allChecks = append(allChecks, checkMeta{
+			name:  checks.RoutingCheckName,
+			check: checks.NewRoutingCheck(cfg.AlertmanagerConfig),
+		})
 	}
 
 	for _, p := range proms {
@@ -128,12 +154,12 @@ func (cfg *Config) GetChecksForRule(ctx context.Context, path string, r parser.R
 	}
 
 	for _, rule := range cfg.Rules {
-		allChecks = append(allChecks, rule.resolveChecks(ctx, path, r, cfg.Checks.Enabled, cfg.Checks.Disabled, proms)...)
+		allChecks = append(allChecks, rule.resolveChecks(ctx, entry.Path, entry.Rule, cfg.Checks.Enabled, cfg.Checks.Disabled, proms)...)
 	}
 
 	for _, cm := range allChecks {
 		// check if rule was disabled
-		if !isEnabled(cfg.Checks.Enabled, cfg.Checks.Disabled, r, cm.name, cm.check) {
+		if !isEnabled(cfg.Checks.Enabled, cfg.Checks.Disabled, entry.Rule, cm.name, cm.check) {
 			continue
 		}
 		// check if rule was already enabled
@@ -154,16 +180,68 @@ func (cfg *Config) GetChecksForRule(ctx context.Context, path string, r parser.R
 		el = append(el, fmt.Sprintf("%v", e))
 	}
 	name := "unknown"
-	if r.AlertingRule != nil {
-		name = r.AlertingRule.Alert.Value.Value
-	} else if r.RecordingRule != nil {
-		name = r.RecordingRule.Record.Value.Value
+	if entry.Rule.AlertingRule != nil {
+		name = entry.Rule.AlertingRule.Alert.Value.Value
+	} else if entry.Rule.RecordingRule != nil {
+		name = entry.Rule.RecordingRule.Record.Value.Value
 	}
-	log.Debug().Strs("enabled", el).Str("path", path).Str("rule", name).Msg("Configured checks for rule")
+	log.Debug().Strs("enabled", el).Str("path", entry.Path).Str("rule", name).Msg("Configured checks for rule")
 
 	return enabled
 }
 
+// GetPrometheusServersForEntry returns all Prometheus servers enabled
+// for the file given rule was defined in.
+func (cfg *Config) GetPrometheusServersForEntry(entry discovery.Entry) []*promapi.FailoverGroup {
+	proms := []*promapi.FailoverGroup{}
+	for _, prom := range cfg.Prometheus {
+		if !prom.isEnabledForPath(entry.Path) {
+			continue
+		}
+		for _, p := range cfg.PrometheusServers {
+			if p.Name() == prom.Name {
+				if prom.Tenant != nil {
+					if tenant, ok := prom.Tenant.forFile(entry.Path, entry.Tenant); ok {
+						p = p.WithTenant(promapi.Tenant{Header: prom.Tenant.header(), Value: tenant})
+					}
+				}
+				proms = append(proms, p)
+				break
+			}
+		}
+	}
+	return proms
+}
+
+// CostBudgets returns all cost budgets configured in the budget block.
+// Budgets are only checked if query/cost check is enabled.
+func (cfg Config) CostBudgets() []checks.CostBudget {
+	if cfg.Budget == nil || !cfg.IsCheckEnabled(checks.CostCheckName) {
+		return nil
+	}
+	return cfg.Budget.costBudgets()
+}
+
+// IsCheckEnabled returns true if given check wasn't disabled in the checks
+// block or via command line flags. It's used for checks that are not run
+// for individual rules, so per rule comments are not taken into account.
+func (cfg Config) IsCheckEnabled(name string) bool {
+	for _, n := range cfg.Checks.Disabled {
+		if n == name {
+			return false
+		}
+	}
+	if len(cfg.Checks.Enabled) == 0 {
+		return true
+	}
+	for _, n := range cfg.Checks.Enabled {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
 func Load(path string, failOnMissing bool) (cfg Config, err error) {
 	cfg = Config{
 		CI: &CI{
@@ -210,29 +288,92 @@ func Load(path string, failOnMissing bool) (cfg Config, err error) {
 		}
 	}
 
+	if cfg.Repository != nil && cfg.Repository.GitLab != nil {
+		if err = cfg.Repository.GitLab.validate(); err != nil {
+			return cfg, err
+		}
+	}
+
 	if cfg.Checks != nil {
 		if err = cfg.Checks.validate(); err != nil {
 			return cfg, err
 		}
 	}
 
+	var diskCache *promapi.DiskCache
+	if cfg.Cache != nil {
+		cfg.Cache.setDefaults()
+		if err = cfg.Cache.validate(); err != nil {
+			return cfg, err
+		}
+		diskCache = cfg.Cache.diskCache()
+	}
+
 	for i, prom := range cfg.Prometheus {
 		if err = prom.validate(); err != nil {
 			return cfg, err
 		}
 		timeout, _ := parseDuration(prom.Timeout)
+		var headers map[string]string
+		if headers, err = prom.requestHeaders(); err != nil {
+			return cfg, fmt.Errorf("prometheus %q: %w", prom.Name, err)
+		}
+		var tlsConf *tls.Config
+		if prom.TLS != nil {
+			if tlsConf, err = prom.TLS.toHTTPConfig(); err != nil {
+				return cfg, fmt.Errorf("prometheus %q: %w", prom.Name, err)
+			}
+		}
 		concurrency := prom.Concurrency
 		if concurrency <= 0 {
 			concurrency = 16
 			cfg.Prometheus[i].Concurrency = concurrency
 		}
-		upstreams := []*promapi.Prometheus{
-			promapi.NewPrometheus(prom.Name, prom.URI, timeout, concurrency),
+		var upstreams []*promapi.Prometheus
+		if prom.TSDB != "" {
+			var db *promapi.Prometheus
+			if db, err = promapi.NewTSDB(prom.Name, prom.TSDB, timeout, concurrency); err != nil {
+				return cfg, fmt.Errorf("prometheus %q: %w", prom.Name, err)
+			}
+			upstreams = append(upstreams, db)
+		} else {
+			upstreams = append(upstreams, promapi.NewPrometheus(prom.Name, prom.URI, headers, timeout, concurrency, tlsConf))
 		}
 		for _, uri := range prom.Failover {
-			upstreams = append(upstreams, promapi.NewPrometheus(prom.Name, uri, timeout, concurrency))
+			upstreams = append(upstreams, promapi.NewPrometheus(prom.Name, uri, headers, timeout, concurrency, tlsConf))
+		}
+		group := promapi.NewFailoverGroup(prom.Name, upstreams, prom.Required)
+		if diskCache != nil {
+			group.SetDiskCache(diskCache)
+		}
+		if prom.Retry != nil {
+			prom.Retry.setDefaults()
+			group.SetRetry(prom.Retry.toRetryConfig())
+		}
+		if prom.CircuitBreaker != nil {
+			prom.CircuitBreaker.setDefaults()
+			// Setting failures to 0 disables the circuit breaker.
+			if failures := *prom.CircuitBreaker.Failures; failures > 0 {
+				cooldown, _ := parseDuration(prom.CircuitBreaker.Cooldown)
+				group.SetCircuitBreaker(failures, cooldown)
+			}
+		}
+		cfg.PrometheusServers = append(cfg.PrometheusServers, group)
+	}
+
+	if cfg.Alertmanager != nil {
+		if err = cfg.Alertmanager.validate(); err != nil {
+			return cfg, err
+		}
+		if cfg.AlertmanagerConfig, err = alertmanager.Load(cfg.Alertmanager.Config); err != nil {
+			return cfg, err
+		}
+	}
+
+	if cfg.Budget != nil {
+		if err = cfg.Budget.validate(); err != nil {
+			return cfg, err
 		}
-		cfg.PrometheusServers = append(cfg.PrometheusServers, promapi.NewFailoverGroup(prom.Name, upstreams, prom.Required))
 	}
 
 	for _, rule := range cfg.Rules {
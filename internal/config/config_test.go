@@ -13,6 +13,7 @@ import (
 
 	"github.com/cloudflare/pint/internal/checks"
 	"github.com/cloudflare/pint/internal/config"
+	"github.com/cloudflare/pint/internal/discovery"
 	"github.com/cloudflare/pint/internal/parser"
 )
 
@@ -59,6 +60,21 @@ prometheus "prom" {
 	}
 }
 
+func TestLoadPrometheusWithMissingTSDB(t *testing.T) {
+	dir := t.TempDir()
+	path := path.Join(dir, "config.hcl")
+	err := ioutil.WriteFile(path, []byte(fmt.Sprintf(`
+prometheus "prom" {
+  tsdb    = "%s/tsdb"
+  timeout = "1s"
+}
+`, dir)), 0o644)
+	assert.NoError(t, err)
+
+	_, err = config.Load(path, true)
+	assert.EqualError(t, err, fmt.Sprintf(`prometheus "prom": failed to open TSDB: open %s/tsdb: no such file or directory`, dir))
+}
+
 func TestDisableOnlineChecksWithoutPrometheus(t *testing.T) {
 	assert := assert.New(t)
 
@@ -124,6 +140,28 @@ func TestSetDisabledChecks(t *testing.T) {
 	assert.Equal([]string{checks.SyntaxCheckName, checks.RateCheckName}, cfg.Checks.Disabled)
 }
 
+func TestIsCheckEnabled(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	path := path.Join(dir, "config.hcl")
+	err := ioutil.WriteFile(path, []byte(``), 0o644)
+	assert.NoError(err)
+
+	cfg, err := config.Load(path, true)
+	assert.NoError(err)
+	assert.True(cfg.IsCheckEnabled(checks.RemovedCheckName))
+
+	cfg.SetDisabledChecks([]string{checks.RemovedCheckName})
+	assert.False(cfg.IsCheckEnabled(checks.RemovedCheckName))
+	assert.True(cfg.IsCheckEnabled(checks.SyntaxCheckName))
+
+	cfg.Checks.Disabled = nil
+	cfg.Checks.Enabled = []string{checks.SyntaxCheckName}
+	assert.False(cfg.IsCheckEnabled(checks.RemovedCheckName))
+	assert.True(cfg.IsCheckEnabled(checks.SyntaxCheckName))
+}
+
 func newRule(t *testing.T, content string) parser.Rule {
 	p := parser.NewParser()
 	rules, err := p.Parse([]byte(content))
@@ -156,6 +194,7 @@ func TestGetChecksForRule(t *testing.T) {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
 			},
 		},
 		{
@@ -174,7 +213,8 @@ prometheus "prom" {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.RateCheckName + "(prom)",
+				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName, checks.RateCheckName + "(prom)",
 				checks.SeriesCheckName + "(prom)",
 				checks.VectorMatchingCheckName + "(prom)",
 			},
@@ -196,7 +236,8 @@ prometheus "prom" {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.RateCheckName + "(prom)",
+				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName, checks.RateCheckName + "(prom)",
 				checks.SeriesCheckName + "(prom)",
 				checks.VectorMatchingCheckName + "(prom)",
 			},
@@ -230,6 +271,7 @@ checks {
 				checks.ComparisonCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
 			},
 		},
 		{
@@ -250,6 +292,7 @@ prometheus "prom" {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
 			},
 		},
 		{
@@ -269,7 +312,8 @@ prometheus "prom" {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.RateCheckName + "(prom)",
+				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName, checks.RateCheckName + "(prom)",
 				checks.SeriesCheckName + "(prom)",
 				checks.VectorMatchingCheckName + "(prom)",
 			},
@@ -296,7 +340,8 @@ prometheus "ignore" {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.RateCheckName + "(prom)",
+				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName, checks.RateCheckName + "(prom)",
 				checks.SeriesCheckName + "(prom)",
 				checks.VectorMatchingCheckName + "(prom)",
 			},
@@ -313,6 +358,7 @@ prometheus "ignore" {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
 			},
 		},
 		{
@@ -336,7 +382,8 @@ rule {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.AggregationCheckName + "(job:true)",
+				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName, checks.AggregationCheckName + "(job:true)",
 				checks.AggregationCheckName + "(instance:false)",
 				checks.AggregationCheckName + "(rack:false)",
 			},
@@ -366,7 +413,8 @@ rule {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.AggregationCheckName + "(job:true)",
+				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName, checks.AggregationCheckName + "(job:true)",
 				checks.AggregationCheckName + "(rack:false)",
 			},
 		},
@@ -390,6 +438,7 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
 			},
 		},
 		{
@@ -426,12 +475,40 @@ prometheus "prom2" {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.RateCheckName + "(prom1)",
+				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName, checks.RateCheckName + "(prom1)",
 				checks.SeriesCheckName + "(prom2)",
 				checks.VectorMatchingCheckName + "(prom2)",
 				checks.CostCheckName + "(prom1)",
 			},
 		},
+		{
+			title: "prometheus check with prometheus servers and snooze comments",
+			config: `
+prometheus "prom1" {
+  uri     = "http://localhost"
+  timeout = "1s"
+  paths   = [ "rules.yml" ]
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, `
+# pint disable promql/series(prom1) until 2099-01-01
+# pint disable promql/rate until 2020-01-01
+- record: foo
+  expr: sum(foo)
+`),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName, checks.RateCheckName + "(prom1)",
+				checks.VectorMatchingCheckName + "(prom1)",
+			},
+		},
 		{
 			title: "duplicated rules",
 			config: `
@@ -473,7 +550,8 @@ rule {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.LabelCheckName + "(team:true)",
+				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName, checks.LabelCheckName + "(team:true)",
 				checks.AnnotationCheckName + "(summary:true)",
 				checks.LabelCheckName + "(team:false)",
 				checks.AnnotationCheckName + "(summary=~^foo.+$:true)",
@@ -529,6 +607,7 @@ rule {
 				checks.AlertForCheckName,
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
 				checks.CostCheckName + "(prom1)",
 				checks.CostCheckName + "(prom2)",
 				checks.CostCheckName + "(prom1:10000)",
@@ -563,7 +642,8 @@ rule {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.RejectCheckName + "(key=~'^http://.+$')",
+				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName, checks.RejectCheckName + "(key=~'^http://.+$')",
 				checks.RejectCheckName + "(val=~'^http://.+$')",
 				checks.RejectCheckName + "(key=~'^.* +.*$')",
 				checks.RejectCheckName + "(val=~'^$')",
@@ -595,6 +675,7 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
 			},
 		},
 		{
@@ -623,6 +704,7 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
 			},
 		},
 		{
@@ -651,6 +733,7 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
 			},
 		},
 		{
@@ -678,7 +761,8 @@ rule {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.LabelCheckName + "(priority:true)",
+				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName, checks.LabelCheckName + "(priority:true)",
 			},
 		},
 		{
@@ -707,6 +791,7 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
 			},
 		},
 		{
@@ -735,6 +820,7 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
 			},
 		},
 		{
@@ -762,7 +848,8 @@ rule {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.LabelCheckName + "(priority:true)",
+				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName, checks.LabelCheckName + "(priority:true)",
 			},
 		},
 		{
@@ -799,7 +886,8 @@ prometheus "prom1" {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.AlertsCheckName + "(prom1)",
+				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName, checks.AlertsCheckName + "(prom1)",
 			},
 		},
 		{
@@ -832,7 +920,8 @@ prometheus "prom1" {
 				checks.ComparisonCheckName,
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
-				checks.RegexpCheckName, checks.RateCheckName + "(prom1)",
+				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName, checks.RateCheckName + "(prom1)",
 				checks.SeriesCheckName + "(prom1)",
 				checks.VectorMatchingCheckName + "(prom1)",
 				checks.AlertsCheckName + "(prom1)",
@@ -960,6 +1049,7 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
 				checks.AnnotationCheckName + "(summary:true)",
 			},
 		},
@@ -984,6 +1074,7 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
 			},
 		},
 		{
@@ -1007,6 +1098,7 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
 			},
 		},
 		{
@@ -1030,6 +1122,7 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
 				checks.AnnotationCheckName + "(summary:true)",
 			},
 		},
@@ -1054,6 +1147,7 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
 			},
 		},
 		{
@@ -1077,9 +1171,83 @@ rule {
 				checks.TemplateCheckName,
 				checks.FragileCheckName,
 				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
 				checks.AnnotationCheckName + "(summary:true)",
 			},
 		},
+		{
+			title: "group match / passing",
+			config: `
+rule {
+  match {
+    group = "team-.+"
+  }
+  annotation "summary" {
+    required = true
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "groups:\n- name: team-foo\n  rules:\n  - alert: foo\n    expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
+				checks.AnnotationCheckName + "(summary:true)",
+			},
+		},
+		{
+			title: "group match / not passing",
+			config: `
+rule {
+  match {
+    group = "team-.+"
+  }
+  annotation "summary" {
+    required = true
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "groups:\n- name: other\n  rules:\n  - alert: foo\n    expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
+			},
+		},
+		{
+			title: "group match / no group",
+			config: `
+rule {
+  match {
+    group = ".*"
+  }
+  annotation "summary" {
+    required = true
+  }
+}
+`,
+			path: "rules.yml",
+			rule: newRule(t, "- alert: foo\n  expr: sum(foo)\n"),
+			checks: []string{
+				checks.SyntaxCheckName,
+				checks.AlertForCheckName,
+				checks.ComparisonCheckName,
+				checks.TemplateCheckName,
+				checks.FragileCheckName,
+				checks.RegexpCheckName,
+				checks.DuplicateCheckName, checks.DependencyCheckName, checks.SnoozeCheckName,
+			},
+		},
 	}
 
 	dir := t.TempDir()
@@ -1097,7 +1265,7 @@ rule {
 			cfg, err := config.Load(path, false)
 			assert.NoError(err)
 
-			checks := cfg.GetChecksForRule(ctx, tc.path, tc.rule)
+			checks := cfg.GetChecksForRule(ctx, discovery.Entry{Path: tc.path, Rule: tc.rule})
 			checkNames := make([]string, 0, len(checks))
 			for _, c := range checks {
 				checkNames = append(checkNames, c.String())
@@ -1108,6 +1276,74 @@ rule {
 	}
 }
 
+func TestGetChecksForRuleWithAlertmanager(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	amPath := path.Join(dir, "alertmanager.yml")
+	err := ioutil.WriteFile(amPath, []byte("route:\n  receiver: default\nreceivers:\n- name: default\n"), 0o644)
+	assert.NoError(err)
+
+	cfgPath := path.Join(dir, "config.hcl")
+	err = ioutil.WriteFile(cfgPath, []byte(fmt.Sprintf("alertmanager {\n  config = %q\n}\n", amPath)), 0o644)
+	assert.NoError(err)
+
+	cfg, err := config.Load(cfgPath, true)
+	assert.NoError(err)
+	assert.NotNil(cfg.AlertmanagerConfig)
+	assert.Equal("default", cfg.AlertmanagerConfig.Route.Receiver)
+
+	ctx := context.WithValue(context.Background(), config.CommandKey, config.LintCommand)
+	checkNames := []string{}
+	for _, c := range cfg.GetChecksForRule(ctx, discovery.Entry{Path: "rules.yml", Rule: newRule(t, "- alert: foo\n  expr: up == 0\n")}) {
+		checkNames = append(checkNames, c.String())
+	}
+	assert.Equal([]string{
+		checks.SyntaxCheckName,
+		checks.AlertForCheckName,
+		checks.ComparisonCheckName,
+		checks.TemplateCheckName,
+		checks.FragileCheckName,
+		checks.RegexpCheckName,
+		checks.DuplicateCheckName,
+		checks.DependencyCheckName, checks.SnoozeCheckName,
+		checks.RoutingCheckName,
+	}, checkNames)
+}
+
+func TestCostBudgets(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	cfgPath := path.Join(dir, "config.hcl")
+	err := ioutil.WriteFile(cfgPath, []byte(`budget {
+  bytesPerSample = 1024
+  severity       = "warning"
+  owner "team-.+" {
+    maxSeries = 1000
+  }
+  file "rules/.+" {
+    maxMemory = "1MiB"
+  }
+}
+`), 0o644)
+	assert.NoError(err)
+
+	cfg, err := config.Load(cfgPath, true)
+	assert.NoError(err)
+
+	budgets := cfg.CostBudgets()
+	assert.Len(budgets, 2)
+	assert.Equal("^team-.+$", budgets[0].Owner.String())
+	assert.Equal(1000, budgets[0].MaxSeries)
+	assert.Equal(checks.Warning, budgets[0].Severity)
+	assert.Equal("^rules/.+$", budgets[1].Path.String())
+	assert.Equal(1024*1024, budgets[1].MaxBytes)
+
+	cfg.DisableOnlineChecks()
+	assert.Empty(cfg.CostBudgets())
+}
+
 func TestConfigErrors(t *testing.T) {
 	type testCaseT struct {
 		config string
@@ -1145,10 +1381,59 @@ func TestConfigErrors(t *testing.T) {
 }`,
 			err: "empty duration string",
 		},
+		{
+			config: `repository {
+  github {
+    mode    = "comments"
+    timeout = "10s"
+    owner   = "foo"
+    repo    = "bar"
+  }
+}`,
+			err: `invalid mode "comments", supported modes are: review, checks`,
+		},
+		{
+			config: `repository {
+  gitlab {
+    uri     = ""
+    timeout = ""
+    project = ""
+  }
+}`,
+			err: "empty duration string",
+		},
+		{
+			config: `repository {
+  gitlab {
+    uri     = "https://gitlab.example.com"
+    timeout = "10s"
+    project = ""
+  }
+}`,
+			err: "project cannot be empty",
+		},
+		{
+			config: `repository {
+  gitlab {
+    uri     = ""
+    timeout = "10s"
+    project = "foo/bar"
+  }
+}`,
+			err: "uri cannot be empty",
+		},
 		{
 			config: `checks { enabled = ["foo"] }`,
 			err:    "unknown check name foo",
 		},
+		{
+			config: `alertmanager { config = "" }`,
+			err:    "config cannot be empty",
+		},
+		{
+			config: `alertmanager { config = "missing.yml" }`,
+			err:    "open missing.yml: no such file or directory",
+		},
 		{
 			config: `prometheus "prom" {
   uri     = "http://localhost"
@@ -1259,6 +1544,14 @@ func TestConfigErrors(t *testing.T) {
   ignore {
     name = ".+++"
   }
+}`,
+			err: "error parsing regexp: invalid nested repetition operator: `++`",
+		},
+		{
+			config: `rule {
+  match {
+    group = ".+++"
+  }
 }`,
 			err: "error parsing regexp: invalid nested repetition operator: `++`",
 		},
@@ -1282,6 +1575,12 @@ func TestConfigErrors(t *testing.T) {
 }`,
 			err: "error parsing regexp: invalid nested repetition operator: `++`",
 		},
+		{
+			config: `budget {
+  owner "team-a" {}
+}`,
+			err: `budget for "team-a" must set maxSeries and/or maxMemory`,
+		},
 	}
 
 	dir := t.TempDir()
@@ -1300,3 +1599,38 @@ func TestConfigErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestRetryAndCircuitBreakerZeroValues(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	cfgPath := path.Join(dir, "config.hcl")
+	err := ioutil.WriteFile(cfgPath, []byte(`prometheus "explicit" {
+  uri     = "http://localhost"
+  timeout = "1s"
+  retry {
+    maxRetries = 0
+  }
+  circuitBreaker {
+    failures = 0
+  }
+}
+prometheus "defaults" {
+  uri     = "http://localhost"
+  timeout = "1s"
+  retry {}
+  circuitBreaker {}
+}
+`), 0o644)
+	assert.NoError(err)
+
+	cfg, err := config.Load(cfgPath, true)
+	assert.NoError(err)
+	assert.Len(cfg.Prometheus, 2)
+
+	assert.Equal(0, *cfg.Prometheus[0].Retry.MaxRetries)
+	assert.Equal(0, *cfg.Prometheus[0].CircuitBreaker.Failures)
+
+	assert.Equal(3, *cfg.Prometheus[1].Retry.MaxRetries)
+	assert.Equal(5, *cfg.Prometheus[1].CircuitBreaker.Failures)
+}
@@ -7,9 +7,11 @@ import (
 )
 
 type CostSettings struct {
-	BytesPerSample int    `hcl:"bytesPerSample,optional" json:"bytesPerSample,omitempty"`
-	MaxSeries      int    `hcl:"maxSeries,optional" json:"maxSeries,omitempty"`
-	Severity       string `hcl:"severity,optional" json:"severity,omitempty"`
+	BytesPerSample        int    `hcl:"bytesPerSample,optional" json:"bytesPerSample,omitempty"`
+	MaxSeries             int    `hcl:"maxSeries,optional" json:"maxSeries,omitempty"`
+	MaxPeakSamples        int    `hcl:"maxPeakSamples,optional" json:"maxPeakSamples,omitempty"`
+	MaxEvaluationDuration string `hcl:"maxEvaluationDuration,optional" json:"maxEvaluationDuration,omitempty"`
+	Severity              string `hcl:"severity,optional" json:"severity,omitempty"`
 }
 
 func (cs CostSettings) validate() error {
@@ -24,6 +26,14 @@ func (cs CostSettings) validate() error {
 	if cs.MaxSeries < 0 {
 		return fmt.Errorf("maxSeries value must be >= 0")
 	}
+	if cs.MaxPeakSamples < 0 {
+		return fmt.Errorf("maxPeakSamples value must be >= 0")
+	}
+	if cs.MaxEvaluationDuration != "" {
+		if _, err := parseDuration(cs.MaxEvaluationDuration); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
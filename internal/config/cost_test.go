@@ -32,6 +32,24 @@ func TestCostSettings(t *testing.T) {
 			},
 			err: errors.New("bytesPerSample value must be >= 0"),
 		},
+		{
+			conf: CostSettings{
+				MaxPeakSamples:        1000,
+				MaxEvaluationDuration: "5s",
+			},
+		},
+		{
+			conf: CostSettings{
+				MaxPeakSamples: -1,
+			},
+			err: errors.New("maxPeakSamples value must be >= 0"),
+		},
+		{
+			conf: CostSettings{
+				MaxEvaluationDuration: "5",
+			},
+			err: errors.New(`not a valid duration string: "5"`),
+		},
 		{
 			conf: CostSettings{
 				Severity: "foo",
@@ -26,11 +26,13 @@ var (
 	CICommand    ContextCommandVal = "ci"
 	LintCommand  ContextCommandVal = "lint"
 	WatchCommand ContextCommandVal = "watch"
+	FixCommand   ContextCommandVal = "fix"
 )
 
 type Match struct {
 	Path       string             `hcl:"path,optional" json:"path,omitempty"`
 	Name       string             `hcl:"name,optional" json:"name,omitempty"`
+	Group      string             `hcl:"group,optional" json:"group,omitempty"`
 	Kind       string             `hcl:"kind,optional" json:"kind,omitempty"`
 	For        string             `hcl:"for,optional" json:"for,omitempty"`
 	Label      *MatchLabel        `hcl:"label,block" json:"label,omitempty"`
@@ -47,6 +49,10 @@ func (m Match) validate(allowEmpty bool) error {
 		return err
 	}
 
+	if _, err := regexp.Compile(m.Group); err != nil {
+		return err
+	}
+
 	switch m.Kind {
 	case "":
 		// not set
@@ -74,7 +80,7 @@ func (m Match) validate(allowEmpty bool) error {
 		}
 	}
 
-	if !allowEmpty && m.Path == "" && m.Name == "" && m.Kind == "" && m.Label == nil && m.Annotation == nil && m.Command == nil && m.For == "" {
+	if !allowEmpty && m.Path == "" && m.Name == "" && m.Group == "" && m.Kind == "" && m.Label == nil && m.Annotation == nil && m.Command == nil && m.For == "" {
 		return fmt.Errorf("ignore block must have at least one condition")
 	}
 
@@ -108,6 +114,13 @@ func (m Match) IsMatch(ctx context.Context, path string, r parser.Rule) bool {
 		}
 	}
 
+	if m.Group != "" {
+		re := strictRegex(m.Group)
+		if r.Group == nil || !re.MatchString(r.Group.Name.Value.Value) {
+			return false
+		}
+	}
+
 	if m.Label != nil {
 		if !m.Label.isMatching(r) {
 			return false
@@ -1,22 +1,43 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"os"
 	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/pint/internal/promapi"
 )
 
 type PrometheusConfig struct {
-	Name        string   `hcl:",label" json:"name"`
-	URI         string   `hcl:"uri" json:"uri"`
-	Failover    []string `hcl:"failover,optional" json:"failover,omitempty"`
-	Timeout     string   `hcl:"timeout"  json:"timeout"`
-	Concurrency int      `hcl:"concurrency,optional" json:"concurrency"`
-	Paths       []string `hcl:"paths,optional" json:"paths,omitempty"`
-	Required    bool     `hcl:"required,optional" json:"required"`
+	Name           string                `hcl:",label" json:"name"`
+	URI            string                `hcl:"uri,optional" json:"uri,omitempty"`
+	TSDB           string                `hcl:"tsdb,optional" json:"tsdb,omitempty"`
+	Failover       []string              `hcl:"failover,optional" json:"failover,omitempty"`
+	Timeout        string                `hcl:"timeout"  json:"timeout"`
+	Concurrency    int                   `hcl:"concurrency,optional" json:"concurrency"`
+	Paths          []string              `hcl:"paths,optional" json:"paths,omitempty"`
+	Required       bool                  `hcl:"required,optional" json:"required"`
+	Headers        map[string]string     `hcl:"headers,optional" json:"-"`
+	BasicAuth      *BasicAuth            `hcl:"basicAuth,block" json:"basicAuth,omitempty"`
+	BearerToken    *BearerToken          `hcl:"bearerToken,block" json:"bearerToken,omitempty"`
+	TLS            *TLSConfig            `hcl:"tls,block" json:"tls,omitempty"`
+	Tenant         *TenantConfig         `hcl:"tenant,block" json:"tenant,omitempty"`
+	Retry          *RetryConfig          `hcl:"retry,block" json:"retry,omitempty"`
+	CircuitBreaker *CircuitBreakerConfig `hcl:"circuitBreaker,block" json:"circuitBreaker,omitempty"`
 }
 
 func (pc PrometheusConfig) validate() error {
-	if pc.URI == "" {
+	if pc.TSDB != "" {
+		if pc.URI != "" || len(pc.Failover) > 0 {
+			return errors.New("tsdb cannot be used together with uri or failover")
+		}
+	} else if pc.URI == "" {
 		return errors.New("prometheus URI cannot be empty")
 	}
 
@@ -30,6 +51,54 @@ func (pc PrometheusConfig) validate() error {
 		}
 	}
 
+	if pc.BasicAuth != nil && pc.BearerToken != nil {
+		return errors.New("basicAuth and bearerToken cannot be both set")
+	}
+
+	if pc.BasicAuth != nil || pc.BearerToken != nil {
+		for k := range pc.Headers {
+			if strings.EqualFold(k, "Authorization") {
+				return errors.New("Authorization header cannot be set together with basicAuth or bearerToken")
+			}
+		}
+	}
+
+	if pc.BasicAuth != nil {
+		if err := pc.BasicAuth.validate(); err != nil {
+			return err
+		}
+	}
+
+	if pc.BearerToken != nil {
+		if err := pc.BearerToken.validate(); err != nil {
+			return err
+		}
+	}
+
+	if pc.TLS != nil {
+		if err := pc.TLS.validate(); err != nil {
+			return err
+		}
+	}
+
+	if pc.Tenant != nil {
+		if err := pc.Tenant.validate(); err != nil {
+			return err
+		}
+	}
+
+	if pc.Retry != nil {
+		if err := pc.Retry.validate(); err != nil {
+			return err
+		}
+	}
+
+	if pc.CircuitBreaker != nil {
+		if err := pc.CircuitBreaker.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -45,3 +114,270 @@ func (pc PrometheusConfig) isEnabledForPath(path string) bool {
 	}
 	return false
 }
+
+// requestHeaders returns all headers that need to be set on every request
+// sent to this Prometheus server, including any authorization header.
+func (pc PrometheusConfig) requestHeaders() (map[string]string, error) {
+	headers := map[string]string{}
+	for k, v := range pc.Headers {
+		headers[k] = v
+	}
+
+	if pc.BasicAuth != nil {
+		password, err := pc.BasicAuth.getPassword()
+		if err != nil {
+			return nil, err
+		}
+		auth := base64.StdEncoding.EncodeToString([]byte(pc.BasicAuth.Username + ":" + password))
+		headers["Authorization"] = "Basic " + auth
+	}
+
+	if pc.BearerToken != nil {
+		token, err := pc.BearerToken.getToken()
+		if err != nil {
+			return nil, err
+		}
+		headers["Authorization"] = "Bearer " + token
+	}
+
+	return headers, nil
+}
+
+type BasicAuth struct {
+	Username     string `hcl:"username" json:"username"`
+	Password     string `hcl:"password,optional" json:"-"`
+	PasswordFile string `hcl:"passwordFile,optional" json:"passwordFile,omitempty"`
+}
+
+func (ba BasicAuth) validate() error {
+	if ba.Username == "" {
+		return errors.New("basicAuth username cannot be empty")
+	}
+	if ba.Password != "" && ba.PasswordFile != "" {
+		return errors.New("basicAuth password and passwordFile cannot be both set")
+	}
+	return nil
+}
+
+func (ba BasicAuth) getPassword() (string, error) {
+	if ba.PasswordFile != "" {
+		return readSecretFile(ba.PasswordFile)
+	}
+	return ba.Password, nil
+}
+
+type BearerToken struct {
+	Token     string `hcl:"token,optional" json:"-"`
+	TokenFile string `hcl:"tokenFile,optional" json:"tokenFile,omitempty"`
+	TokenEnv  string `hcl:"tokenEnv,optional" json:"tokenEnv,omitempty"`
+}
+
+func (bt BearerToken) validate() error {
+	var set int
+	for _, s := range []string{bt.Token, bt.TokenFile, bt.TokenEnv} {
+		if s != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("bearerToken requires exactly one of token, tokenFile or tokenEnv to be set")
+	}
+	return nil
+}
+
+func (bt BearerToken) getToken() (string, error) {
+	switch {
+	case bt.TokenFile != "":
+		return readSecretFile(bt.TokenFile)
+	case bt.TokenEnv != "":
+		token, ok := os.LookupEnv(bt.TokenEnv)
+		if !ok || token == "" {
+			return "", fmt.Errorf("%s environment variable is not set or empty", bt.TokenEnv)
+		}
+		return token, nil
+	default:
+		return bt.Token, nil
+	}
+}
+
+func readSecretFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
+type TLSConfig struct {
+	ServerName         string `hcl:"serverName,optional" json:"serverName,omitempty"`
+	InsecureSkipVerify bool   `hcl:"skipVerify,optional" json:"skipVerify,omitempty"`
+	CaCert             string `hcl:"caCert,optional" json:"caCert,omitempty"`
+	ClientCert         string `hcl:"clientCert,optional" json:"clientCert,omitempty"`
+	ClientKey          string `hcl:"clientKey,optional" json:"clientKey,omitempty"`
+}
+
+func (t TLSConfig) validate() error {
+	if (t.ClientCert == "") != (t.ClientKey == "") {
+		return errors.New("clientCert and clientKey must be set together")
+	}
+	return nil
+}
+
+func (t TLSConfig) toHTTPConfig() (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+
+	if t.CaCert != "" {
+		ca, err := os.ReadFile(t.CaCert)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("failed to load CA certificate from %s", t.CaCert)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if t.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(t.ClientCert, t.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+const defaultTenantHeader = "X-Scope-OrgID"
+
+type TenantConfig struct {
+	Header string `hcl:"header,optional" json:"header,omitempty"`
+	Path   string `hcl:"path,optional" json:"path,omitempty"`
+	Value  string `hcl:"value,optional" json:"value,omitempty"`
+}
+
+func (tc TenantConfig) validate() error {
+	if tc.Path != "" {
+		if _, err := regexp.Compile(tc.Path); err != nil {
+			return err
+		}
+		if tc.Value == "" {
+			return errors.New("tenant value cannot be empty when path is set")
+		}
+	}
+	return nil
+}
+
+func (tc TenantConfig) header() string {
+	if tc.Header == "" {
+		return defaultTenantHeader
+	}
+	return tc.Header
+}
+
+// forFile returns the tenant that should be used when checking rules
+// from given file. A tenant set via `# pint tenant` comment always wins,
+// otherwise value is expanded using path regexp capture groups.
+func (tc TenantConfig) forFile(path, comment string) (string, bool) {
+	if comment != "" {
+		return comment, true
+	}
+	if tc.Path == "" {
+		return tc.Value, tc.Value != ""
+	}
+	re := strictRegex(tc.Path)
+	match := re.FindStringSubmatchIndex(path)
+	if match == nil {
+		return "", false
+	}
+	tenant := string(re.ExpandString(nil, tc.Value, path, match))
+	return tenant, tenant != ""
+}
+
+type RetryConfig struct {
+	// MaxRetries is a pointer so we can tell if it was explicitly set to 0.
+	MaxRetries *int   `hcl:"maxRetries,optional" json:"maxRetries"`
+	MinBackoff string `hcl:"minBackoff,optional" json:"minBackoff"`
+	MaxBackoff string `hcl:"maxBackoff,optional" json:"maxBackoff"`
+}
+
+func (rc *RetryConfig) setDefaults() {
+	if rc.MaxRetries == nil {
+		maxRetries := 3
+		rc.MaxRetries = &maxRetries
+	}
+	if rc.MinBackoff == "" {
+		rc.MinBackoff = "500ms"
+	}
+	if rc.MaxBackoff == "" {
+		rc.MaxBackoff = "10s"
+	}
+}
+
+func (rc RetryConfig) validate() error {
+	if rc.MaxRetries != nil && *rc.MaxRetries < 0 {
+		return errors.New("retry maxRetries cannot be < 0")
+	}
+	var minBackoff, maxBackoff time.Duration
+	var err error
+	if rc.MinBackoff != "" {
+		if minBackoff, err = parseDuration(rc.MinBackoff); err != nil {
+			return err
+		}
+	}
+	if rc.MaxBackoff != "" {
+		if maxBackoff, err = parseDuration(rc.MaxBackoff); err != nil {
+			return err
+		}
+	}
+	if rc.MinBackoff != "" && rc.MaxBackoff != "" && minBackoff > maxBackoff {
+		return errors.New("retry minBackoff cannot be greater than maxBackoff")
+	}
+	return nil
+}
+
+func (rc RetryConfig) toRetryConfig() promapi.RetryConfig {
+	minBackoff, _ := parseDuration(rc.MinBackoff)
+	maxBackoff, _ := parseDuration(rc.MaxBackoff)
+	var maxRetries int
+	if rc.MaxRetries != nil {
+		maxRetries = *rc.MaxRetries
+	}
+	return promapi.RetryConfig{
+		MaxRetries: maxRetries,
+		MinBackoff: minBackoff,
+		MaxBackoff: maxBackoff,
+	}
+}
+
+type CircuitBreakerConfig struct {
+	// Failures is a pointer so we can tell if it was explicitly set to 0.
+	Failures *int   `hcl:"failures,optional" json:"failures"`
+	Cooldown string `hcl:"cooldown,optional" json:"cooldown"`
+}
+
+func (cb *CircuitBreakerConfig) setDefaults() {
+	if cb.Failures == nil {
+		failures := 5
+		cb.Failures = &failures
+	}
+	if cb.Cooldown == "" {
+		cb.Cooldown = "1m"
+	}
+}
+
+func (cb CircuitBreakerConfig) validate() error {
+	if cb.Failures != nil && *cb.Failures < 0 {
+		return errors.New("circuitBreaker failures cannot be < 0")
+	}
+	if cb.Cooldown != "" {
+		if _, err := parseDuration(cb.Cooldown); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,13 +1,22 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+func intPtr(i int) *int {
+	return &i
+}
+
 func TestPrometheusConfig(t *testing.T) {
 	type testCaseT struct {
 		conf PrometheusConfig
@@ -56,6 +65,195 @@ func TestPrometheusConfig(t *testing.T) {
 			},
 			err: errors.New("error parsing regexp: invalid nested repetition operator: `++`"),
 		},
+		{
+			conf: PrometheusConfig{
+				Name:    "prom",
+				TSDB:    "/data/tsdb",
+				Timeout: "5m",
+			},
+		},
+		{
+			conf: PrometheusConfig{
+				Name:           "prom",
+				URI:            "http://localhost",
+				Timeout:        "5m",
+				Retry:          &RetryConfig{MaxRetries: intPtr(5), MinBackoff: "1s", MaxBackoff: "1m"},
+				CircuitBreaker: &CircuitBreakerConfig{Failures: intPtr(3), Cooldown: "5m"},
+			},
+		},
+		{
+			conf: PrometheusConfig{
+				Name:    "prom",
+				URI:     "http://localhost",
+				Timeout: "5m",
+				Retry:   &RetryConfig{MaxRetries: intPtr(-1)},
+			},
+			err: errors.New("retry maxRetries cannot be < 0"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:    "prom",
+				URI:     "http://localhost",
+				Timeout: "5m",
+				Retry:   &RetryConfig{MinBackoff: "1m", MaxBackoff: "1s"},
+			},
+			err: errors.New("retry minBackoff cannot be greater than maxBackoff"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:    "prom",
+				URI:     "http://localhost",
+				Timeout: "5m",
+				Retry:   &RetryConfig{MinBackoff: "foo"},
+			},
+			err: errors.New(`not a valid duration string: "foo"`),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:           "prom",
+				URI:            "http://localhost",
+				Timeout:        "5m",
+				CircuitBreaker: &CircuitBreakerConfig{Failures: intPtr(-1)},
+			},
+			err: errors.New("circuitBreaker failures cannot be < 0"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:           "prom",
+				URI:            "http://localhost",
+				Timeout:        "5m",
+				CircuitBreaker: &CircuitBreakerConfig{Cooldown: "1x"},
+			},
+			err: errors.New(`not a valid duration string: "1x"`),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:    "prom",
+				URI:     "http://localhost",
+				TSDB:    "/data/tsdb",
+				Timeout: "5m",
+			},
+			err: errors.New("tsdb cannot be used together with uri or failover"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:     "prom",
+				TSDB:     "/data/tsdb",
+				Failover: []string{"http://localhost"},
+				Timeout:  "5m",
+			},
+			err: errors.New("tsdb cannot be used together with uri or failover"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:        "prom",
+				URI:         "http://localhost",
+				Timeout:     "5m",
+				BasicAuth:   &BasicAuth{Username: "foo", Password: "bar"},
+				BearerToken: &BearerToken{Token: "bar"},
+			},
+			err: errors.New("basicAuth and bearerToken cannot be both set"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:      "prom",
+				URI:       "http://localhost",
+				Timeout:   "5m",
+				Headers:   map[string]string{"authorization": "Basic Zm9vOmJhcg=="},
+				BasicAuth: &BasicAuth{Username: "foo", Password: "bar"},
+			},
+			err: errors.New("Authorization header cannot be set together with basicAuth or bearerToken"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:        "prom",
+				URI:         "http://localhost",
+				Timeout:     "5m",
+				Headers:     map[string]string{"Authorization": "Bearer foo"},
+				BearerToken: &BearerToken{Token: "bar"},
+			},
+			err: errors.New("Authorization header cannot be set together with basicAuth or bearerToken"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:    "prom",
+				URI:     "http://localhost",
+				Timeout: "5m",
+				Headers: map[string]string{"Authorization": "Bearer foo"},
+			},
+		},
+		{
+			conf: PrometheusConfig{
+				Name:      "prom",
+				URI:       "http://localhost",
+				Timeout:   "5m",
+				BasicAuth: &BasicAuth{Password: "bar"},
+			},
+			err: errors.New("basicAuth username cannot be empty"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:      "prom",
+				URI:       "http://localhost",
+				Timeout:   "5m",
+				BasicAuth: &BasicAuth{Username: "foo", Password: "bar", PasswordFile: "bar.txt"},
+			},
+			err: errors.New("basicAuth password and passwordFile cannot be both set"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:        "prom",
+				URI:         "http://localhost",
+				Timeout:     "5m",
+				BearerToken: &BearerToken{},
+			},
+			err: errors.New("bearerToken requires exactly one of token, tokenFile or tokenEnv to be set"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:        "prom",
+				URI:         "http://localhost",
+				Timeout:     "5m",
+				BearerToken: &BearerToken{TokenFile: "token.txt", TokenEnv: "TOKEN"},
+			},
+			err: errors.New("bearerToken requires exactly one of token, tokenFile or tokenEnv to be set"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:    "prom",
+				URI:     "http://localhost",
+				Timeout: "5m",
+				TLS:     &TLSConfig{ClientCert: "cert.pem"},
+			},
+			err: errors.New("clientCert and clientKey must be set together"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:    "prom",
+				URI:     "http://localhost",
+				Timeout: "5m",
+				Headers: map[string]string{"X-Foo": "bar"},
+				TLS:     &TLSConfig{ClientCert: "cert.pem", ClientKey: "key.pem", InsecureSkipVerify: true},
+			},
+		},
+		{
+			conf: PrometheusConfig{
+				Name:    "prom",
+				URI:     "http://localhost",
+				Timeout: "5m",
+				Tenant:  &TenantConfig{Path: "rules/(.+)/.+"},
+			},
+			err: errors.New("tenant value cannot be empty when path is set"),
+		},
+		{
+			conf: PrometheusConfig{
+				Name:    "prom",
+				URI:     "http://localhost",
+				Timeout: "5m",
+				Tenant:  &TenantConfig{Path: "rules/(.+/.+", Value: "$1"},
+			},
+			err: errors.New("error parsing regexp: missing closing ): `rules/(.+/.+`"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -70,3 +268,161 @@ func TestPrometheusConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestPrometheusConfigRequestHeaders(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := path.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0o644))
+	t.Setenv("PINT_TEST_TOKEN", "envsecret")
+
+	type testCaseT struct {
+		conf    PrometheusConfig
+		headers map[string]string
+		err     string
+	}
+
+	testCases := []testCaseT{
+		{
+			conf:    PrometheusConfig{},
+			headers: map[string]string{},
+		},
+		{
+			conf: PrometheusConfig{
+				Headers: map[string]string{"X-Foo": "bar"},
+			},
+			headers: map[string]string{"X-Foo": "bar"},
+		},
+		{
+			conf: PrometheusConfig{
+				Headers:   map[string]string{"X-Foo": "bar"},
+				BasicAuth: &BasicAuth{Username: "foo", Password: "bar"},
+			},
+			headers: map[string]string{"X-Foo": "bar", "Authorization": "Basic Zm9vOmJhcg=="},
+		},
+		{
+			conf: PrometheusConfig{
+				BasicAuth: &BasicAuth{Username: "foo", PasswordFile: tokenFile},
+			},
+			headers: map[string]string{"Authorization": "Basic Zm9vOnNlY3JldA=="},
+		},
+		{
+			conf: PrometheusConfig{
+				BearerToken: &BearerToken{Token: "abc"},
+			},
+			headers: map[string]string{"Authorization": "Bearer abc"},
+		},
+		{
+			conf: PrometheusConfig{
+				BearerToken: &BearerToken{TokenFile: tokenFile},
+			},
+			headers: map[string]string{"Authorization": "Bearer secret"},
+		},
+		{
+			conf: PrometheusConfig{
+				BearerToken: &BearerToken{TokenEnv: "PINT_TEST_TOKEN"},
+			},
+			headers: map[string]string{"Authorization": "Bearer envsecret"},
+		},
+		{
+			conf: PrometheusConfig{
+				BearerToken: &BearerToken{TokenEnv: "PINT_TEST_MISSING_TOKEN"},
+			},
+			err: "PINT_TEST_MISSING_TOKEN environment variable is not set or empty",
+		},
+		{
+			conf: PrometheusConfig{
+				BearerToken: &BearerToken{TokenFile: path.Join(dir, "missing")},
+			},
+			err: fmt.Sprintf("open %s: no such file or directory", path.Join(dir, "missing")),
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			headers, err := tc.conf.requestHeaders()
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.headers, headers)
+		})
+	}
+}
+
+func TestPrometheusConfigJSONHidesSecrets(t *testing.T) {
+	conf := PrometheusConfig{
+		Name:        "prom",
+		URI:         "http://localhost",
+		Timeout:     "1s",
+		Headers:     map[string]string{"X-Auth": "header-secret"},
+		BasicAuth:   &BasicAuth{Username: "foo", Password: "password-secret"},
+		BearerToken: &BearerToken{Token: "token-secret"},
+	}
+	out, err := json.Marshal(conf)
+	require.NoError(t, err)
+	for _, secret := range []string{"header-secret", "password-secret", "token-secret", "X-Auth"} {
+		require.NotContains(t, string(out), secret)
+	}
+}
+
+func TestTenantConfigForFile(t *testing.T) {
+	type testCaseT struct {
+		conf    TenantConfig
+		path    string
+		comment string
+		tenant  string
+		ok      bool
+	}
+
+	testCases := []testCaseT{
+		{
+			conf: TenantConfig{},
+			path: "rules/team1/alerts.yml",
+		},
+		{
+			conf:   TenantConfig{Value: "shared"},
+			path:   "rules/team1/alerts.yml",
+			tenant: "shared",
+			ok:     true,
+		},
+		{
+			conf:    TenantConfig{},
+			path:    "rules/team1/alerts.yml",
+			comment: "team2",
+			tenant:  "team2",
+			ok:      true,
+		},
+		{
+			conf:   TenantConfig{Path: "rules/([^/]+)/.+", Value: "$1"},
+			path:   "rules/team1/alerts.yml",
+			tenant: "team1",
+			ok:     true,
+		},
+		{
+			conf:   TenantConfig{Path: "rules/(?P<team>[^/]+)/(?P<env>[^/]+)/.+", Value: "${team}-${env}"},
+			path:   "rules/team1/prod/alerts.yml",
+			tenant: "team1-prod",
+			ok:     true,
+		},
+		{
+			conf:    TenantConfig{Path: "rules/([^/]+)/.+", Value: "$1"},
+			path:    "rules/team1/alerts.yml",
+			comment: "team2",
+			tenant:  "team2",
+			ok:      true,
+		},
+		{
+			conf: TenantConfig{Path: "rules/([^/]+)/.+", Value: "$1"},
+			path: "alerts.yml",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			tenant, ok := tc.conf.forFile(tc.path, tc.comment)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.tenant, tenant)
+		})
+	}
+}
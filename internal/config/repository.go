@@ -29,6 +29,7 @@ func (bb BitBucket) validate() error {
 }
 
 type GitHub struct {
+	Mode      string `hcl:"mode,optional"`
 	BaseURI   string `hcl:"baseuri,optional"`
 	UploadURI string `hcl:"uploaduri,optional"`
 	Timeout   string `hcl:"timeout"`
@@ -46,6 +47,11 @@ func (gh GitHub) validate() error {
 	if gh.Owner == "" {
 		return fmt.Errorf("owner cannot be empty")
 	}
+	switch gh.Mode {
+	case "", "review", "checks":
+	default:
+		return fmt.Errorf("invalid mode %q, supported modes are: review, checks", gh.Mode)
+	}
 	if gh.BaseURI != "" {
 		_, err := url.Parse(gh.BaseURI)
 		if err != nil {
@@ -62,7 +68,30 @@ func (gh GitHub) validate() error {
 	return nil
 }
 
+type GitLab struct {
+	URI     string `hcl:"uri"`
+	Timeout string `hcl:"timeout"`
+	Project string `hcl:"project"`
+}
+
+func (gl GitLab) validate() error {
+	if _, err := parseDuration(gl.Timeout); err != nil {
+		return err
+	}
+	if gl.Project == "" {
+		return fmt.Errorf("project cannot be empty")
+	}
+	if gl.URI == "" {
+		return fmt.Errorf("uri cannot be empty")
+	}
+	if _, err := url.Parse(gl.URI); err != nil {
+		return fmt.Errorf("invalid uri: %w", err)
+	}
+	return nil
+}
+
 type Repository struct {
 	BitBucket *BitBucket `hcl:"bitbucket,block" json:"bitbucket,omitempty"`
 	GitHub    *GitHub    `hcl:"github,block" json:"github,omitempty"`
+	GitLab    *GitLab    `hcl:"gitlab,block" json:"gitlab,omitempty"`
 }
@@ -122,10 +122,11 @@ func (rule Rule) resolveChecks(ctx context.Context, path string, r parser.Rule,
 
 	if rule.Cost != nil {
 		severity := rule.Cost.getSeverity(checks.Bug)
+		evalDur, _ := parseDuration(rule.Cost.MaxEvaluationDuration)
 		for _, prom := range prometheusServers {
 			enabled = append(enabled, checkMeta{
 				name:  checks.CostCheckName,
-				check: checks.NewCostCheck(prom, rule.Cost.BytesPerSample, rule.Cost.MaxSeries, severity),
+				check: checks.NewCostCheck(prom, rule.Cost.BytesPerSample, rule.Cost.MaxSeries, rule.Cost.MaxPeakSamples, evalDur, severity),
 			})
 		}
 	}
@@ -229,6 +230,17 @@ func isEnabled(enabledChecks, disabledChecks []string, rule parser.Rule, name st
 		}
 	}
 
+	now := time.Now()
+	for _, match := range []string{name, instance} {
+		if rule.IsSnoozed(match, now) {
+			log.Debug().
+				Str("check", instance).
+				Str("match", match).
+				Msg("Check snoozed by comment")
+			return false
+		}
+	}
+
 	for _, c := range disabledChecks {
 		if c == name || c == instance {
 			return false
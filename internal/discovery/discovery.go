@@ -1,6 +1,7 @@
 package discovery
 
 import (
+	"bytes"
 	"os"
 	"regexp"
 	"strings"
@@ -14,8 +15,9 @@ import (
 )
 
 const (
-	FileOwnerComment = "file/owner"
-	RuleOwnerComment = "rule/owner"
+	FileOwnerComment  = "file/owner"
+	RuleOwnerComment  = "rule/owner"
+	FileTenantComment = "tenant"
 )
 
 var ignoredErrors = []string{
@@ -42,7 +44,9 @@ type Entry struct {
 	PathError     error
 	ModifiedLines []int
 	Rule          parser.Rule
+	Group         *parser.RuleGroup
 	Owner         string
+	Tenant        string
 }
 
 func readFile(path string, isStrict bool) (entries []Entry, err error) {
@@ -65,9 +69,10 @@ func readFile(path string, isStrict bool) (entries []Entry, err error) {
 	}
 
 	fileOwner, _ := parser.GetComment(string(content), FileOwnerComment)
+	fileTenant, _ := parser.GetComment(string(content), FileTenantComment)
 
 	if isStrict {
-		if _, errs := rulefmt.Parse(content); len(errs) > 0 {
+		if errs := strictParse(content); len(errs) > 0 {
 			for _, err := range errs {
 				if isStrictIgnored(err) {
 					continue
@@ -112,9 +117,11 @@ func readFile(path string, isStrict bool) (entries []Entry, err error) {
 			owner = fileOwner
 		}
 		entries = append(entries, Entry{
-			Path:  path,
-			Rule:  rule,
-			Owner: owner.Value,
+			Path:   path,
+			Rule:   rule,
+			Group:  rule.Group,
+			Owner:  owner.Value,
+			Tenant: fileTenant.Value,
 		})
 	}
 
@@ -122,6 +129,56 @@ func readFile(path string, isStrict bool) (entries []Entry, err error) {
 	return entries, nil
 }
 
+// parseRules returns entries for all rules in given content.
+// It's used to read files that are only a source of information about
+// other rules, so files that fail to parse are skipped.
+func parseRules(path string, content []byte) (entries []Entry) {
+	content, err := parser.ReadContent(bytes.NewReader(content))
+	if err != nil {
+		log.Debug().Err(err).Str("path", path).Msg("Failed to read file content")
+		return nil
+	}
+
+	rules, err := parser.NewParser().Parse(content)
+	if err != nil {
+		log.Debug().Err(err).Str("path", path).Msg("Failed to parse file content")
+		return nil
+	}
+
+	fileOwner, _ := parser.GetComment(string(content), FileOwnerComment)
+	fileTenant, _ := parser.GetComment(string(content), FileTenantComment)
+	for _, rule := range rules {
+		owner, ok := rule.GetComment(RuleOwnerComment)
+		if !ok {
+			owner = fileOwner
+		}
+		entries = append(entries, Entry{
+			Path:   path,
+			Rule:   rule,
+			Group:  rule.Group,
+			Owner:  owner.Value,
+			Tenant: fileTenant.Value,
+		})
+	}
+	return entries
+}
+
+// strictParse validates content using Prometheus rule file parser.
+// Files with PrometheusRule manifests will only have the spec of each
+// PrometheusRule validated.
+func strictParse(content []byte) (errs []error) {
+	specs, ok := parser.PrometheusRuleSpecs(content)
+	if !ok {
+		_, errs = rulefmt.Parse(content)
+		return errs
+	}
+	for _, spec := range specs {
+		_, serrs := rulefmt.Parse(spec)
+		errs = append(errs, serrs...)
+	}
+	return errs
+}
+
 func matchesAny(re []*regexp.Regexp, s string) bool {
 	for _, r := range re {
 		if v := r.MatchString(s); v {
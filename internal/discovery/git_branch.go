@@ -2,7 +2,10 @@ package discovery
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/cloudflare/pint/internal/git"
@@ -35,75 +38,41 @@ type GitBranchFinder struct {
 }
 
 func (f GitBranchFinder) Find() (entries []Entry, err error) {
-	cr, err := git.CommitRange(f.gitCmd, f.baseBranch)
+	cr, err := f.commitRange()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get the list of commits to scan: %w", err)
+		return nil, err
 	}
 
-	log.Debug().Str("from", cr.From).Str("to", cr.To).Msg("Got commit range from git")
-
-	if f.maxCommits > 0 && len(cr.Commits) > f.maxCommits {
-		return nil, fmt.Errorf("number of commits to check (%d) is higher than maxCommits (%d), exiting", len(cr.Commits), f.maxCommits)
-	}
-
-	out, err := f.gitCmd("log", "--reverse", "--no-merges", "--pretty=format:%H", "--name-status", cr.String())
+	changes, skip, err := f.fileChanges(cr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get the list of modified files from git: %w", err)
+		return nil, err
+	}
+	if skip.commit != "" {
+		log.Info().Str("commit", skip.commit).Msgf("Found a commit with '%s', skipping all checks", skip.tag)
+		return []Entry{}, nil
 	}
 
 	pathCommits := map[string]map[string]struct{}{}
-	var commit string
-
-	for _, line := range strings.Split(string(out), "\n") {
-		parts := strings.Split(removeRedundantSpaces(line), " ")
-		if len(parts) == 1 && parts[0] != "" {
-			commit = parts[0]
-		} else if len(parts) >= 2 {
-			op := parts[0]
-			srcPath := parts[1]
-			dstPath := parts[len(parts)-1]
-			log.Debug().
-				Str("path", dstPath).
-				Str("commit", commit).
-				Bool("allowed", f.isPathAllowed(dstPath)).
-				Msg("Git file change")
-			if !f.isPathAllowed(dstPath) {
-				continue
-			}
-
-			msg, err := git.CommitMessage(f.gitCmd, commit)
-			if err != nil {
-				return nil, fmt.Errorf("failed to get commit message for %s: %w", commit, err)
-			}
-			if strings.Contains(msg, "[skip ci]") {
-				log.Info().Str("commit", commit).Msg("Found a commit with '[skip ci]', skipping all checks")
-				return []Entry{}, nil
-			}
-			if strings.Contains(msg, "[no ci]") {
-				log.Info().Str("commit", commit).Msg("Found a commit with '[no ci]', skipping all checks")
-				return []Entry{}, nil
-			}
-
-			if _, ok := pathCommits[dstPath]; !ok {
-				pathCommits[dstPath] = map[string]struct{}{}
-			}
-			// check if we're dealing with a rename and if so we need to
-			// rename results in pathCommits
-			if strings.HasPrefix(op, "R") {
-				if commits, ok := pathCommits[srcPath]; ok {
-					for c := range commits {
-						pathCommits[dstPath][c] = struct{}{}
-					}
-					delete(pathCommits, srcPath)
+	for _, fc := range changes {
+		if _, ok := pathCommits[fc.dstPath]; !ok {
+			pathCommits[fc.dstPath] = map[string]struct{}{}
+		}
+		// check if we're dealing with a rename and if so we need to
+		// rename results in pathCommits
+		if strings.HasPrefix(fc.op, "R") {
+			if commits, ok := pathCommits[fc.srcPath]; ok {
+				for c := range commits {
+					pathCommits[fc.dstPath][c] = struct{}{}
 				}
+				delete(pathCommits, fc.srcPath)
 			}
-			// check if file is being removed, if so drop it from the results
-			if strings.HasPrefix(op, "D") {
-				delete(pathCommits, srcPath)
-				continue
-			}
-			pathCommits[dstPath][commit] = struct{}{}
 		}
+		// check if file is being removed, if so drop it from the results
+		if strings.HasPrefix(fc.op, "D") {
+			delete(pathCommits, fc.srcPath)
+			continue
+		}
+		pathCommits[fc.dstPath][fc.commit] = struct{}{}
 	}
 
 	for path, commits := range pathCommits {
@@ -139,6 +108,168 @@ func (f GitBranchFinder) Find() (entries []Entry, err error) {
 	return entries, nil
 }
 
+// BaseEntries returns all rules defined in the base branch version of files
+// modified, renamed or removed on current branch.
+// Files that fail to parse are skipped.
+func (f GitBranchFinder) BaseEntries() (entries []Entry, err error) {
+	cr, err := f.commitRange()
+	if err != nil {
+		return nil, err
+	}
+
+	changes, skip, err := f.fileChanges(cr)
+	if err != nil {
+		return nil, err
+	}
+	if skip.commit != "" {
+		return nil, nil
+	}
+
+	// Track files added on current branch, those are not present on
+	// the base branch.
+	added := map[string]struct{}{}
+	basePaths := map[string]struct{}{}
+	for _, fc := range changes {
+		_, isAdded := added[fc.srcPath]
+		switch {
+		case strings.HasPrefix(fc.op, "A"):
+			added[fc.dstPath] = struct{}{}
+		case strings.HasPrefix(fc.op, "R"):
+			if isAdded {
+				delete(added, fc.srcPath)
+				added[fc.dstPath] = struct{}{}
+			} else {
+				basePaths[fc.srcPath] = struct{}{}
+			}
+		case !isAdded:
+			basePaths[fc.srcPath] = struct{}{}
+		}
+	}
+
+	paths := make([]string, 0, len(basePaths))
+	for path := range basePaths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	base := cr.From + "^"
+	for _, path := range paths {
+		if !f.isPathAllowed(path) {
+			continue
+		}
+		content, err := git.FileContent(f.gitCmd, base, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s from %s: %w", path, base, err)
+		}
+		entries = append(entries, parseRules(path, content)...)
+	}
+
+	return entries, nil
+}
+
+// RepositoryEntries returns all rules defined in files tracked by git.
+// Only files allowed by include patterns are read, if there are no include
+// patterns then only YAML files are read, since reading all source code and
+// binary files tracked by git would be both slow and pointless.
+// Files that fail to parse are skipped.
+func (f GitBranchFinder) RepositoryEntries() (entries []Entry, err error) {
+	paths, err := git.TrackedFiles(f.gitCmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the list of tracked files from git: %w", err)
+	}
+
+	for _, path := range paths {
+		if !f.isPathAllowed(path) {
+			continue
+		}
+		if len(f.include) == 0 && !isYamlFile(path) {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			// File might be removed from the working tree but not from git index.
+			log.Debug().Err(err).Str("path", path).Msg("Failed to read tracked file")
+			continue
+		}
+		entries = append(entries, parseRules(path, content)...)
+	}
+
+	return entries, nil
+}
+
+type fileChange struct {
+	commit  string
+	op      string
+	srcPath string
+	dstPath string
+}
+
+type skipCommit struct {
+	commit string
+	tag    string
+}
+
+func (f GitBranchFinder) commitRange() (cr git.CommitRangeResults, err error) {
+	cr, err = git.CommitRange(f.gitCmd, f.baseBranch)
+	if err != nil {
+		return cr, fmt.Errorf("failed to get the list of commits to scan: %w", err)
+	}
+
+	log.Debug().Str("from", cr.From).Str("to", cr.To).Msg("Got commit range from git")
+
+	if f.maxCommits > 0 && len(cr.Commits) > f.maxCommits {
+		return cr, fmt.Errorf("number of commits to check (%d) is higher than maxCommits (%d), exiting", len(cr.Commits), f.maxCommits)
+	}
+	return cr, nil
+}
+
+// fileChanges returns all changes to allowed files made on current branch.
+// If any commit modifying those files has a tag in the commit message asking
+// to skip CI checks then this commit is returned as skip.
+func (f GitBranchFinder) fileChanges(cr git.CommitRangeResults) (changes []fileChange, skip skipCommit, err error) {
+	out, err := f.gitCmd("log", "--reverse", "--no-merges", "--pretty=format:%H", "--name-status", cr.String())
+	if err != nil {
+		return nil, skip, fmt.Errorf("failed to get the list of modified files from git: %w", err)
+	}
+
+	var commit string
+	for _, line := range strings.Split(string(out), "\n") {
+		parts := strings.Split(removeRedundantSpaces(line), " ")
+		if len(parts) == 1 && parts[0] != "" {
+			commit = parts[0]
+		} else if len(parts) >= 2 {
+			fc := fileChange{
+				commit:  commit,
+				op:      parts[0],
+				srcPath: parts[1],
+				dstPath: parts[len(parts)-1],
+			}
+			log.Debug().
+				Str("path", fc.dstPath).
+				Str("commit", commit).
+				Bool("allowed", f.isPathAllowed(fc.dstPath)).
+				Msg("Git file change")
+			if !f.isPathAllowed(fc.dstPath) {
+				continue
+			}
+
+			msg, err := git.CommitMessage(f.gitCmd, commit)
+			if err != nil {
+				return nil, skip, fmt.Errorf("failed to get commit message for %s: %w", commit, err)
+			}
+			for _, tag := range []string{"[skip ci]", "[no ci]"} {
+				if strings.Contains(msg, tag) {
+					return nil, skipCommit{commit: commit, tag: tag}, nil
+				}
+			}
+
+			changes = append(changes, fc)
+		}
+	}
+
+	return changes, skip, nil
+}
+
 func (f GitBranchFinder) isPathAllowed(path string) bool {
 	if len(f.include) == 0 {
 		return true
@@ -152,6 +283,14 @@ func (f GitBranchFinder) isPathAllowed(path string) bool {
 	return false
 }
 
+func isYamlFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		return true
+	}
+	return false
+}
+
 func removeRedundantSpaces(line string) string {
 	return strings.Join(strings.Fields(line), " ")
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/cloudflare/pint/internal/discovery"
+	"github.com/cloudflare/pint/internal/git"
 )
 
 func blameLine(sha string, line int, filename string) string {
@@ -488,3 +489,171 @@ R090    foo/c2c.yml         c2c.yml
 		})
 	}
 }
+
+func TestGitBranchFinderBaseEntries(t *testing.T) {
+	type testCaseT struct {
+		finder discovery.GitBranchFinder
+		rules  []rule
+		err    string
+	}
+
+	gitLog := func(msg string, files map[string]string) git.CommandRunner {
+		return func(args ...string) ([]byte, error) {
+			cmd := strings.Join(args, " ")
+			switch cmd {
+			case "log --format=%H --no-abbrev-commit --reverse main..HEAD":
+				return []byte("commit1\ncommit2\n"), nil
+			case "log --reverse --no-merges --pretty=format:%H --name-status commit1^..commit2":
+				return []byte(`commit1
+A       new.yml
+M       foo.yml
+M       docs.md
+commit2
+D       old.yml
+R100    src.yml   dst.yml
+M       new.yml
+`), nil
+			case "show -s --format=%B commit1", "show -s --format=%B commit2":
+				return []byte(msg), nil
+			}
+			if content, ok := files[cmd]; ok {
+				return []byte(content), nil
+			}
+			return nil, fmt.Errorf("unknown args: %v", args)
+		}
+	}
+
+	testCases := []testCaseT{
+		{
+			finder: discovery.NewGitBranchFinder(
+				func(args ...string) ([]byte, error) {
+					return nil, fmt.Errorf("mock error")
+				},
+				nil,
+				"main",
+				0,
+				nil,
+			),
+			err: "failed to get the list of commits to scan: mock error",
+		},
+		{
+			finder: discovery.NewGitBranchFinder(
+				gitLog("foo", map[string]string{}),
+				[]*regexp.Regexp{regexp.MustCompile(`^.+\.yml$`)},
+				"main",
+				0,
+				nil,
+			),
+			err: "failed to get foo.yml from commit1^: unknown args: [show commit1^:foo.yml]",
+		},
+		{
+			finder: discovery.NewGitBranchFinder(
+				gitLog("foo [skip ci]", map[string]string{}),
+				[]*regexp.Regexp{regexp.MustCompile(`^.+\.yml$`)},
+				"main",
+				0,
+				nil,
+			),
+			rules: []rule{},
+		},
+		{
+			finder: discovery.NewGitBranchFinder(
+				gitLog("foo", map[string]string{
+					"show commit1^:foo.yml": "- record: foo\n  expr: sum(up)\n",
+					"show commit1^:old.yml": "- record: old\n  expr: sum(up)\n- alert: old\n  expr: old == 0\n",
+					"show commit1^:src.yml": "- record: src\n  expr: sum(up)\n",
+				}),
+				[]*regexp.Regexp{regexp.MustCompile(`^.+\.yml$`)},
+				"main",
+				0,
+				nil,
+			),
+			rules: []rule{
+				{path: "foo.yml", name: "foo", lines: []int{1, 2}},
+				{path: "old.yml", name: "old", lines: []int{1, 2}},
+				{path: "old.yml", name: "old", lines: []int{3, 4}},
+				{path: "src.yml", name: "src", lines: []int{1, 2}},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			entries, err := tc.finder.BaseEntries()
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			rules := []rule{}
+			for _, e := range entries {
+				rules = append(rules, rule{
+					path:  e.Path,
+					name:  e.Rule.Name(),
+					lines: e.Rule.Lines(),
+				})
+			}
+			require.ElementsMatch(t, tc.rules, rules)
+		})
+	}
+}
+
+func TestGitBranchFinderRepositoryEntries(t *testing.T) {
+	workdir := t.TempDir()
+	require.NoError(t, os.Chdir(workdir))
+	require.NoError(t, os.MkdirAll("rules", 0o755))
+	require.NoError(t, ioutil.WriteFile("rules/1.yml", []byte("- record: foo\n  expr: sum(up)\n"), 0o644))
+	require.NoError(t, ioutil.WriteFile("rules/2.yml", []byte("- record: foo\n  expr: sum(up\n"), 0o644))
+	require.NoError(t, ioutil.WriteFile("README.md", []byte("# Rules\n"), 0o644))
+
+	finder := discovery.NewGitBranchFinder(
+		func(args ...string) ([]byte, error) {
+			if strings.Join(args, " ") != "ls-files" {
+				return nil, fmt.Errorf("unknown args: %v", args)
+			}
+			return []byte("README.md\nrules/1.yml\nrules/2.yml\nrules/3.yml\n"), nil
+		},
+		[]*regexp.Regexp{regexp.MustCompile(`^rules/.+$`)},
+		"main",
+		0,
+		nil,
+	)
+
+	entries, err := finder.RepositoryEntries()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "rules/1.yml", entries[0].Path)
+	require.Equal(t, "foo", entries[0].Rule.Name())
+	require.NoError(t, entries[0].Rule.Error.Err)
+	require.Equal(t, "rules/2.yml", entries[1].Path)
+	require.Error(t, entries[1].Rule.Expr().SyntaxError)
+
+	// Without include patterns only YAML files are read.
+	require.NoError(t, ioutil.WriteFile("rules.txt", []byte("- record: foo\n  expr: sum(up)\n"), 0o644))
+	finder = discovery.NewGitBranchFinder(
+		func(args ...string) ([]byte, error) {
+			return []byte("README.md\nrules.txt\nrules/1.yml\n"), nil
+		},
+		nil,
+		"main",
+		0,
+		nil,
+	)
+	entries, err = finder.RepositoryEntries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "rules/1.yml", entries[0].Path)
+
+	finder = discovery.NewGitBranchFinder(
+		func(args ...string) ([]byte, error) {
+			return nil, fmt.Errorf("mock error")
+		},
+		nil,
+		"main",
+		0,
+		nil,
+	)
+	_, err = finder.RepositoryEntries()
+	require.EqualError(t, err, "failed to get the list of tracked files from git: mock error")
+}
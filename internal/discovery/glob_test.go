@@ -34,6 +34,18 @@ func TestGlobPathFinder(t *testing.T) {
 
 	_, strictErrs := rulefmt.Parse([]byte(testRuleBody))
 
+	testTenantRules, err := p.Parse([]byte("# pint tenant team1\n" + testRuleBody))
+	require.NoError(t, err)
+
+	testGroupBody := "groups:\n- name: foo\n  interval: 1m\n  rules:\n  - record: foo\n    expr: sum(foo)\n"
+	testGroupRules, err := p.Parse([]byte(testGroupBody))
+	require.NoError(t, err)
+
+	testCRDBody := "---\napiVersion: v1\nkind: Service\n---\napiVersion: monitoring.coreos.com/v1\nkind: PrometheusRule\nspec:\n  " +
+		strings.ReplaceAll(strings.TrimSuffix(testGroupBody, "\n"), "\n", "\n  ") + "\n"
+	testCRDRules, err := p.Parse([]byte(testCRDBody))
+	require.NoError(t, err)
+
 	testCases := []testCaseT{
 		{
 			files:  map[string]string{},
@@ -84,6 +96,19 @@ func TestGlobPathFinder(t *testing.T) {
 				},
 			},
 		},
+		{
+			files:  map[string]string{"foo/bar.yml": "# pint tenant team1\n" + testRuleBody},
+			finder: discovery.NewGlobFinder([]string{"*"}, []*regexp.Regexp{regexp.MustCompile(".*")}),
+			entries: []discovery.Entry{
+				{
+					Path:          "foo/bar.yml",
+					Rule:          testTenantRules[0],
+					ModifiedLines: testTenantRules[0].Lines(),
+					Owner:         "bob",
+					Tenant:        "team1",
+				},
+			},
+		},
 		{
 			files:  map[string]string{"bar.yml": testRuleBody},
 			finder: discovery.NewGlobFinder([]string{"*"}, nil),
@@ -96,6 +121,30 @@ func TestGlobPathFinder(t *testing.T) {
 				},
 			},
 		},
+		{
+			files:  map[string]string{"bar.yml": testGroupBody},
+			finder: discovery.NewGlobFinder([]string{"*"}, nil),
+			entries: []discovery.Entry{
+				{
+					Path:          "bar.yml",
+					Rule:          testGroupRules[0],
+					Group:         testGroupRules[0].Group,
+					ModifiedLines: testGroupRules[0].Lines(),
+				},
+			},
+		},
+		{
+			files:  map[string]string{"bar.yml": testCRDBody},
+			finder: discovery.NewGlobFinder([]string{"*"}, nil),
+			entries: []discovery.Entry{
+				{
+					Path:          "bar.yml",
+					Rule:          testCRDRules[0],
+					Group:         testCRDRules[0].Group,
+					ModifiedLines: []int{12, 13},
+				},
+			},
+		},
 		{
 			files:  map[string]string{"bar.yml": "record:::{}\n  expr: sum(foo)\n\n# pint file/owner bob\n"},
 			finder: discovery.NewGlobFinder([]string{"*"}, []*regexp.Regexp{regexp.MustCompile(".*")}),
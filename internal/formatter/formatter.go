@@ -0,0 +1,290 @@
+package formatter
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+type change struct {
+	first int
+	last  int
+	lines []string
+}
+
+// Format returns content of a rule file with all rule queries formatted
+// and all labels and annotations sorted by key.
+// Only lines with rule queries, labels or annotations that need to be
+// modified are changed, everything else, including comments, is preserved.
+func Format(content []byte) ([]byte, error) {
+	rules, err := parseRules(content)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	var changes []change
+	for _, rule := range rules {
+		if rule.Error.Err != nil {
+			continue
+		}
+		if c, ok := formatExprLines(lines, rule.Expr()); ok {
+			changes = append(changes, c)
+		}
+		for _, m := range ruleMaps(rule) {
+			if c, ok := sortMapLines(lines, m); ok {
+				changes = append(changes, c)
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].first > changes[j].first
+	})
+	for _, c := range changes {
+		tail := append([]string{}, lines[c.last:]...)
+		lines = append(append(lines[:c.first-1], c.lines...), tail...)
+	}
+
+	out := []byte(strings.Join(lines, "\n"))
+	if err = verify(rules, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func parseRules(content []byte) ([]parser.Rule, error) {
+	data, err := parser.ReadContent(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	return parser.NewParser().Parse(data)
+}
+
+func formatExprLines(lines []string, expr parser.PromQLExpr) (c change, ok bool) {
+	keyLine := expr.Key.Position.FirstLine()
+	if keyLine < 1 || keyLine > len(lines) {
+		return c, false
+	}
+	line := lines[keyLine-1]
+	col := strings.Index(line, expr.Key.Value+":")
+	if col < 0 {
+		return c, false
+	}
+	header := line[:col+len(expr.Key.Value)+1]
+	rest := strings.TrimLeft(line[len(header):], " ")
+
+	formatted, err := FormatExpr(expr.Value.Value)
+	if err != nil {
+		return c, false
+	}
+
+	switch {
+	case strings.HasPrefix(rest, "|"):
+		vl := expr.Value.Position.Lines
+		value := strings.TrimSuffix(expr.Value.Value, "\n")
+		if value == formatted || !isSequence(vl, keyLine+1) || len(vl) != len(strings.Split(value, "\n")) {
+			return c, false
+		}
+		chomp, indicator, tail := parseBlockHeader(rest[1:])
+		indent := col + indicator
+		if indicator == 0 {
+			first := lines[vl[0]-1]
+			indent = len(first) - len(strings.TrimLeft(first, " "))
+		}
+		if indent <= col || indent-col > 9 {
+			return c, false
+		}
+		c = change{first: vl[0], last: vl[len(vl)-1], lines: indentLines(formatted, strings.Repeat(" ", indent))}
+		if strings.HasPrefix(formatted, " ") {
+			c.first = keyLine
+			c.lines = append([]string{blockHeader(header, chomp, indent-col, tail)}, c.lines...)
+		}
+		return c, true
+	case rest == "", strings.HasPrefix(rest, ">"), strings.HasPrefix(rest, `"`), strings.HasPrefix(rest, "'"):
+		return c, false
+	default:
+		vl := expr.Value.Position.Lines
+		if len(vl) != 1 || vl[0] != keyLine || !strings.HasPrefix(rest, expr.Value.Value) || expr.Value.Value == formatted {
+			return c, false
+		}
+		suffix := rest[len(expr.Value.Value):]
+		if !strings.Contains(formatted, "\n") && !needsQuoting(formatted) {
+			return change{first: keyLine, last: keyLine, lines: []string{header + " " + formatted + suffix}}, true
+		}
+		var indicator int
+		if strings.HasPrefix(formatted, " ") {
+			indicator = 2
+		}
+		return change{
+			first: keyLine,
+			last:  keyLine,
+			lines: append([]string{blockHeader(header, "", indicator, suffix)}, indentLines(formatted, strings.Repeat(" ", col+2))...),
+		}, true
+	}
+}
+
+// parseBlockHeader parses the header of a literal block scalar after the
+// leading | and returns chomping and indentation indicators with anything
+// that follows them, which might be a comment.
+func parseBlockHeader(s string) (chomp string, indicator int, tail string) {
+	for i, r := range s {
+		switch {
+		case r == '-' || r == '+':
+			chomp = string(r)
+		case r >= '1' && r <= '9':
+			indicator = int(r - '0')
+		default:
+			return chomp, indicator, s[i:]
+		}
+	}
+	return chomp, indicator, ""
+}
+
+// blockHeader returns the first line of a literal block scalar.
+// Indentation indicator is only needed when the first line of the block
+// is indented more than the following lines.
+func blockHeader(key, chomp string, indicator int, tail string) string {
+	header := key + " |" + chomp
+	if indicator > 0 {
+		header += strconv.Itoa(indicator)
+	}
+	return header + tail
+}
+
+// sortMapLines will sort map items by key, but only if each item
+// is on a separate line and there are no comments or empty lines between them.
+func sortMapLines(lines []string, m *parser.YamlMap) (c change, ok bool) {
+	if m == nil || len(m.Items) < 2 {
+		return c, false
+	}
+
+	spans := make([][]string, 0, len(m.Items))
+	next := m.Items[0].Key.Position.FirstLine()
+	if next <= m.Key.Position.LastLine() {
+		return c, false
+	}
+	for _, item := range m.Items {
+		il := item.Lines()
+		sort.Ints(il)
+		if !isSequence(il, next) || il[len(il)-1] > len(lines) {
+			return c, false
+		}
+		spans = append(spans, lines[il[0]-1:il[len(il)-1]])
+		next = il[len(il)-1] + 1
+	}
+
+	idx := make([]int, len(m.Items))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return m.Items[idx[i]].Key.Value < m.Items[idx[j]].Key.Value
+	})
+	if sort.IntsAreSorted(idx) {
+		return c, false
+	}
+
+	c.first = m.Items[0].Key.Position.FirstLine()
+	c.last = next - 1
+	for _, i := range idx {
+		c.lines = append(c.lines, spans[i]...)
+	}
+	return c, true
+}
+
+func isSequence(lines []int, first int) bool {
+	if len(lines) == 0 {
+		return false
+	}
+	for i, l := range lines {
+		if l != first+i {
+			return false
+		}
+	}
+	return true
+}
+
+func indentLines(s, indent string) (lines []string) {
+	for _, line := range strings.Split(s, "\n") {
+		lines = append(lines, indent+line)
+	}
+	return lines
+}
+
+// needsQuoting returns true if given string cannot be used as a plain YAML value.
+func needsQuoting(s string) bool {
+	return s == "" ||
+		strings.ContainsAny(s[:1], "{}[]&*!|>'\"%@`#,") ||
+		strings.HasPrefix(s, "- ") ||
+		strings.HasPrefix(s, "? ") ||
+		strings.HasPrefix(s, ": ") ||
+		strings.Contains(s, ": ") ||
+		strings.Contains(s, " #") ||
+		strings.HasSuffix(s, ":")
+}
+
+// verify checks that formatted content has the same rules as the original.
+func verify(rules []parser.Rule, content []byte) error {
+	formatted, err := parseRules(content)
+	if err != nil {
+		return fmt.Errorf("formatted content cannot be parsed: %w", err)
+	}
+	if len(formatted) != len(rules) {
+		return fmt.Errorf("formatted content has %d rule(s), expected %d", len(formatted), len(rules))
+	}
+	for i := range rules {
+		if rules[i].Error.Err != nil {
+			continue
+		}
+		line := rules[i].Lines()[0]
+		if rules[i].Name() != formatted[i].Name() {
+			return fmt.Errorf("formatting changed rule name on line %d", line)
+		}
+		if !isSameExpr(rules[i].Expr().Value.Value, formatted[i].Expr().Value.Value) {
+			return fmt.Errorf("formatting changed rule query on line %d", line)
+		}
+		if !isSameMaps(ruleMaps(rules[i]), ruleMaps(formatted[i])) {
+			return fmt.Errorf("formatting changed rule labels or annotations on line %d", line)
+		}
+	}
+	return nil
+}
+
+func ruleMaps(rule parser.Rule) (maps []*parser.YamlMap) {
+	if rule.AlertingRule != nil {
+		maps = append(maps, rule.AlertingRule.Labels, rule.AlertingRule.Annotations)
+	}
+	if rule.RecordingRule != nil {
+		maps = append(maps, rule.RecordingRule.Labels)
+	}
+	return maps
+}
+
+func isSameMaps(a, b []*parser.YamlMap) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if mapString(a[i]) != mapString(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func mapString(m *parser.YamlMap) string {
+	if m == nil {
+		return ""
+	}
+	items := make([]string, 0, len(m.Items))
+	for _, item := range m.Items {
+		items = append(items, fmt.Sprintf("%q=%q", item.Key.Value, item.Value.Value))
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
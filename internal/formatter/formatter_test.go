@@ -0,0 +1,145 @@
+package formatter_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/formatter"
+)
+
+func TestFormatExpr(t *testing.T) {
+	type testCaseT struct {
+		input  string
+		output string
+		err    string
+	}
+
+	testCases := []testCaseT{
+		{input: "up", output: "up"},
+		{input: "sum( foo{job='bar'} ) by (job)", output: `sum by(job) (foo{job="bar"})`},
+		{input: "foo   /   on(job)   group_left()   bar", output: "foo / on(job) group_left() bar"},
+		{input: "rate(foo[5m]) > bool 0", output: "rate(foo[5m]) > bool 0"},
+		{input: "sum(foo", err: "1:8: parse error: unclosed left parenthesis"},
+		{input: "sum(foo) # comment", err: "query contains comments"},
+		{
+			input: `sum by(job, instance) (rate(http_requests_total{job="api-server", status=~"5.."}[5m])) / sum by(job, instance) (rate(http_requests_total{job="api-server"}[5m])) > 0.01`,
+			output: `    sum by(job, instance) (rate(http_requests_total{job="api-server",status=~"5.."}[5m]))
+  /
+    sum by(job, instance) (rate(http_requests_total{job="api-server"}[5m]))
+>
+  0.01`,
+		},
+		{
+			input: `count(up{job="api-server", instance=~"node-.+", cluster="production-eu-west-1", env="production"} == 0) by (cluster, instance)`,
+			output: `count by(cluster, instance) (
+  up{cluster="production-eu-west-1",env="production",instance=~"node-.+",job="api-server"} == 0
+)`,
+		},
+		{
+			input: `topk(5, label_replace(rate(http_requests_total{job="api-server", cluster="production-eu-west-1"}[5m]), "dst", "$1", "src", "(.+)"))`,
+			output: `topk(
+  5,
+  label_replace(
+    rate(http_requests_total{cluster="production-eu-west-1",job="api-server"}[5m]),
+    "dst",
+    "$1",
+    "src",
+    "(.+)"
+  )
+)`,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			output, err := formatter.FormatExpr(tc.input)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.output, output)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	type testCaseT struct {
+		input  string
+		output string
+		err    string
+	}
+
+	testCases := []testCaseT{
+		{
+			input:  "",
+			output: "",
+		},
+		{
+			input:  "- record: foo\n  expr: sum by(job) (foo)\n",
+			output: "- record: foo\n  expr: sum by(job) (foo)\n",
+		},
+		{
+			input:  "- record: foo\n  expr: sum(foo{job='bar'})   by (job) # comment\n",
+			output: "- record: foo\n  expr: sum by(job) (foo{job=\"bar\"}) # comment\n",
+		},
+		{
+			input:  "- record: foo\n  expr: |\n    sum(foo)\n    by (job)\n  labels:\n    job: foo\n",
+			output: "- record: foo\n  expr: |\n    sum by(job) (foo)\n  labels:\n    job: foo\n",
+		},
+		{
+			input:  "- record: foo\n  expr: '{__name__=\"foo\"}'\n",
+			output: "- record: foo\n  expr: '{__name__=\"foo\"}'\n",
+		},
+		{
+			input:  "- record: foo\n  expr: foo + \n    bar\n",
+			output: "- record: foo\n  expr: foo + \n    bar\n",
+		},
+		{
+			input:  "- record: foo\n  expr: foo{job=\"a\"} or foo{__name__=\"bar\"}\n",
+			output: "- record: foo\n  expr: foo{job=\"a\"} or foo{__name__=\"bar\"}\n",
+		},
+		{
+			input:  "groups:\n- name: foo\n  rules:\n  # pint disable promql/series\n  - alert: foo\n    expr: count(up{job=\"api-server\", instance=~\"node-.+\", cluster=\"production-eu-west-1\", env=\"production\"} == 0) by (cluster, instance)\n    labels:\n      severity: page\n      # comment\n      env: prod\n      cluster: foo\n    annotations:\n      summary: foo\n      description: |\n        foo\n        bar\n",
+			output: "groups:\n- name: foo\n  rules:\n  # pint disable promql/series\n  - alert: foo\n    expr: |\n      count by(cluster, instance) (\n        up{cluster=\"production-eu-west-1\",env=\"production\",instance=~\"node-.+\",job=\"api-server\"} == 0\n      )\n    labels:\n      severity: page\n      # comment\n      env: prod\n      cluster: foo\n    annotations:\n      description: |\n        foo\n        bar\n      summary: foo\n",
+		},
+		{
+			input:  "- record: foo\n  expr: sum by(job, instance) (rate(http_requests_total{job=\"api-server\", status=~\"5..\"}[5m])) / sum by(job, instance) (rate(http_requests_total[5m])) > 0.01\n",
+			output: "- record: foo\n  expr: |2\n        sum by(job, instance) (rate(http_requests_total{job=\"api-server\",status=~\"5..\"}[5m]))\n      /\n        sum by(job, instance) (rate(http_requests_total[5m]))\n    >\n      0.01\n",
+		},
+		{
+			input:  "- record: foo\n  expr: |- # comment\n      sum by(job, instance) (rate(http_requests_total{job=\"api-server\", status=~\"5..\"}[5m])) / sum by(job, instance) (rate(http_requests_total[5m])) > 0.01\n",
+			output: "- record: foo\n  expr: |-4 # comment\n          sum by(job, instance) (rate(http_requests_total{job=\"api-server\",status=~\"5..\"}[5m]))\n        /\n          sum by(job, instance) (rate(http_requests_total[5m]))\n      >\n        0.01\n",
+		},
+		{
+			input:  "- alert: foo\n  expr: up == 0\n  labels: {b: b, a: a}\n",
+			output: "- alert: foo\n  expr: up == 0\n  labels: {b: b, a: a}\n",
+		},
+		{
+			input:  "- record: foo\n  expr: sum(foo) # pint ignore/line\n",
+			output: "- record: foo\n  expr: sum(foo) # pint ignore/line\n",
+		},
+		{
+			input:  "- record: foo\n  expr: sum(foo\n- record: bar\n  expr: sum( bar )\n",
+			output: "- record: foo\n  expr: sum(foo\n- record: bar\n  expr: sum(bar)\n",
+		},
+		{
+			input: "- record: foo\n  expr: sum(foo)\n  labels: [\n",
+			err:   "yaml: line 3: did not find expected node content",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			output, err := formatter.Format([]byte(tc.input))
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.output, string(output))
+			}
+		})
+	}
+}
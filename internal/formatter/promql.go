@@ -0,0 +1,134 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	promParser "github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	maxCharactersPerLine = 100
+	indentString         = "  "
+)
+
+// FormatExpr returns canonical representation of given PromQL query.
+// Queries longer than maxCharactersPerLine will be split into multiple lines.
+// It will return an error if the query cannot be parsed or if it contains
+// comments, since those would be lost when formatting it.
+func FormatExpr(expr string) (string, error) {
+	if hasComments(expr) {
+		return "", fmt.Errorf("query contains comments")
+	}
+	node, err := promParser.ParseExpr(expr)
+	if err != nil {
+		return "", err
+	}
+	return pretty(node, 0), nil
+}
+
+func hasComments(expr string) bool {
+	l := promParser.Lex(expr)
+	var item promParser.Item
+	for {
+		l.NextItem(&item)
+		switch item.Typ {
+		case promParser.COMMENT:
+			return true
+		case promParser.EOF, promParser.ERROR:
+			return false
+		}
+	}
+}
+
+func indent(level int) string {
+	return strings.Repeat(indentString, level)
+}
+
+func needsSplit(node promParser.Node) bool {
+	return len(node.String()) > maxCharactersPerLine
+}
+
+func pretty(node promParser.Node, level int) string {
+	if !needsSplit(node) {
+		return indent(level) + node.String()
+	}
+
+	switch n := node.(type) {
+	case *promParser.AggregateExpr:
+		s := indent(level) + aggregateOp(n) + "(\n"
+		if n.Op.IsAggregatorWithParam() {
+			s += pretty(n.Param, level+1) + ",\n"
+		}
+		return s + pretty(n.Expr, level+1) + "\n" + indent(level) + ")"
+	case *promParser.BinaryExpr:
+		return fmt.Sprintf("%s\n%s%s%s\n%s",
+			pretty(n.LHS, level+1), indent(level), binaryOp(n), vectorMatching(n.VectorMatching), pretty(n.RHS, level+1))
+	case *promParser.Call:
+		args := make([]string, 0, len(n.Args))
+		for _, arg := range n.Args {
+			args = append(args, pretty(arg, level+1))
+		}
+		return fmt.Sprintf("%s%s(\n%s\n%s)", indent(level), n.Func.Name, strings.Join(args, ",\n"), indent(level))
+	case *promParser.ParenExpr:
+		return fmt.Sprintf("%s(\n%s\n%s)", indent(level), pretty(n.Expr, level+1), indent(level))
+	case *promParser.SubqueryExpr:
+		s := n.String()
+		return pretty(n.Expr, level) + s[len(n.Expr.String()):]
+	case *promParser.UnaryExpr:
+		return indent(level) + n.Op.String() + strings.TrimLeft(pretty(n.Expr, level), " ")
+	case *promParser.StepInvariantExpr:
+		return pretty(n.Expr, level)
+	}
+
+	return indent(level) + node.String()
+}
+
+func aggregateOp(n *promParser.AggregateExpr) string {
+	switch {
+	case n.Without:
+		return fmt.Sprintf("%s without(%s) ", n.Op, strings.Join(n.Grouping, ", "))
+	case len(n.Grouping) > 0:
+		return fmt.Sprintf("%s by(%s) ", n.Op, strings.Join(n.Grouping, ", "))
+	}
+	return n.Op.String()
+}
+
+func binaryOp(n *promParser.BinaryExpr) string {
+	if n.ReturnBool {
+		return n.Op.String() + " bool"
+	}
+	return n.Op.String()
+}
+
+func vectorMatching(vm *promParser.VectorMatching) (s string) {
+	if vm == nil || (len(vm.MatchingLabels) == 0 && !vm.On) {
+		return ""
+	}
+	if vm.On {
+		s = fmt.Sprintf(" on(%s)", strings.Join(vm.MatchingLabels, ", "))
+	} else {
+		s = fmt.Sprintf(" ignoring(%s)", strings.Join(vm.MatchingLabels, ", "))
+	}
+	switch vm.Card {
+	case promParser.CardManyToOne:
+		s += fmt.Sprintf(" group_left(%s)", strings.Join(vm.Include, ", "))
+	case promParser.CardOneToMany:
+		s += fmt.Sprintf(" group_right(%s)", strings.Join(vm.Include, ", "))
+	}
+	return s
+}
+
+// isSameExpr returns true if both queries are parsed into identical AST
+// or, if any of them cannot be parsed, when both are identical strings.
+func isSameExpr(a, b string) bool {
+	an, err := promParser.ParseExpr(a)
+	if err != nil {
+		return a == b
+	}
+	bn, err := promParser.ParseExpr(b)
+	if err != nil {
+		return a == b
+	}
+	return an.String() == bn.String()
+}
@@ -147,3 +147,20 @@ func CommitMessage(cmd CommandRunner, sha string) (string, error) {
 	}
 	return string(msg), err
 }
+
+func FileContent(cmd CommandRunner, commit, path string) ([]byte, error) {
+	return cmd("show", fmt.Sprintf("%s:%s", commit, path))
+}
+
+func TrackedFiles(cmd CommandRunner) (paths []string, err error) {
+	out, err := cmd("ls-files")
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths, nil
+}
@@ -313,3 +313,81 @@ func TestCommitMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestFileContent(t *testing.T) {
+	type testCaseT struct {
+		mock   git.CommandRunner
+		output string
+		err    string
+	}
+
+	testCases := []testCaseT{
+		{
+			mock: func(args ...string) ([]byte, error) {
+				return nil, fmt.Errorf("mock error")
+			},
+			err: "mock error",
+		},
+		{
+			mock: func(args ...string) ([]byte, error) {
+				if strings.Join(args, " ") != "show abc123^:rules/foo.yml" {
+					return nil, fmt.Errorf("unexpected args: %v", args)
+				}
+				return []byte("- record: foo\n  expr: sum(bar)\n"), nil
+			},
+			output: "- record: foo\n  expr: sum(bar)\n",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			output, err := git.FileContent(tc.mock, "abc123^", "rules/foo.yml")
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.output, string(output))
+			}
+		})
+	}
+}
+
+func TestTrackedFiles(t *testing.T) {
+	type testCaseT struct {
+		mock  git.CommandRunner
+		paths []string
+		err   string
+	}
+
+	testCases := []testCaseT{
+		{
+			mock: func(args ...string) ([]byte, error) {
+				return nil, fmt.Errorf("mock error")
+			},
+			err: "mock error",
+		},
+		{
+			mock: func(args ...string) ([]byte, error) {
+				return []byte(""), nil
+			},
+		},
+		{
+			mock: func(args ...string) ([]byte, error) {
+				return []byte("foo.yml\nrules/bar.yml\n"), nil
+			},
+			paths: []string{"foo.yml", "rules/bar.yml"},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			paths, err := git.TrackedFiles(tc.mock)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.paths, paths)
+			}
+		})
+	}
+}
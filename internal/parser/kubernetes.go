@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	apiVersionKey = "apiVersion"
+	kindKey       = "kind"
+	specKey       = "spec"
+
+	prometheusRuleAPIGroup = "monitoring.coreos.com/"
+	prometheusRuleKind     = "PrometheusRule"
+)
+
+func decodeDocuments(content []byte) (docs []*yaml.Node, err error) {
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var doc yaml.Node
+		err = dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, &doc)
+	}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 1; i < len(node.Content); i += 2 {
+		if node.Content[i-1].Value == key {
+			return node.Content[i]
+		}
+	}
+	return nil
+}
+
+// prometheusRuleSpec returns the spec node of a PrometheusRule manifest
+// or nil if given document is not a PrometheusRule.
+func prometheusRuleSpec(doc *yaml.Node) (spec *yaml.Node, ok bool) {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, false
+	}
+	root := doc.Content[0]
+
+	apiVersion := mappingValue(root, apiVersionKey)
+	if apiVersion == nil || !strings.HasPrefix(apiVersion.Value, prometheusRuleAPIGroup) {
+		return nil, false
+	}
+	kind := mappingValue(root, kindKey)
+	if kind == nil || kind.Value != prometheusRuleKind {
+		return nil, false
+	}
+	return mappingValue(root, specKey), true
+}
+
+func prometheusRuleSpecs(docs []*yaml.Node) (specs []*yaml.Node, found bool) {
+	for _, doc := range docs {
+		if spec, ok := prometheusRuleSpec(doc); ok {
+			found = true
+			if spec != nil {
+				specs = append(specs, spec)
+			}
+		}
+	}
+	return specs, found
+}
+
+// PrometheusRuleSpecs returns the spec section of every PrometheusRule
+// manifest found in given content. Each returned spec has the same number
+// of lines as the original content, with all lines outside of the spec
+// blanked, so any line numbers reported when parsing it will match lines
+// in the original file.
+// ok will be false if there are no PrometheusRule manifests in the content.
+func PrometheusRuleSpecs(content []byte) (specs [][]byte, ok bool) {
+	docs, err := decodeDocuments(content)
+	if err != nil {
+		return nil, false
+	}
+
+	nodes, ok := prometheusRuleSpecs(docs)
+	lines := strings.Split(string(content), "\n")
+	for _, node := range nodes {
+		specs = append(specs, extractNodeLines(lines, node))
+	}
+	return specs, ok
+}
+
+func extractNodeLines(lines []string, node *yaml.Node) []byte {
+	out := make([]string, len(lines))
+	indent := node.Column - 1
+	for i := node.Line - 1; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if i >= node.Line && len(line)-len(strings.TrimLeft(line, " ")) < indent {
+			break
+		}
+		out[i] = line[indent:]
+	}
+	return []byte(strings.Join(out, "\n"))
+}
@@ -0,0 +1,91 @@
+package parser_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+func TestPrometheusRuleSpecs(t *testing.T) {
+	type testCaseT struct {
+		content string
+		specs   []string
+		ok      bool
+	}
+
+	testCases := []testCaseT{
+		{
+			content: "",
+		},
+		{
+			content: "groups:\n- name: foo\n  rules: []\n",
+		},
+		{
+			content: "! !00 \xf6",
+		},
+		{
+			content: "apiVersion: v1\nkind: ConfigMap\ndata: {}\n",
+		},
+		{
+			content: "apiVersion: monitoring.coreos.com/v1\nkind: PrometheusRule\nmetadata:\n  name: foo\n",
+			ok:      true,
+		},
+		{
+			content: `apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: foo
+spec:
+  groups:
+  # comment
+  - name: foo
+
+    rules:
+    - record: foo
+      expr: bar
+status: {}
+`,
+			specs: []string{
+				"\n\n\n\n\ngroups:\n\n- name: foo\n\n  rules:\n  - record: foo\n    expr: bar\n\n",
+			},
+			ok: true,
+		},
+		{
+			content: `---
+apiVersion: v1
+kind: Service
+spec:
+  foo: bar
+---
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+spec:
+    groups: []
+---
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+spec: {groups: [{name: foo, rules: []}]}
+`,
+			specs: []string{
+				"\n\n\n\n\n\n\n\n\ngroups: []\n\n\n\n\n",
+				"\n\n\n\n\n\n\n\n\n\n\n\n\n{groups: [{name: foo, rules: []}]}\n",
+			},
+			ok: true,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i+1), func(t *testing.T) {
+			specs, ok := parser.PrometheusRuleSpecs([]byte(tc.content))
+			require.Equal(t, tc.ok, ok)
+			var got []string
+			for _, spec := range specs {
+				got = append(got, string(spec))
+			}
+			require.Equal(t, tc.specs, got)
+		})
+	}
+}
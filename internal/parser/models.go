@@ -2,6 +2,7 @@ package parser
 
 import (
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -297,6 +298,34 @@ func (rr RecordingRule) Comments() (comments []string) {
 	return
 }
 
+// RuleGroup describes the group a rule was defined in.
+type RuleGroup struct {
+	Name     YamlKeyValue
+	Interval *YamlKeyValue
+	Limit    *YamlKeyValue
+	Comments []string
+}
+
+func (rg RuleGroup) Lines() (lines []int) {
+	lines = appendLine(lines, rg.Name.Lines()...)
+	if rg.Interval != nil {
+		lines = appendLine(lines, rg.Interval.Lines()...)
+	}
+	if rg.Limit != nil {
+		lines = appendLine(lines, rg.Limit.Lines()...)
+	}
+	return
+}
+
+func (rg RuleGroup) HasComment(comment string) bool {
+	for _, c := range rg.Comments {
+		if hasComment(c, comment) {
+			return true
+		}
+	}
+	return false
+}
+
 type ParseError struct {
 	Fragment string
 	Err      error
@@ -307,6 +336,22 @@ type Rule struct {
 	AlertingRule  *AlertingRule
 	RecordingRule *RecordingRule
 	Error         ParseError
+	// Group is only set for rules defined inside a "groups -> rules" block.
+	Group *RuleGroup
+	// GroupIndex is the position of this rule in its group rules list.
+	GroupIndex int
+	// Snoozes are all `# pint disable ... until ...` comments set on this rule.
+	Snoozes []Snooze
+}
+
+func (r Rule) Name() string {
+	if r.RecordingRule != nil {
+		return r.RecordingRule.Record.Value.Value
+	}
+	if r.AlertingRule != nil {
+		return r.AlertingRule.Alert.Value.Value
+	}
+	return ""
 }
 
 func (r Rule) Expr() PromQLExpr {
@@ -382,6 +427,27 @@ func (r Rule) GetComment(comment ...string) (s Comment, ok bool) {
 	return
 }
 
+func (r Rule) comments() []string {
+	if r.RecordingRule != nil {
+		return r.RecordingRule.Comments()
+	}
+	if r.AlertingRule != nil {
+		return r.AlertingRule.Comments()
+	}
+	return nil
+}
+
+// IsSnoozed returns true if the rule has a snooze comment for given
+// match that is still active at the given time.
+func (r Rule) IsSnoozed(match string, now time.Time) bool {
+	for _, s := range r.Snoozes {
+		if s.Match == match && s.IsActive(now) {
+			return true
+		}
+	}
+	return false
+}
+
 type Result struct {
 	Path    string
 	Error   error
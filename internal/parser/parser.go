@@ -14,6 +14,10 @@ const (
 	alertKey       = "alert"
 	forKey         = "for"
 	annotationsKey = "annotations"
+	nameKey        = "name"
+	intervalKey    = "interval"
+	limitKey       = "limit"
+	rulesKey       = "rules"
 )
 
 func NewParser() Parser {
@@ -33,16 +37,32 @@ func (p Parser) Parse(content []byte) (rules []Rule, err error) {
 		}
 	}()
 
-	var node yaml.Node
-	err = yaml.Unmarshal(content, &node)
+	docs, err := decodeDocuments(content)
 	if err != nil {
 		return nil, err
 	}
 
-	return parseNode(content, &node, 0)
+	// If there are any PrometheusRule manifests then we only want to
+	// parse rules from their spec and ignore all other documents.
+	if specs, ok := prometheusRuleSpecs(docs); ok {
+		docs = specs
+	}
+
+	for _, doc := range docs {
+		ret, err := parseNode(content, doc, 0)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, ret...)
+	}
+	return rules, nil
 }
 
 func parseNode(content []byte, node *yaml.Node, offset int) (rules []Rule, err error) {
+	if isGroup(node) {
+		return parseGroup(content, node, offset)
+	}
+
 	ret, isEmpty, err := parseRule(content, node, offset)
 	if err != nil {
 		return nil, err
@@ -63,6 +83,14 @@ func parseNode(content []byte, node *yaml.Node, offset int) (rules []Rule, err e
 				rules = append(rules, ret...)
 			}
 		case yaml.MappingNode:
+			if isGroup(root) {
+				ret, err := parseGroup(content, root, offset)
+				if err != nil {
+					return nil, err
+				}
+				rules = append(rules, ret...)
+				continue
+			}
 			rule, isEmpty, err := parseRule(content, root, offset)
 			if err != nil {
 				return nil, err
@@ -96,6 +124,59 @@ func parseNode(content []byte, node *yaml.Node, offset int) (rules []Rule, err e
 	return rules, nil
 }
 
+func isGroup(node *yaml.Node) bool {
+	if node.Kind != yaml.MappingNode || !hasKey(node, nameKey) {
+		return false
+	}
+	for i := 1; i < len(node.Content); i += 2 {
+		if node.Content[i-1].Value == rulesKey {
+			return node.Content[i].Kind == yaml.SequenceNode
+		}
+	}
+	return false
+}
+
+func parseGroup(content []byte, node *yaml.Node, offset int) (rules []Rule, err error) {
+	group := RuleGroup{Comments: mergeComments(node)}
+
+	var rulesNode *yaml.Node
+	var key *yaml.Node
+	for i, part := range unpackNodes(node) {
+		if i%2 == 0 {
+			key = part
+			continue
+		}
+		switch key.Value {
+		case nameKey:
+			group.Name = *newYamlKeyValue(key, part, offset)
+		case intervalKey:
+			group.Interval = newYamlKeyValue(key, part, offset)
+		case limitKey:
+			group.Limit = newYamlKeyValue(key, part, offset)
+		case rulesKey:
+			// comments on the rules sequence belong to individual rules
+			rulesNode = part
+			group.Comments = append(group.Comments, mergeComments(key)...)
+			continue
+		}
+		group.Comments = append(group.Comments, mergeComments(key)...)
+		group.Comments = append(group.Comments, mergeComments(part)...)
+	}
+
+	for i, n := range rulesNode.Content {
+		ret, err := parseNode(content, n, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range ret {
+			rule.Group = &group
+			rule.GroupIndex = i
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
 func parseRule(content []byte, node *yaml.Node, offset int) (rule Rule, isEmpty bool, err error) {
 	isEmpty = true
 
@@ -244,6 +325,7 @@ func parseRule(content []byte, node *yaml.Node, offset int) (rule Rule, isEmpty
 			Expr:   *exprPart,
 			Labels: labelsPart,
 		}}
+		rule.Snoozes = findSnoozes(content, rule, commentsStart(node, offset), offset)
 		return
 	}
 
@@ -256,12 +338,23 @@ func parseRule(content []byte, node *yaml.Node, offset int) (rule Rule, isEmpty
 			Labels:      labelsPart,
 			Annotations: annotationsPart,
 		}}
+		rule.Snoozes = findSnoozes(content, rule, commentsStart(node, offset), offset)
 		return
 	}
 
 	return
 }
 
+// commentsStart returns the first line of given rule node, including any
+// comments placed directly above it.
+func commentsStart(node *yaml.Node, offset int) int {
+	line := node.Line + offset
+	if node.HeadComment != "" {
+		line -= len(strings.Split(node.HeadComment, "\n"))
+	}
+	return line
+}
+
 func unpackNodes(node *yaml.Node) []*yaml.Node {
 	nodes := make([]*yaml.Node, 0, len(node.Content))
 	var isMerge bool
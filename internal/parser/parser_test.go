@@ -477,6 +477,18 @@ groups:
 							},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{3}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{3}},
+								Value:    "custom_rules",
+							},
+						},
+					},
 				},
 			},
 			shouldError: false,
@@ -817,6 +829,18 @@ data:
 							},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{11}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{11}},
+								Value:    "example-app-alerts",
+							},
+						},
+					},
 				},
 				{
 					AlertingRule: &parser.AlertingRule{
@@ -874,6 +898,19 @@ data:
 							},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{11}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{11}},
+								Value:    "example-app-alerts",
+							},
+						},
+					},
+					GroupIndex: 1,
 				},
 			},
 		},
@@ -992,6 +1029,18 @@ data:
 							},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "haproxy.api_server.rules",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -1031,6 +1080,18 @@ data:
 							Query: &parser.PromQLNode{Expr: "expr1"},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "certmanager",
+							},
+						},
+					},
 				},
 				{
 					RecordingRule: &parser.RecordingRule{
@@ -1056,6 +1117,19 @@ data:
 							Query: &parser.PromQLNode{Expr: "expr2"},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "certmanager",
+							},
+						},
+					},
+					GroupIndex: 1,
 				},
 				{
 					RecordingRule: &parser.RecordingRule{
@@ -1081,6 +1155,19 @@ data:
 							Query: &parser.PromQLNode{Expr: "expr1"},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "certmanager",
+							},
+						},
+					},
+					GroupIndex: 2,
 				},
 			},
 		},
@@ -1151,6 +1238,18 @@ data:
 							},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "certmanager",
+							},
+						},
+					},
 				},
 				{
 					RecordingRule: &parser.RecordingRule{
@@ -1205,6 +1304,261 @@ data:
 							},
 						},
 					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "certmanager",
+							},
+						},
+					},
+					GroupIndex: 1,
+				},
+			},
+		},
+		{
+			content: []byte(`groups:
+# pint file/owner bob
+- name: foo
+  interval: 2m
+  limit: 10
+  rules:
+  - record: foo
+    expr: bar
+- name: bar
+  rules:
+  - alert: foo
+    expr: bar
+`),
+			output: []parser.Rule{
+				{
+					RecordingRule: &parser.RecordingRule{
+						Record: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{7}},
+								Value:    "record",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{7}},
+								Value:    "foo",
+							},
+						},
+						Expr: parser.PromQLExpr{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{8}},
+								Value:    "expr",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{8}},
+								Value:    "bar",
+							},
+							Query: &parser.PromQLNode{
+								Expr: "bar",
+							},
+						},
+					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{3}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{3}},
+								Value:    "foo",
+							},
+						},
+						Interval: &parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{4}},
+								Value:    "interval",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{4}},
+								Value:    "2m",
+							},
+						},
+						Limit: &parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{5}},
+								Value:    "limit",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{5}},
+								Value:    "10",
+							},
+						},
+						Comments: []string{"# pint file/owner bob"},
+					},
+				},
+				{
+					AlertingRule: &parser.AlertingRule{
+						Alert: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{11}},
+								Value:    "alert",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{11}},
+								Value:    "foo",
+							},
+						},
+						Expr: parser.PromQLExpr{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{12}},
+								Value:    "expr",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{12}},
+								Value:    "bar",
+							},
+							Query: &parser.PromQLNode{
+								Expr: "bar",
+							},
+						},
+					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{9}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{9}},
+								Value:    "bar",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			content: []byte(`---
+apiVersion: v1
+kind: ConfigMap
+data:
+  alerts: |
+    - record: configmap
+      expr: foo
+---
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: example
+spec:
+  groups:
+  - name: example
+    rules:
+    - record: prometheusrule
+      expr: bar
+`),
+			output: []parser.Rule{
+				{
+					RecordingRule: &parser.RecordingRule{
+						Record: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{17}},
+								Value:    "record",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{17}},
+								Value:    "prometheusrule",
+							},
+						},
+						Expr: parser.PromQLExpr{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{18}},
+								Value:    "expr",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{18}},
+								Value:    "bar",
+							},
+							Query: &parser.PromQLNode{
+								Expr: "bar",
+							},
+						},
+					},
+					Group: &parser.RuleGroup{
+						Name: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{15}},
+								Value:    "name",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{15}},
+								Value:    "example",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			content: []byte(`- record: first
+  expr: foo
+---
+- record: second
+  expr: bar
+`),
+			output: []parser.Rule{
+				{
+					RecordingRule: &parser.RecordingRule{
+						Record: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{1}},
+								Value:    "record",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{1}},
+								Value:    "first",
+							},
+						},
+						Expr: parser.PromQLExpr{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "expr",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{2}},
+								Value:    "foo",
+							},
+							Query: &parser.PromQLNode{
+								Expr: "foo",
+							},
+						},
+					},
+				},
+				{
+					RecordingRule: &parser.RecordingRule{
+						Record: parser.YamlKeyValue{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{4}},
+								Value:    "record",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{4}},
+								Value:    "second",
+							},
+						},
+						Expr: parser.PromQLExpr{
+							Key: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{5}},
+								Value:    "expr",
+							},
+							Value: &parser.YamlNode{
+								Position: parser.FilePosition{Lines: []int{5}},
+								Value:    "bar",
+							},
+							Query: &parser.PromQLNode{
+								Expr: "bar",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const snoozeSeparator = " until "
+
+// Snooze is a temporary `# pint disable <match> until <date> [reason]` comment.
+type Snooze struct {
+	// Line is the line number the comment was found on.
+	Line    int
+	Comment string
+	Match   string
+	Until   time.Time
+	Reason  string
+	Err     error
+}
+
+// IsActive returns true if the snooze didn't expire yet.
+func (s Snooze) IsActive(now time.Time) bool {
+	return s.Err == nil && now.Before(s.Until)
+}
+
+func parseSnoozeDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return t, fmt.Errorf("invalid snooze date %q, expected YYYY-MM-DD or RFC3339 timestamp", s)
+	}
+	return t, nil
+}
+
+func parseSnooze(text string) (s Snooze, ok bool) {
+	if !strings.HasPrefix(text, "disable ") {
+		return s, false
+	}
+	text = strings.TrimPrefix(text, "disable ")
+	idx := strings.Index(text, snoozeSeparator)
+	if idx < 0 {
+		return s, false
+	}
+	s.Match = text[:idx]
+	parts := strings.SplitN(text[idx+len(snoozeSeparator):], " ", 2)
+	s.Until, s.Err = parseSnoozeDate(parts[0])
+	if len(parts) > 1 {
+		s.Reason = parts[1]
+	}
+	return s, true
+}
+
+func getSnoozes(line string) (snoozes []Snooze) {
+	sc := bufio.NewScanner(strings.NewReader(line))
+	for sc.Scan() {
+		// Snooze reason can contain '#' so everything after the first
+		// "# pint" is used instead of the text after the last '#'.
+		elems := strings.Split(sc.Text(), "#")
+		for i := 1; i < len(elems); i++ {
+			parts := strings.SplitN(removeRedundantSpaces(strings.Join(elems[i:], "#")), " ", 2)
+			if len(parts) < 2 || parts[0] != "pint" {
+				continue
+			}
+			if s, ok := parseSnooze(parts[1]); ok {
+				s.Comment = parts[1]
+				snoozes = append(snoozes, s)
+			}
+			break
+		}
+	}
+	return snoozes
+}
+
+// findSnoozes returns all snooze comments set on given rule with the line
+// number of each comment. Lines are found by searching the lines of the rule,
+// including comments placed directly above and below it, for the first line
+// with the same snooze comment that wasn't already matched.
+// Line numbers in content start at offset+1.
+func findSnoozes(content []byte, rule Rule, start, offset int) (snoozes []Snooze) {
+	lines := strings.Split(string(content), "\n")
+	getLine := func(i int) (string, bool) {
+		if i-offset < 1 || i-offset > len(lines) {
+			return "", false
+		}
+		return lines[i-offset-1], true
+	}
+
+	lr := rule.LineRange()
+	if lr[0] < start {
+		start = lr[0]
+	}
+	// Include comments placed below the last line of the rule, but stop
+	// at comments that are not indented more than the rule itself, since
+	// those are placed on top of the next rule.
+	first, _ := getLine(lr[0])
+	indent := lineIndent(first)
+	end := lr[len(lr)-1]
+	for {
+		line, ok := getLine(end + 1)
+		if !ok {
+			break
+		}
+		if text := strings.TrimSpace(line); text != "" && (!strings.HasPrefix(text, "#") || lineIndent(line) <= indent) {
+			break
+		}
+		end++
+	}
+
+	used := map[int]struct{}{}
+	for _, c := range rule.comments() {
+		for _, s := range getSnoozes(c) {
+			s.Line = lr[0]
+			for i := start; i <= end; i++ {
+				if _, ok := used[i]; ok {
+					continue
+				}
+				line, ok := getLine(i)
+				if !ok {
+					continue
+				}
+				if found := getSnoozes(line); len(found) == 1 && found[0].Comment == s.Comment {
+					s.Line = i
+					used[i] = struct{}{}
+					break
+				}
+			}
+			snoozes = append(snoozes, s)
+		}
+	}
+	return snoozes
+}
+
+func lineIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
@@ -0,0 +1,190 @@
+package parser_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+func TestRuleSnoozes(t *testing.T) {
+	type testCaseT struct {
+		input   string
+		snoozes []parser.Snooze
+	}
+
+	testCases := []testCaseT{
+		{
+			input: "- record: foo\n  expr: sum(foo)\n",
+		},
+		{
+			input: "# pint disable promql/series\n- record: foo\n  expr: sum(foo)\n",
+		},
+		{
+			input: "# pint disable promql/series until 2022-07-01\n- record: foo\n  expr: sum(foo)\n",
+			snoozes: []parser.Snooze{
+				{
+					Line:    1,
+					Comment: "disable promql/series until 2022-07-01",
+					Match:   "promql/series",
+					Until:   time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			input: "- alert: foo\n  # pint disable promql/series(foo{job=\"bar\"})   until 2022-07-01T10:00:00Z   INC-1 broken exporter\n  expr: foo > 0\n",
+			snoozes: []parser.Snooze{
+				{
+					Line:    2,
+					Comment: `disable promql/series(foo{job="bar"}) until 2022-07-01T10:00:00Z INC-1 broken exporter`,
+					Match:   `promql/series(foo{job="bar"})`,
+					Until:   time.Date(2022, 7, 1, 10, 0, 0, 0, time.UTC),
+					Reason:  "INC-1 broken exporter",
+				},
+			},
+		},
+		{
+			input: "# pint disable promql/series until never\n- record: foo\n  expr: sum(foo)\n",
+			snoozes: []parser.Snooze{
+				{
+					Line:    1,
+					Comment: "disable promql/series until never",
+					Match:   "promql/series",
+					Err:     errors.New(`invalid snooze date "never", expected YYYY-MM-DD or RFC3339 timestamp`),
+				},
+			},
+		},
+		{
+			input: "# pint disable promql/series until 2022-07-01\n# pint disable promql/rate until 2022-07-01\n- record: foo\n  expr: sum(foo)\n  # pint disable promql/series until 2022-07-01\n  labels:\n    job: foo\n",
+			snoozes: []parser.Snooze{
+				{
+					Line:    1,
+					Comment: "disable promql/series until 2022-07-01",
+					Match:   "promql/series",
+					Until:   time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					Line:    2,
+					Comment: "disable promql/rate until 2022-07-01",
+					Match:   "promql/rate",
+					Until:   time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					Line:    5,
+					Comment: "disable promql/series until 2022-07-01",
+					Match:   "promql/series",
+					Until:   time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			input: "# pint disable promql/series until 2022-07-01 see issue #123\n- record: foo\n  expr: sum(foo)\n",
+			snoozes: []parser.Snooze{
+				{
+					Line:    1,
+					Comment: "disable promql/series until 2022-07-01 see issue #123",
+					Match:   "promql/series",
+					Until:   time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC),
+					Reason:  "see issue #123",
+				},
+			},
+		},
+		{
+			input: "- record: foo # pint disable promql/series until 2022-07-01 #123\n  expr: sum(foo)\n",
+			snoozes: []parser.Snooze{
+				{
+					Line:    1,
+					Comment: "disable promql/series until 2022-07-01 #123",
+					Match:   "promql/series",
+					Until:   time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC),
+					Reason:  "#123",
+				},
+			},
+		},
+	}
+
+	p := parser.NewParser()
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			rules, err := p.Parse([]byte(tc.input))
+			require.NoError(t, err)
+			require.Len(t, rules, 1)
+			require.Equal(t, tc.snoozes, rules[0].Snoozes)
+		})
+	}
+}
+
+func TestRuleSnoozeLines(t *testing.T) {
+	input := `- record: foo
+  expr: sum(foo)
+  # pint disable promql/series until 2022-07-01
+  labels:
+    job: foo
+- record: bar
+  expr: sum(bar)
+  labels:
+    job: bar
+  # pint disable promql/series until 2022-07-01
+
+# pint disable promql/series until 2022-07-01
+- record: baz
+  expr: sum(baz)
+`
+	p := parser.NewParser()
+	rules, err := p.Parse([]byte(input))
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+
+	lines := [][]int{}
+	for _, rule := range rules {
+		l := []int{}
+		for _, s := range rule.Snoozes {
+			l = append(l, s.Line)
+		}
+		lines = append(lines, l)
+	}
+	require.Equal(t, [][]int{{3}, {10}, {12}}, lines)
+}
+
+func TestRuleSnoozeLinesAlias(t *testing.T) {
+	input := `- &base
+  record: foo
+  # pint disable promql/series until 2022-07-01
+  expr: sum(foo)
+- <<: *base
+  labels:
+    job: bar
+- record: baz
+  # pint disable promql/series until 2022-07-01
+  expr: sum(baz)
+`
+	p := parser.NewParser()
+	rules, err := p.Parse([]byte(input))
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+
+	lines := [][]int{}
+	for _, rule := range rules {
+		l := []int{}
+		for _, s := range rule.Snoozes {
+			l = append(l, s.Line)
+		}
+		lines = append(lines, l)
+	}
+	require.Equal(t, [][]int{{3}, {3}, {9}}, lines)
+}
+
+func TestRuleIsSnoozed(t *testing.T) {
+	p := parser.NewParser()
+	rules, err := p.Parse([]byte("# pint disable promql/series until 2022-07-01\n- record: foo\n  expr: sum(foo)\n"))
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+
+	require.True(t, rules[0].IsSnoozed("promql/series", time.Date(2022, 6, 30, 0, 0, 0, 0, time.UTC)))
+	require.False(t, rules[0].IsSnoozed("promql/series", time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)))
+	require.False(t, rules[0].IsSnoozed("promql/rate", time.Date(2022, 6, 30, 0, 0, 0, 0, time.UTC)))
+}
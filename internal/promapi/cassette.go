@@ -0,0 +1,124 @@
+package promapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/rs/zerolog/log"
+)
+
+type CassetteMode int
+
+const (
+	// CassetteRecord will save all responses to disk.
+	CassetteRecord CassetteMode = iota
+	// CassetteReplay will only use saved responses and never send any query.
+	CassetteReplay
+)
+
+// Cassette records Prometheus responses to a directory and replays them later,
+// which allows to run online checks without any network access.
+// Each response is stored in a file named after the cache key of the query
+// that produced it.
+type Cassette struct {
+	dir      string
+	mode     CassetteMode
+	mu       sync.Mutex
+	recorded map[string]struct{}
+}
+
+func NewCassette(dir string, mode CassetteMode) *Cassette {
+	return &Cassette{dir: dir, mode: mode, recorded: map[string]struct{}{}}
+}
+
+type cassetteEntry struct {
+	RecordedAt time.Time       `json:"recordedAt"`
+	Query      string          `json:"query"`
+	ErrorType  v1.ErrorType    `json:"errorType,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Result     json.RawMessage `json:"result,omitempty"`
+}
+
+func (c *Cassette) isReplaying() bool {
+	return c != nil && c.mode == CassetteReplay
+}
+
+func (c *Cassette) path(prom, key string) string {
+	return filepath.Join(c.dir, prom, key+".json")
+}
+
+func (c *Cassette) record(prom, key, query string, result any, err error) {
+	if c == nil || c.mode != CassetteRecord {
+		return
+	}
+	// Don't record connection errors, so replaying will fail over to the
+	// next server, same as it did when recording.
+	if err != nil && IsUnavailableError(err) {
+		return
+	}
+
+	path := c.path(prom, key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.recorded[path]; ok {
+		return
+	}
+	c.recorded[path] = struct{}{}
+
+	entry := cassetteEntry{RecordedAt: time.Now().UTC(), Query: query}
+	var apiErr *v1.Error
+	if errors.As(err, &apiErr) {
+		entry.ErrorType = apiErr.Type
+		entry.Error = apiErr.Msg
+	} else if entry.Result, err = json.Marshal(result); err != nil {
+		log.Error().Err(err).Str("query", query).Msg("Failed to encode Prometheus response")
+		return
+	}
+
+	content, err := json.MarshalIndent(entry, "", "  ")
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+			err = os.WriteFile(path, content, 0o644)
+		}
+	}
+	if err != nil {
+		log.Error().Err(err).Str("path", path).Msg("Failed to record Prometheus response")
+		return
+	}
+	log.Debug().Str("path", path).Str("query", query).Msg("Recorded Prometheus response")
+}
+
+// replay decodes the recorded response into result and returns the time
+// at which it was recorded.
+func (c *Cassette) replay(prom, key, query string, result any) (time.Time, error) {
+	path := c.path(prom, key)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		log.Warn().Str("path", path).Str("query", query).Msg("No recorded Prometheus response")
+		return time.Time{}, fmt.Errorf("no recorded response for %q", query)
+	}
+
+	var entry cassetteEntry
+	if err = json.Unmarshal(content, &entry); err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode recorded response from %s: %w", path, err)
+	}
+
+	log.Debug().Str("path", path).Str("query", query).Msg("Replaying recorded Prometheus response")
+
+	if entry.ErrorType != "" {
+		return entry.RecordedAt, &v1.Error{Type: entry.ErrorType, Msg: entry.Error}
+	}
+	if err = json.Unmarshal(entry.Result, result); err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode recorded response from %s: %w", path, err)
+	}
+	return entry.RecordedAt, nil
+}
@@ -0,0 +1,119 @@
+package promapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestCassette(t *testing.T) {
+	var requests int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		err := r.ParseForm()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/query":
+			switch r.Form.Get("query") {
+			case "foo":
+				w.WriteHeader(200)
+				_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"job":"foo"},"value":[1614859502.068,"1"]}]}}`))
+			case "down":
+				w.WriteHeader(500)
+				_, _ = w.Write([]byte("fake error\n"))
+			default:
+				w.WriteHeader(400)
+				_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"unhandled query"}`))
+			}
+		case "/api/v1/query_range":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"job":"foo"},"values":[[1614859502,"1"],[1614859562,"2"]]}]}}`))
+		case "/api/v1/metadata":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":{"foo":[{"type":"gauge","help":"Text","unit":""}]}}`))
+		case "/api/v1/status/config":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":{"yaml":"global:\n  scrape_interval: 30s\n"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	rangeTimes := promapi.NewAbsoluteRange(time.Unix(1614859500, 0), time.Unix(1614859600, 0), time.Minute)
+
+	rec := promapi.NewPrometheus("prom", srv.URL, nil, time.Second, 1, nil)
+	rec.SetCassette(promapi.NewCassette(dir, promapi.CassetteRecord))
+	rec.StartWorkers()
+	defer rec.Close()
+
+	qr, err := rec.Query(ctx, "foo")
+	require.NoError(t, err)
+	_, err = rec.Query(ctx, "bar")
+	require.EqualError(t, err, "bad_data: unhandled query")
+	_, err = rec.Query(ctx, "down")
+	require.Error(t, err)
+	rqr, err := rec.RangeQuery(ctx, "foo", rangeTimes)
+	require.NoError(t, err)
+	mr, err := rec.Metadata(ctx, "foo")
+	require.NoError(t, err)
+	cr, err := rec.Config(ctx)
+	require.NoError(t, err)
+
+	sent := atomic.LoadInt64(&requests)
+
+	rep := promapi.NewPrometheus("prom", "http://replay.invalid", nil, time.Second, 1, nil)
+	rep.SetCassette(promapi.NewCassette(dir, promapi.CassetteReplay))
+	rep.StartWorkers()
+	defer rep.Close()
+
+	qr2, err := rep.Query(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, "http://replay.invalid", qr2.URI)
+	require.Len(t, qr2.Series, 1)
+	require.Equal(t, qr.Series[0].Metric, qr2.Series[0].Metric)
+	require.Equal(t, qr.Series[0].Value, qr2.Series[0].Value)
+	require.False(t, qr2.Series[0].Timestamp.Before(qr.Series[0].Timestamp))
+
+	_, err = rep.Query(ctx, "bar")
+	require.EqualError(t, err, "bad_data: unhandled query")
+	var apiErr *v1.Error
+	require.True(t, errors.As(err, &apiErr))
+
+	_, err = rep.Query(ctx, "down")
+	require.EqualError(t, err, `no recorded response for "down"`)
+	require.True(t, promapi.IsUnavailableError(err))
+
+	rqr2, err := rep.RangeQuery(ctx, "foo", rangeTimes)
+	require.NoError(t, err)
+	require.Len(t, rqr2.Samples, 1)
+	require.Equal(t, rqr.Samples[0].Metric, rqr2.Samples[0].Metric)
+	require.Len(t, rqr2.Samples[0].Values, 2)
+	require.Equal(t, model.SampleValue(2), rqr2.Samples[0].Values[1].Value)
+	require.False(t, rqr2.Start.Before(rqr.Start))
+
+	mr2, err := rep.Metadata(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, mr.Metadata, mr2.Metadata)
+
+	cr2, err := rep.Config(ctx)
+	require.NoError(t, err)
+	require.Equal(t, cr.Config, cr2.Config)
+	require.Equal(t, time.Second*30, cr2.Config.Global.ScrapeInterval)
+
+	require.Equal(t, sent, atomic.LoadInt64(&requests), "replay mode sent queries to the server")
+}
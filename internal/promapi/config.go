@@ -59,10 +59,37 @@ func (q configQuery) String() string {
 func (q configQuery) CacheKey() string {
 	h := sha1.New()
 	_, _ = io.WriteString(h, q.Endpoint())
+	writeTenantCacheKey(q.ctx, h)
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
 
 func (p *Prometheus) Config(ctx context.Context) (*ConfigResult, error) {
+	q := configQuery{ctx: ctx}
+	if p.cassette.isReplaying() {
+		var r ConfigResult
+		if _, err := p.cassette.replay(p.name, q.CacheKey(), q.String(), &r); err != nil {
+			return nil, QueryError{err: err, msg: decodeError(err)}
+		}
+		r.URI = p.uri
+		return &r, nil
+	}
+
+	var cached ConfigResult
+	if p.diskCache.get(p.name, p.uri, q.Endpoint(), q.CacheKey(), &cached) {
+		cached.URI = p.uri
+		p.cassette.record(p.name, q.CacheKey(), q.String(), cached, nil)
+		return &cached, nil
+	}
+
+	r, err := p.config(ctx)
+	p.cassette.record(p.name, q.CacheKey(), q.String(), r, err)
+	if err == nil {
+		p.diskCache.set(p.name, p.uri, q.Endpoint(), q.CacheKey(), r)
+	}
+	return r, err
+}
+
+func (p *Prometheus) config(ctx context.Context) (*ConfigResult, error) {
 	log.Debug().Str("uri", p.uri).Msg("Scheduling Prometheus configuration query")
 
 	resultChan := make(chan queryResult)
@@ -122,7 +122,7 @@ func TestConfig(t *testing.T) {
 		t.Run(strings.TrimPrefix(tc.prefix, "/"), func(t *testing.T) {
 			assert := assert.New(t)
 
-			prom := promapi.NewPrometheus("test", srv.URL+tc.prefix, tc.timeout, 1)
+			prom := promapi.NewPrometheus("test", srv.URL+tc.prefix, nil, tc.timeout, 1, nil)
 			prom.StartWorkers()
 			defer prom.Close()
 
@@ -0,0 +1,232 @@
+package promapi
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DiskCache stores query results in a directory, so they can be reused
+// by multiple pint runs. Results are only stored for successful queries
+// and are valid for a TTL configured per API endpoint.
+// When the size of all stored results exceeds maxSize the oldest ones are removed.
+type DiskCache struct {
+	dir     string
+	maxSize int64
+	ttls    map[string]time.Duration
+
+	mu      sync.Mutex
+	scanned bool
+	size    int64
+}
+
+func NewDiskCache(dir string, maxSize int64, ttls map[string]time.Duration) *DiskCache {
+	return &DiskCache{dir: dir, maxSize: maxSize, ttls: ttls}
+}
+
+type diskCacheEntry struct {
+	Created time.Time       `json:"created"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// path returns the path of the file storing results for given query key.
+// Entries are stored per Prometheus server name, and the server URI is part
+// of the file name, so changing the URI of a server never returns results
+// cached for the old one.
+func (dc *DiskCache) path(prom, uri, key string) string {
+	h := sha256.New()
+	_, _ = h.Write([]byte(uri))
+	_, _ = h.Write([]byte{'\n'})
+	_, _ = h.Write([]byte(key))
+	return filepath.Join(dc.dir, prom, fmt.Sprintf("%x.json", h.Sum(nil)))
+}
+
+// get decodes stored result into result and returns true if there's
+// a valid entry for given key.
+func (dc *DiskCache) get(prom, uri, endpoint, key string, result any) bool {
+	if dc == nil {
+		return false
+	}
+
+	ttl, ok := dc.ttls[endpoint]
+	if !ok || ttl <= 0 {
+		return false
+	}
+
+	path := dc.path(prom, uri, key)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	var entry diskCacheEntry
+	if err = json.Unmarshal(content, &entry); err != nil || time.Since(entry.Created) > ttl {
+		dc.remove(path)
+		return false
+	}
+
+	if err = json.Unmarshal(entry.Result, result); err != nil {
+		log.Warn().Err(err).Str("path", path).Msg("Failed to decode cached query result")
+		dc.remove(path)
+		return false
+	}
+
+	prometheusCacheHitsTotal.WithLabelValues(prom, endpoint).Inc()
+	log.Debug().Str("path", path).Str("endpoint", endpoint).Msg("Disk cache hit")
+	return true
+}
+
+func (dc *DiskCache) set(prom, uri, endpoint, key string, result any) {
+	if dc == nil {
+		return
+	}
+
+	if ttl, ok := dc.ttls[endpoint]; !ok || ttl <= 0 {
+		return
+	}
+
+	var err error
+	entry := diskCacheEntry{Created: time.Now()}
+	if entry.Result, err = json.Marshal(result); err != nil {
+		log.Warn().Err(err).Str("endpoint", endpoint).Msg("Failed to encode query result for disk cache")
+		return
+	}
+	content, err := json.Marshal(entry)
+	if err != nil {
+		log.Warn().Err(err).Str("endpoint", endpoint).Msg("Failed to encode query result for disk cache")
+		return
+	}
+
+	path := dc.path(prom, uri, key)
+
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	dc.scan()
+
+	var oldSize int64
+	if fi, err := os.Stat(path); err == nil {
+		oldSize = fi.Size()
+	}
+
+	// Write to a temporary file first, so other pint processes never
+	// read partially written entries.
+	if err = writeFileAtomic(path, content); err != nil {
+		log.Warn().Err(err).Str("path", path).Msg("Failed to write query result to disk cache")
+		return
+	}
+
+	dc.size += int64(len(content)) - oldSize
+	if dc.maxSize > 0 && dc.size > dc.maxSize {
+		dc.trim()
+	}
+}
+
+// writeFileAtomic writes content to a uniquely named temporary file in the
+// same directory as path and then renames it to path.
+func writeFileAtomic(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	_, err = f.Write(content)
+	if err == nil {
+		err = f.Chmod(0o644)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+	}
+	return err
+}
+
+func (dc *DiskCache) remove(path string) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	if err = os.Remove(path); err == nil && dc.scanned {
+		dc.size -= fi.Size()
+	}
+}
+
+type diskCacheFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+func (dc *DiskCache) files() (files []diskCacheFile) {
+	_ = filepath.WalkDir(dc.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, diskCacheFile{path: path, size: fi.Size(), modTime: fi.ModTime()})
+		return nil
+	})
+	return files
+}
+
+// scan calculates the size of all entries already stored in the cache directory.
+// Must be called with mu held.
+func (dc *DiskCache) scan() {
+	if dc.scanned {
+		return
+	}
+	dc.scanned = true
+	for _, f := range dc.files() {
+		dc.size += f.size
+	}
+	log.Debug().Str("dir", dc.dir).Int64("size", dc.size).Msg("Scanned disk cache")
+}
+
+// trim removes the oldest entries until the cache is below maxSize.
+// Must be called with mu held.
+func (dc *DiskCache) trim() {
+	files := dc.files()
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	dc.size = 0
+	for _, f := range files {
+		dc.size += f.size
+	}
+
+	var removed int
+	for _, f := range files {
+		if dc.size <= dc.maxSize {
+			break
+		}
+		if err := os.Remove(f.path); err != nil {
+			continue
+		}
+		dc.size -= f.size
+		removed++
+	}
+	log.Debug().Str("dir", dc.dir).Int("removed", removed).Int64("size", dc.size).Msg("Trimmed disk cache")
+}
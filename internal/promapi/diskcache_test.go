@@ -0,0 +1,210 @@
+package promapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestDiskCache(t *testing.T) {
+	var requests int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/query":
+			if r.Form.Get("query") == "error" {
+				w.WriteHeader(400)
+				_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"unhandled query"}`))
+				return
+			}
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"job":"foo"},"value":[1614859502.068,"1"]}]}}`))
+		case "/api/v1/query_range":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"job":"foo"},"values":[[1614859502,"1"],[1614859562,"2"]]}]}}`))
+		case "/api/v1/metadata":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":{"foo":[{"type":"gauge","help":"Text","unit":""}]}}`))
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	ttls := map[string]time.Duration{
+		"/api/v1/query":       time.Hour,
+		"/api/v1/query/range": time.Hour,
+	}
+	rangeTimes := promapi.NewAbsoluteRange(time.Unix(1614859500, 0), time.Unix(1614859600, 0), time.Minute)
+
+	run := func() {
+		prom := promapi.NewPrometheus("prom", srv.URL, nil, time.Second, 1, nil)
+		prom.SetDiskCache(promapi.NewDiskCache(dir, 0, ttls))
+		prom.StartWorkers()
+		defer prom.Close()
+
+		qr, err := prom.Query(ctx, "foo")
+		require.NoError(t, err)
+		require.Equal(t, srv.URL, qr.URI)
+		require.Len(t, qr.Series, 1)
+
+		_, err = prom.Query(ctx, "error")
+		require.EqualError(t, err, "bad_data: unhandled query")
+
+		rqr, err := prom.RangeQuery(ctx, "foo", rangeTimes)
+		require.NoError(t, err)
+		require.Len(t, rqr.Samples, 1)
+		require.Len(t, rqr.Samples[0].Values, 2)
+
+		mr, err := prom.Metadata(ctx, "foo")
+		require.NoError(t, err)
+		require.Len(t, mr.Metadata, 1)
+	}
+
+	run()
+	require.Equal(t, int64(4), atomic.LoadInt64(&requests))
+
+	// query and range query results are reused, errors and metadata are never stored
+	run()
+	require.Equal(t, int64(6), atomic.LoadInt64(&requests))
+
+	entries, err := os.ReadDir(filepath.Join(dir, "prom"))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
+
+func TestDiskCacheExpired(t *testing.T) {
+	var requests int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	dc := promapi.NewDiskCache(dir, 0, map[string]time.Duration{"/api/v1/query": time.Millisecond * 50})
+
+	for i := 0; i < 2; i++ {
+		prom := promapi.NewPrometheus("prom", srv.URL, nil, time.Second, 1, nil)
+		prom.SetDiskCache(dc)
+		prom.StartWorkers()
+		_, err := prom.Query(context.Background(), "foo")
+		require.NoError(t, err)
+		prom.Close()
+		time.Sleep(time.Millisecond * 100)
+	}
+	require.Equal(t, int64(2), atomic.LoadInt64(&requests))
+}
+
+func TestDiskCacheRelativeRange(t *testing.T) {
+	var requests int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		// Return a single sample at the start of each queried slice.
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"job":"foo"},"values":[[` + r.Form.Get("start") + `,"1"]]}]}}`))
+	}))
+	defer srv.Close()
+
+	dc := promapi.NewDiskCache(t.TempDir(), 0, map[string]time.Duration{"/api/v1/query/range": time.Hour})
+
+	var results []*promapi.RangeQueryResult
+	for i := 0; i < 2; i++ {
+		prom := promapi.NewPrometheus("prom", srv.URL, nil, time.Second, 1, nil)
+		prom.SetDiskCache(dc)
+		prom.StartWorkers()
+		rqr, err := prom.RangeQuery(context.Background(), "foo", promapi.NewRelativeRange(time.Minute*10, time.Minute))
+		require.NoError(t, err)
+		require.Len(t, rqr.Samples, 1)
+		require.NotEmpty(t, rqr.Samples[0].Values)
+		results = append(results, rqr)
+		prom.Close()
+		time.Sleep(time.Millisecond * 20)
+	}
+	require.Equal(t, int64(2), atomic.LoadInt64(&requests), "range query wasn't cached")
+
+	// Cached result is moved to the current range.
+	require.True(t, results[1].Start.After(results[0].Start), "cached range wasn't moved")
+	require.InDelta(t, results[0].End.Sub(results[0].Start), results[1].End.Sub(results[1].Start), float64(time.Millisecond))
+	for _, rqr := range results {
+		require.InDelta(t, 0, rqr.Samples[0].Values[0].Timestamp.Time().Sub(rqr.Start), float64(time.Millisecond*2))
+	}
+}
+
+func TestDiskCacheServerURI(t *testing.T) {
+	newServer := func(requests *int64) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt64(requests, 1)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		}))
+	}
+
+	var requests1, requests2 int64
+	srv1 := newServer(&requests1)
+	defer srv1.Close()
+	srv2 := newServer(&requests2)
+	defer srv2.Close()
+
+	dc := promapi.NewDiskCache(t.TempDir(), 0, map[string]time.Duration{"/api/v1/query": time.Hour})
+
+	// Both servers use the same name, so only the URI tells their results apart.
+	for _, uri := range []string{srv1.URL, srv2.URL, srv1.URL, srv2.URL} {
+		prom := promapi.NewPrometheus("prom", uri, nil, time.Second, 1, nil)
+		prom.SetDiskCache(dc)
+		prom.StartWorkers()
+		qr, err := prom.Query(context.Background(), "foo")
+		require.NoError(t, err)
+		require.Equal(t, uri, qr.URI)
+		prom.Close()
+	}
+	require.Equal(t, int64(1), atomic.LoadInt64(&requests1))
+	require.Equal(t, int64(1), atomic.LoadInt64(&requests2))
+}
+
+func TestDiskCacheMaxSize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"job":"foo"},"value":[1614859502.068,"1"]}]}}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	prom := promapi.NewPrometheus("prom", srv.URL, nil, time.Second, 1, nil)
+	prom.SetDiskCache(promapi.NewDiskCache(dir, 500, map[string]time.Duration{"/api/v1/query": time.Hour}))
+	prom.StartWorkers()
+	defer prom.Close()
+
+	for _, q := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		_, err := prom.Query(context.Background(), q)
+		require.NoError(t, err)
+	}
+
+	var size int64
+	entries, err := os.ReadDir(filepath.Join(dir, "prom"))
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	for _, e := range entries {
+		fi, err := e.Info()
+		require.NoError(t, err)
+		size += fi.Size()
+	}
+	require.LessOrEqual(t, size, int64(500))
+}
@@ -2,6 +2,7 @@ package promapi
 
 import (
 	"context"
+	"time"
 )
 
 type FailoverGroupError struct {
@@ -30,6 +31,8 @@ type FailoverGroup struct {
 	name         string
 	servers      []*Prometheus
 	strictErrors bool
+	tenant       *Tenant
+	retry        RetryConfig
 }
 
 func NewFailoverGroup(name string, servers []*Prometheus, strictErrors bool) *FailoverGroup {
@@ -44,6 +47,18 @@ func (fg *FailoverGroup) Name() string {
 	return fg.name
 }
 
+// WithTenant returns a copy of this group that will send all queries
+// for given tenant. Both groups share the same servers, workers and cache.
+func (fg *FailoverGroup) WithTenant(tenant Tenant) *FailoverGroup {
+	return &FailoverGroup{
+		name:         fg.name,
+		servers:      fg.servers,
+		strictErrors: fg.strictErrors,
+		tenant:       &tenant,
+		retry:        fg.retry,
+	}
+}
+
 func (fg *FailoverGroup) StartWorkers() {
 	for _, prom := range fg.servers {
 		prom.StartWorkers()
@@ -62,62 +77,114 @@ func (fg *FailoverGroup) Close() {
 	}
 }
 
-func (fg *FailoverGroup) Config(ctx context.Context) (cfg *ConfigResult, err error) {
-	var uri string
+// SetRetry enables retrying queries that failed with a transient error.
+func (fg *FailoverGroup) SetRetry(rc RetryConfig) {
+	fg.retry = rc
+}
+
+// SetCircuitBreaker makes all queries skip servers that failed more than
+// failures times in a row, until cooldown passes.
+func (fg *FailoverGroup) SetCircuitBreaker(failures int, cooldown time.Duration) {
+	for _, prom := range fg.servers {
+		prom.breaker = newCircuitBreaker(prom.name, prom.uri, failures, cooldown)
+	}
+}
+
+// run calls fn for every server in this group until one of them
+// returns a result or an error that isn't caused by the server being unavailable.
+func (fg *FailoverGroup) run(ctx context.Context, endpoint string, fn func(*Prometheus) error) (uri string, err error) {
 	for _, prom := range fg.servers {
+		if berr := prom.breaker.allow(); berr != nil {
+			if err == nil {
+				uri, err = prom.uri, berr
+			}
+			continue
+		}
 		uri = prom.uri
-		cfg, err = prom.Config(ctx)
-		if err == nil {
-			return
+		err = fg.retry.do(ctx, prom, endpoint, func() error { return fn(prom) })
+		if err == nil || !IsUnavailableError(err) {
+			prom.breaker.success()
+			return uri, err
 		}
-		if !IsUnavailableError(err) {
-			return cfg, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+		if ctx.Err() == nil {
+			prom.breaker.failure()
+		} else {
+			prom.breaker.cancel()
 		}
 	}
-	return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+	return uri, err
+}
+
+func (fg *FailoverGroup) Config(ctx context.Context) (cfg *ConfigResult, err error) {
+	ctx = withTenant(ctx, fg.tenant)
+	uri, err := fg.run(ctx, "/api/v1/status/config", func(prom *Prometheus) (err error) {
+		cfg, err = prom.Config(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+	}
+	return cfg, nil
 }
 
 func (fg *FailoverGroup) Query(ctx context.Context, expr string) (qr *QueryResult, err error) {
-	var uri string
-	for _, prom := range fg.servers {
-		uri = prom.uri
+	ctx = withTenant(ctx, fg.tenant)
+	uri, err := fg.run(ctx, "/api/v1/query", func(prom *Prometheus) (err error) {
 		qr, err = prom.Query(ctx, expr)
-		if err == nil {
-			return
-		}
-		if !IsUnavailableError(err) {
-			return qr, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
-		}
+		return err
+	})
+	if err != nil {
+		return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
 	}
-	return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+	return qr, nil
+}
+
+func (fg *FailoverGroup) QueryWithStats(ctx context.Context, expr string) (qr *QueryResult, err error) {
+	ctx = withTenant(ctx, fg.tenant)
+	uri, err := fg.run(ctx, "/api/v1/query", func(prom *Prometheus) (err error) {
+		qr, err = prom.QueryWithStats(ctx, expr)
+		return err
+	})
+	if err != nil {
+		return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+	}
+	return qr, nil
 }
 
 func (fg *FailoverGroup) RangeQuery(ctx context.Context, expr string, params RangeQueryTimes) (rqr *RangeQueryResult, err error) {
-	var uri string
-	for _, prom := range fg.servers {
-		uri = prom.uri
+	ctx = withTenant(ctx, fg.tenant)
+	uri, err := fg.run(ctx, "/api/v1/query/range", func(prom *Prometheus) (err error) {
 		rqr, err = prom.RangeQuery(ctx, expr, params)
-		if err == nil {
-			return
-		}
-		if !IsUnavailableError(err) {
-			return rqr, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
-		}
+		return err
+	})
+	if err != nil {
+		return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
 	}
-	return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+	return rqr, nil
 }
 
 func (fg *FailoverGroup) Metadata(ctx context.Context, metric string) (metadata *MetadataResult, err error) {
-	var uri string
-	for _, prom := range fg.servers {
-		uri = prom.uri
+	ctx = withTenant(ctx, fg.tenant)
+	uri, err := fg.run(ctx, "/api/v1/metadata", func(prom *Prometheus) (err error) {
 		metadata, err = prom.Metadata(ctx, metric)
-		if err == nil {
-			return
-		}
-		if !IsUnavailableError(err) {
-			return metadata, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
-		}
+		return err
+	})
+	if err != nil {
+		return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
+	}
+	return metadata, nil
+}
+
+// SetCassette enables recording or replaying of responses on all servers.
+func (fg *FailoverGroup) SetCassette(c *Cassette) {
+	for _, prom := range fg.servers {
+		prom.SetCassette(c)
+	}
+}
+
+// SetDiskCache enables persistent query cache on all servers.
+func (fg *FailoverGroup) SetDiskCache(dc *DiskCache) {
+	for _, prom := range fg.servers {
+		prom.SetDiskCache(dc)
 	}
-	return nil, &FailoverGroupError{err: err, uri: uri, isStrict: fg.strictErrors}
 }
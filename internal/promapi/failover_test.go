@@ -0,0 +1,67 @@
+package promapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestFailoverGroupWithTenant(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant := r.Header.Get("X-Scope-OrgID")
+		mu.Lock()
+		requests[tenant]++
+		mu.Unlock()
+
+		w.WriteHeader(200)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(fmt.Sprintf(`{
+			"status":"success",
+			"data":{
+				"resultType":"vector",
+				"result":[{"metric":{"tenant":%q},"value":[1614859502.068,"1"]}]
+			}
+		}`, tenant)))
+	}))
+	defer srv.Close()
+
+	fg := promapi.NewFailoverGroup("test", []*promapi.Prometheus{
+		promapi.NewPrometheus("test", srv.URL, nil, time.Second, 1, nil),
+	}, true)
+	fg.StartWorkers()
+	defer fg.Close()
+
+	team1 := fg.WithTenant(promapi.Tenant{Header: "X-Scope-OrgID", Value: "team1"})
+	team2 := fg.WithTenant(promapi.Tenant{Header: "X-Scope-OrgID", Value: "team2"})
+	require.Equal(t, "test", team1.Name())
+
+	for _, tc := range []struct {
+		fg     *promapi.FailoverGroup
+		tenant string
+	}{
+		{fg: fg, tenant: ""},
+		{fg: team1, tenant: "team1"},
+		{fg: team2, tenant: "team2"},
+		{fg: team1, tenant: "team1"},
+		{fg: fg, tenant: ""},
+	} {
+		qr, err := tc.fg.Query(context.Background(), "foo")
+		require.NoError(t, err)
+		require.Len(t, qr.Series, 1)
+		require.Equal(t, model.LabelValue(tc.tenant), qr.Series[0].Metric["tenant"])
+	}
+
+	// each tenant has a separate cache entry
+	require.Equal(t, map[string]int{"": 1, "team1": 1, "team2": 1}, requests)
+}
@@ -50,10 +50,37 @@ func (q metadataQuery) CacheKey() string {
 	_, _ = io.WriteString(h, q.Endpoint())
 	_, _ = io.WriteString(h, "\n")
 	_, _ = io.WriteString(h, q.metric)
+	writeTenantCacheKey(q.ctx, h)
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
 
 func (p *Prometheus) Metadata(ctx context.Context, metric string) (*MetadataResult, error) {
+	key := metadataQuery{ctx: ctx, metric: metric}.CacheKey()
+	if p.cassette.isReplaying() {
+		var metadata MetadataResult
+		if _, err := p.cassette.replay(p.name, key, metric, &metadata); err != nil {
+			return nil, QueryError{err: err, msg: decodeError(err)}
+		}
+		metadata.URI = p.uri
+		return &metadata, nil
+	}
+
+	var cached MetadataResult
+	if p.diskCache.get(p.name, p.uri, "/api/v1/metadata", key, &cached) {
+		cached.URI = p.uri
+		p.cassette.record(p.name, key, metric, cached, nil)
+		return &cached, nil
+	}
+
+	metadata, err := p.metadata(ctx, metric)
+	p.cassette.record(p.name, key, metric, metadata, err)
+	if err == nil {
+		p.diskCache.set(p.name, p.uri, "/api/v1/metadata", key, metadata)
+	}
+	return metadata, err
+}
+
+func (p *Prometheus) metadata(ctx context.Context, metric string) (*MetadataResult, error) {
 	log.Debug().Str("uri", p.uri).Str("metric", metric).Msg("Scheduling Prometheus metrics metadata query")
 
 	resultChan := make(chan queryResult)
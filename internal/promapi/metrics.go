@@ -38,6 +38,27 @@ var (
 		},
 		[]string{"name", "endpoint", "reason"},
 	)
+	prometheusQueryRetriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pint_prometheus_query_retries_total",
+			Help: "Total number of retried prometheus queries",
+		},
+		[]string{"name", "endpoint"},
+	)
+	prometheusCircuitBreakerOpen = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pint_prometheus_circuit_breaker_open",
+			Help: "Number of prometheus server URIs with an open circuit breaker, queries are not sent to those",
+		},
+		[]string{"name"},
+	)
+	prometheusCircuitBreakerTripsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pint_prometheus_circuit_breaker_trips_total",
+			Help: "Total number of times circuit breaker for prometheus server was opened",
+		},
+		[]string{"name"},
+	)
 )
 
 func RegisterMetrics() {
@@ -45,6 +66,9 @@ func RegisterMetrics() {
 	prometheus.MustRegister(prometheusCacheHitsTotal)
 	prometheus.MustRegister(prometheusQueriesTotal)
 	prometheus.MustRegister(prometheusQueryErrorsTotal)
+	prometheus.MustRegister(prometheusQueryRetriesTotal)
+	prometheus.MustRegister(prometheusCircuitBreakerOpen)
+	prometheus.MustRegister(prometheusCircuitBreakerTripsTotal)
 }
 
 func errReason(err error) string {
@@ -1,6 +1,9 @@
 package promapi
 
 import (
+	"crypto/tls"
+	"io"
+	"net/http"
 	"sync"
 	"time"
 
@@ -46,16 +49,28 @@ type Prometheus struct {
 	name        string
 	uri         string
 	api         v1.API
+	client      api.Client
 	timeout     time.Duration
 	concurrency int
 	cache       *lru.ARCCache
+	closer      io.Closer
+	cassette    *Cassette
+	diskCache   *DiskCache
+	breaker     *circuitBreaker
 
 	wg      sync.WaitGroup
 	queries chan queryRequest
 }
 
-func NewPrometheus(name, uri string, timeout time.Duration, concurrency int) *Prometheus {
-	client, err := api.NewClient(api.Config{Address: uri})
+func NewPrometheus(name, uri string, headers map[string]string, timeout time.Duration, concurrency int, tlsConf *tls.Config) *Prometheus {
+	transport := api.DefaultRoundTripper.(*http.Transport).Clone()
+	if tlsConf != nil {
+		transport.TLSClientConfig = tlsConf
+	}
+	client, err := api.NewClient(api.Config{
+		Address:      uri,
+		RoundTripper: headersRoundTripper{headers: headers, rt: transport},
+	})
 	if err != nil {
 		// config validation should prevent this from ever happening
 		// panic so we don't need to return an error and it's easier to
@@ -68,6 +83,7 @@ func NewPrometheus(name, uri string, timeout time.Duration, concurrency int) *Pr
 		name:        name,
 		uri:         uri,
 		api:         v1.NewAPI(client),
+		client:      client,
 		timeout:     timeout,
 		cache:       cache,
 		concurrency: concurrency,
@@ -75,10 +91,25 @@ func NewPrometheus(name, uri string, timeout time.Duration, concurrency int) *Pr
 	return &prom
 }
 
+// SetCassette enables recording or replaying of all responses.
+func (prom *Prometheus) SetCassette(c *Cassette) {
+	prom.cassette = c
+}
+
+// SetDiskCache enables storing query results in a persistent cache.
+func (prom *Prometheus) SetDiskCache(dc *DiskCache) {
+	prom.diskCache = dc
+}
+
 func (prom *Prometheus) Close() {
 	log.Debug().Str("name", prom.name).Str("uri", prom.uri).Msg("Stopping query workers")
 	close(prom.queries)
 	prom.wg.Wait()
+	if prom.closer != nil {
+		if err := prom.closer.Close(); err != nil {
+			log.Error().Err(err).Str("name", prom.name).Str("uri", prom.uri).Msg("Failed to close")
+		}
+	}
 }
 
 func (prom *Prometheus) StartWorkers() {
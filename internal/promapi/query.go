@@ -14,13 +14,15 @@ import (
 type QueryResult struct {
 	URI             string
 	Series          model.Vector
+	Stats           *QueryStats
 	DurationSeconds float64
 }
 
 type instantQuery struct {
-	prom *Prometheus
-	ctx  context.Context
-	expr string
+	prom  *Prometheus
+	ctx   context.Context
+	expr  string
+	stats bool
 }
 
 func (q instantQuery) Run() (any, error) {
@@ -32,6 +34,10 @@ func (q instantQuery) Run() (any, error) {
 	ctx, cancel := context.WithTimeout(q.ctx, q.prom.timeout)
 	defer cancel()
 
+	if q.stats {
+		return queryWithStats(ctx, q.prom.client, q.expr, time.Now())
+	}
+
 	v, _, err := q.prom.api.Query(ctx, q.expr, time.Now())
 	return v, err
 }
@@ -49,15 +55,60 @@ func (q instantQuery) CacheKey() string {
 	_, _ = io.WriteString(h, q.Endpoint())
 	_, _ = io.WriteString(h, "\n")
 	_, _ = io.WriteString(h, q.expr)
+	if q.stats {
+		_, _ = io.WriteString(h, "\nstats")
+	}
+	writeTenantCacheKey(q.ctx, h)
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
 
 func (p *Prometheus) Query(ctx context.Context, expr string) (*QueryResult, error) {
+	return p.instantQuery(ctx, expr, false)
+}
+
+// QueryWithStats works like Query but it will also ask Prometheus for query
+// statistics. Stats will be nil if Prometheus doesn't support them.
+func (p *Prometheus) QueryWithStats(ctx context.Context, expr string) (*QueryResult, error) {
+	return p.instantQuery(ctx, expr, true)
+}
+
+func (p *Prometheus) instantQuery(ctx context.Context, expr string, stats bool) (*QueryResult, error) {
+	key := instantQuery{ctx: ctx, expr: expr, stats: stats}.CacheKey()
+	if p.cassette.isReplaying() {
+		var qr QueryResult
+		recordedAt, err := p.cassette.replay(p.name, key, expr, &qr)
+		if err != nil {
+			return nil, QueryError{err: err, msg: decodeError(err)}
+		}
+		qr.URI = p.uri
+		offset := model.Time(time.Since(recordedAt).Milliseconds())
+		for _, s := range qr.Series {
+			s.Timestamp += offset
+		}
+		return &qr, nil
+	}
+
+	var cached QueryResult
+	if p.diskCache.get(p.name, p.uri, "/api/v1/query", key, &cached) {
+		cached.URI = p.uri
+		p.cassette.record(p.name, key, expr, cached, nil)
+		return &cached, nil
+	}
+
+	qr, err := p.query(ctx, expr, stats)
+	p.cassette.record(p.name, key, expr, qr, err)
+	if err == nil {
+		p.diskCache.set(p.name, p.uri, "/api/v1/query", key, qr)
+	}
+	return qr, err
+}
+
+func (p *Prometheus) query(ctx context.Context, expr string, stats bool) (*QueryResult, error) {
 	log.Debug().Str("uri", p.uri).Str("query", expr).Msg("Scheduling prometheus query")
 
 	resultChan := make(chan queryResult)
 	p.queries <- queryRequest{
-		query:  instantQuery{prom: p, ctx: ctx, expr: expr},
+		query:  instantQuery{prom: p, ctx: ctx, expr: expr, stats: stats},
 		result: resultChan,
 	}
 
@@ -68,6 +119,11 @@ func (p *Prometheus) Query(ctx context.Context, expr string) (*QueryResult, erro
 
 	qr := QueryResult{URI: p.uri}
 
+	if sr, ok := result.value.(statsResult); ok {
+		qr.Stats = sr.stats
+		result.value = sr.value
+	}
+
 	switch result.value.(model.Value).Type() {
 	case model.ValVector:
 		vectorVal := result.value.(model.Vector)
@@ -2,6 +2,7 @@ package promapi_test
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -188,7 +189,7 @@ func TestQuery(t *testing.T) {
 		t.Run(tc.query, func(t *testing.T) {
 			assert := assert.New(t)
 
-			prom := promapi.NewPrometheus("test", srv.URL, tc.timeout, 1)
+			prom := promapi.NewPrometheus("test", srv.URL, nil, tc.timeout, 1, nil)
 			prom.StartWorkers()
 			defer prom.Close()
 
@@ -205,3 +206,134 @@ func TestQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryHeadersAndTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("X-Scope-OrgID") != "team1" {
+			w.WriteHeader(401)
+			_, _ = w.Write([]byte("unauthorized"))
+			return
+		}
+		w.WriteHeader(200)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"status":"success",
+			"data":{
+				"resultType":"vector",
+				"result":[{"metric":{},"value":[1614859502.068,"1"]}]
+			}
+		}`))
+	}))
+	defer srv.Close()
+
+	type testCaseT struct {
+		name    string
+		headers map[string]string
+		tlsConf *tls.Config
+		err     bool
+	}
+
+	testCases := []testCaseT{
+		{
+			name:    "no headers",
+			tlsConf: &tls.Config{InsecureSkipVerify: true},
+			err:     true,
+		},
+		{
+			name:    "unverified certificate",
+			headers: map[string]string{"Authorization": "Bearer secret", "X-Scope-OrgID": "team1"},
+			err:     true,
+		},
+		{
+			name:    "headers and tls",
+			headers: map[string]string{"Authorization": "Bearer secret", "X-Scope-OrgID": "team1"},
+			tlsConf: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			prom := promapi.NewPrometheus("test", srv.URL, tc.headers, time.Second, 1, tc.tlsConf)
+			prom.StartWorkers()
+			defer prom.Close()
+
+			qr, err := prom.Query(context.Background(), "foo")
+			if tc.err {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Len(qr.Series, 1)
+			}
+		})
+	}
+}
+
+func TestQueryWithStats(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Form.Get("stats") != "all" {
+			w.WriteHeader(400)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"missing stats"}`))
+			return
+		}
+
+		switch r.Form.Get("query") {
+		case "stats":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{
+				"status":"success",
+				"data":{
+					"resultType":"vector",
+					"result":[{"metric":{},"value":[1614859502.068,"1"]}],
+					"stats":{
+						"timings":{"evalTotalTime":1.5,"execTotalTime":1.6},
+						"samples":{"totalQueryableSamples":1000,"peakSamples":200}
+					}
+				}
+			}`))
+		case "nostats":
+			w.WriteHeader(200)
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		case "overloaded":
+			w.WriteHeader(503)
+			_, _ = w.Write([]byte("overloaded\n"))
+		default:
+			w.WriteHeader(422)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"execution","error":"query failed"}`))
+		}
+	}))
+	defer srv.Close()
+
+	prom := promapi.NewPrometheus("test", srv.URL, nil, time.Second, 1, nil)
+	prom.StartWorkers()
+	defer prom.Close()
+
+	qr, err := prom.QueryWithStats(context.Background(), "stats")
+	assert.NoError(t, err)
+	assert.Len(t, qr.Series, 1)
+	assert.Equal(t, &promapi.QueryStats{
+		Timings: promapi.QueryTimings{EvalTotalTime: 1.5, ExecTotalTime: 1.6},
+		Samples: promapi.QuerySamples{TotalQueryableSamples: 1000, PeakSamples: 200},
+	}, qr.Stats)
+	assert.Equal(t, time.Millisecond*1500, qr.Stats.EvalDuration())
+
+	qr, err = prom.QueryWithStats(context.Background(), "nostats")
+	assert.NoError(t, err)
+	assert.Len(t, qr.Series, 0)
+	assert.Nil(t, qr.Stats)
+
+	_, err = prom.QueryWithStats(context.Background(), "overloaded")
+	assert.EqualError(t, err, "server_error: server error: 503")
+	assert.True(t, promapi.IsUnavailableError(err))
+
+	_, err = prom.QueryWithStats(context.Background(), "error")
+	assert.EqualError(t, err, "execution: query failed")
+	assert.False(t, promapi.IsUnavailableError(err))
+}
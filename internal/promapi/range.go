@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -22,6 +23,7 @@ type RangeQueryResult struct {
 	Samples         []*model.SampleStream
 	Start           time.Time
 	End             time.Time
+	Step            time.Duration
 	DurationSeconds float64
 }
 
@@ -94,8 +96,20 @@ func (p *Prometheus) RangeQuery(ctx context.Context, expr string, params RangeQu
 	_, _ = io.WriteString(h, expr)
 	_, _ = io.WriteString(h, "\n")
 	_, _ = io.WriteString(h, params.String())
+	writeTenantCacheKey(ctx, h)
 	cacheKey := fmt.Sprintf("%x", h.Sum(nil))
 
+	if p.cassette.isReplaying() {
+		var res RangeQueryResult
+		recordedAt, err := p.cassette.replay(p.name, cacheKey, expr, &res)
+		if err != nil {
+			return nil, QueryError{err: err, msg: decodeError(err)}
+		}
+		res.URI = p.uri
+		res.shift(time.Since(recordedAt))
+		return &res, nil
+	}
+
 	if cached, ok := p.cache.Get(cacheKey); ok {
 		prometheusCacheHitsTotal.WithLabelValues(p.name, "/api/v1/query/range").Inc()
 		log.Debug().
@@ -106,87 +120,239 @@ func (p *Prometheus) RangeQuery(ctx context.Context, expr string, params RangeQu
 		return &res, nil
 	}
 
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var lastErr error
+	var cached RangeQueryResult
+	if p.diskCache.get(p.name, p.uri, "/api/v1/query/range", cacheKey, &cached) {
+		cached.URI = p.uri
+		// Relative ranges move with time, so results cached by a previous
+		// pint run need to be moved to the current range.
+		cached.shift(start.Sub(cached.Start))
+		p.cache.Add(cacheKey, cached)
+		p.cassette.record(p.name, cacheKey, expr, cached, nil)
+		return &cached, nil
+	}
 
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
+	res := RangeQueryResult{URI: p.uri, Start: start, End: end, Step: step}
+	var err error
+	for i := 0; ; i++ {
+		res.Samples, err = p.rangeQuerySlices(ctx, expr, start, end, res.Step, queryStep)
+		if !errors.Is(err, errSliceTooSmall) || i >= rangeQueryMaxStepIncrease || res.Step*2 > lookback {
+			break
+		}
+		res.Step *= 2
+		queryStep = (queryStep * 2).Round(res.Step)
+		log.Warn().
+			Str("uri", p.uri).
+			Str("query", expr).
+			Str("step", output.HumanizeDuration(res.Step)).
+			Msg("Range query returned too many samples, retrying with a bigger step")
+	}
+	if err != nil {
+		p.cassette.record(p.name, cacheKey, expr, nil, err)
+		return nil, QueryError{err: err, msg: decodeError(err)}
+	}
+
+	for k := range res.Samples {
+		sort.SliceStable(res.Samples[k].Values, func(i, j int) bool {
+			return res.Samples[k].Values[i].Timestamp.Before(res.Samples[k].Values[j].Timestamp)
+		})
+	}
 
-	res := RangeQueryResult{URI: p.uri, Start: start, End: end}
-	for _, s := range sliceRange(start, end, step, queryStep) {
-		query := queryRequest{
-			query: rangeQuery{
-				prom: p,
-				ctx:  ctx,
-				expr: expr,
-				r: v1.Range{
-					Start: s.start,
-					End:   s.end,
-					Step:  step,
-				},
-			},
+	log.Debug().Str("uri", p.uri).Str("query", expr).Int("samples", len(res.Samples)).Msg("Parsed range response")
+
+	p.cache.Add(cacheKey, res)
+	p.cassette.record(p.name, cacheKey, expr, res, nil)
+	p.diskCache.set(p.name, p.uri, "/api/v1/query/range", cacheKey, res)
+
+	return &res, nil
+}
+
+// shift moves the range and all sample timestamps by given offset.
+func (r *RangeQueryResult) shift(offset time.Duration) {
+	if offset == 0 {
+		return
+	}
+	r.Start = r.Start.Add(offset)
+	r.End = r.End.Add(offset)
+	for _, s := range r.Samples {
+		for i := range s.Values {
+			s.Values[i].Timestamp = s.Values[i].Timestamp.Add(offset)
 		}
+	}
+}
+
+const (
+	// Slices returning fewer samples than this will be doubled in size.
+	rangeQueryGrowSamples = 100000
+	// Prometheus rejects range queries with more than 11000 points per series.
+	rangeQueryMaxPoints = 10000
+	// How many times the step can be doubled before giving up.
+	rangeQueryMaxStepIncrease = 4
+)
+
+// errSliceTooSmall is returned when a range query slice is too big for
+// Prometheus limits but cannot be split any further.
+var errSliceTooSmall = errors.New("range query slice cannot be split")
+
+type sliceTooSmallError struct {
+	err error
+}
+
+func (e sliceTooSmallError) Error() string {
+	return e.err.Error()
+}
+
+func (e sliceTooSmallError) Unwrap() error {
+	return e.err
+}
 
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
+func (e sliceTooSmallError) Is(target error) bool {
+	return target == errSliceTooSmall
+}
+
+// isTooLargeError returns true if the query was rejected because it would load
+// too many samples (--query.max-samples) or return too many points per series.
+func isTooLargeError(err error) bool {
+	var apiErr *v1.Error
+	if ok := errors.As(err, &apiErr); ok {
+		return strings.Contains(apiErr.Msg, "query processing would load too many samples") ||
+			strings.Contains(apiErr.Msg, "exceeded maximum resolution")
+	}
+	return false
+}
+
+type sliceResult struct {
+	tr     timeRange
+	matrix model.Matrix
+	err    error
+}
 
-			var result queryResult
-			query.result = make(chan queryResult)
-			p.queries <- query
-			result = <-query.result
+// rangeQuerySlices runs a range query split into multiple slices.
+// Slices that fail because of Prometheus query limits are split in half
+// and retried, slices returning only a few samples make the following
+// slices bigger.
+func (p *Prometheus) rangeQuerySlices(ctx context.Context, expr string, start, end time.Time, step, sliceSize time.Duration) (samples []*model.SampleStream, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	maxSliceSize := step * rangeQueryMaxPoints
+	if sliceSize > maxSliceSize {
+		sliceSize = maxSliceSize
+	}
 
-			mu.Lock()
-			defer mu.Unlock()
+	// Once a slice fails we'll never grow slices back to its size.
+	failedSize := maxSliceSize * 2
 
-			if result.err != nil {
-				if !errors.Is(result.err, context.Canceled) {
-					lastErr = result.err
+	pending := sliceRange(start, end, step, sliceSize)
+	for len(pending) > 0 {
+		batch := pending
+		if len(batch) > p.concurrency {
+			batch = pending[:p.concurrency]
+		}
+		pending = pending[len(batch):]
+
+		results := make([]sliceResult, len(batch))
+		var wg sync.WaitGroup
+		for i, tr := range batch {
+			i, tr := i, tr
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				query := queryRequest{
+					query: rangeQuery{
+						prom: p,
+						ctx:  ctx,
+						expr: expr,
+						r:    v1.Range{Start: tr.start, End: tr.end, Step: step},
+					},
+					result: make(chan queryResult),
+				}
+				p.queries <- query
+				result := <-query.result
+				results[i] = sliceResult{tr: tr, err: result.err}
+				if result.err != nil {
+					return
+				}
+				switch result.value.(model.Value).Type() {
+				case model.ValMatrix:
+					results[i].matrix = result.value.(model.Matrix)
+				default:
+					log.Error().Str("uri", p.uri).Str("query", expr).Msgf("Range query returned unknown result type: %v", result.value.(model.Value).Type())
+					results[i].err = fmt.Errorf("unknown result type: %v", result.value.(model.Value).Type())
+				}
+			}()
+		}
+		wg.Wait()
+
+		var tooLarge []sliceResult
+		var maxSamples int
+		for _, r := range results {
+			if r.err != nil {
+				if isTooLargeError(r.err) {
+					tooLarge = append(tooLarge, r)
+					continue
 				}
-				cancel()
-				return
+				return nil, r.err
 			}
 
-			switch result.value.(model.Value).Type() {
-			case model.ValMatrix:
-				for _, sample := range result.value.(model.Matrix) {
-					var found bool
-					for i, rs := range res.Samples {
-						if sample.Metric.Equal(rs.Metric) {
-							found = true
-							res.Samples[i].Values = append(res.Samples[i].Values, sample.Values...)
-							break
-						}
-					}
-					if !found {
-						res.Samples = append(res.Samples, sample)
+			var count int
+			for _, sample := range r.matrix {
+				count += len(sample.Values)
+				var found bool
+				for i, rs := range samples {
+					if sample.Metric.Equal(rs.Metric) {
+						found = true
+						samples[i].Values = append(samples[i].Values, sample.Values...)
+						break
 					}
 				}
-			default:
-				log.Error().Str("uri", p.uri).Str("query", expr).Msgf("Range query returned unknown result type: %v", result.value.(model.Value).Type())
-				lastErr = fmt.Errorf("unknown result type: %v", result.value.(model.Value).Type())
-				return
+				if !found {
+					samples = append(samples, sample)
+				}
 			}
-		}()
-	}
-	wg.Wait()
-
-	if lastErr != nil {
-		return nil, QueryError{err: lastErr, msg: decodeError(lastErr)}
-	}
+			if count > maxSamples {
+				maxSamples = count
+			}
+		}
 
-	for k := range res.Samples {
-		sort.SliceStable(res.Samples[k].Values, func(i, j int) bool {
-			return res.Samples[k].Values[i].Timestamp.Before(res.Samples[k].Values[j].Timestamp)
-		})
+		switch {
+		case len(tooLarge) > 0:
+			if sliceSize < failedSize {
+				failedSize = sliceSize
+			}
+			sliceSize = (sliceSize / 2).Round(step)
+			if sliceSize < step {
+				sliceSize = step
+			}
+			var split []timeRange
+			for _, r := range tooLarge {
+				parts := sliceRange(r.tr.start, r.tr.end, step, sliceSize)
+				if len(parts) == 1 {
+					return nil, sliceTooSmallError{err: r.err}
+				}
+				split = append(split, parts...)
+			}
+			log.Debug().
+				Str("uri", p.uri).
+				Str("query", expr).
+				Str("slice", output.HumanizeDuration(sliceSize)).
+				Msg("Range query slice returned too many samples, splitting it")
+			pending = append(split, replanSlices(pending, end, step, sliceSize)...)
+		case maxSamples < rangeQueryGrowSamples && sliceSize*2 <= maxSliceSize && sliceSize*2 < failedSize && len(pending) > 0:
+			sliceSize *= 2
+			pending = replanSlices(pending, end, step, sliceSize)
+		}
 	}
 
-	log.Debug().Str("uri", p.uri).Str("query", expr).Int("samples", len(res.Samples)).Msg("Parsed range response")
-
-	p.cache.Add(cacheKey, res)
+	return samples, nil
+}
 
-	return &res, nil
+// replanSlices splits the time range covered by all pending slices
+// using a new slice size.
+func replanSlices(pending []timeRange, end time.Time, step, sliceSize time.Duration) []timeRange {
+	if len(pending) == 0 {
+		return nil
+	}
+	return sliceRange(pending[0].start, end, step, sliceSize)
 }
 
 type timeRange struct {
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -194,11 +195,10 @@ func TestRange(t *testing.T) {
 				case float64(timeParse("2022-06-14T00:00:00Z").Unix()):
 					require.Equal(t, float64(timeParse("2022-06-14T01:59:00Z").Unix()), end, "invalid end for #0")
 				case float64(timeParse("2022-06-14T02:00:00Z").Unix()):
-					require.Equal(t, float64(timeParse("2022-06-14T03:59:00Z").Unix()), end, "invalid end for #1")
-				case float64(timeParse("2022-06-14T04:00:00Z").Unix()):
-					require.Equal(t, float64(timeParse("2022-06-14T05:59:00Z").Unix()), end, "invalid end for #2")
+					// first slice returned only a few samples, so the next one is twice as big
+					require.Equal(t, float64(timeParse("2022-06-14T05:59:00Z").Unix()), end, "invalid end for #1")
 				case float64(timeParse("2022-06-14T06:00:00Z").Unix()):
-					require.Equal(t, float64(timeParse("2022-06-14T07:00:00Z").Unix()), end, "invalid end for #3")
+					require.Equal(t, float64(timeParse("2022-06-14T07:00:00Z").Unix()), end, "invalid end for #2")
 				default:
 					t.Fatalf("unknown start: %.2f", start)
 				}
@@ -304,7 +304,7 @@ func TestRange(t *testing.T) {
 			}))
 			defer srv.Close()
 
-			prom := promapi.NewPrometheus("test", srv.URL, tc.timeout, 1)
+			prom := promapi.NewPrometheus("test", srv.URL, nil, tc.timeout, 1, nil)
 			prom.StartWorkers()
 			defer prom.Close()
 
@@ -331,3 +331,101 @@ func generateSamples(start, end time.Time, step time.Duration) (samples []model.
 	}
 	return samples
 }
+
+func TestRangeAdaptiveSlices(t *testing.T) {
+	type testCaseT struct {
+		name      string
+		start     time.Time
+		end       time.Time
+		step      time.Duration
+		maxPoints int
+		minStep   time.Duration
+		queries   int
+		resStep   time.Duration
+		err       string
+	}
+
+	start := time.Date(2022, 6, 14, 0, 0, 0, 0, time.UTC)
+
+	testCases := []testCaseT{
+		{
+			name:      "split slices",
+			start:     start,
+			end:       start.Add(time.Hour * 4),
+			step:      time.Minute,
+			maxPoints: 50,
+			// both 2h slices fail, then split into 1h slices that fail again
+			// and 30m slices that all work
+			queries: 2 + 4 + 8,
+			resStep: time.Minute,
+		},
+		{
+			name:    "bigger step",
+			start:   start,
+			end:     start.Add(time.Hour),
+			step:    time.Minute,
+			minStep: time.Minute * 2,
+			resStep: time.Minute * 2,
+		},
+		{
+			name:    "step limit",
+			start:   start,
+			end:     start.Add(time.Hour),
+			step:    time.Minute,
+			minStep: time.Hour * 24,
+			err:     "execution: query processing would load too many samples into memory in query execution",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var queries int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				queries++
+				mu.Unlock()
+
+				_ = r.ParseForm()
+				start, _ := strconv.ParseFloat(r.Form.Get("start"), 64)
+				end, _ := strconv.ParseFloat(r.Form.Get("end"), 64)
+				step, _ := strconv.ParseFloat(r.Form.Get("step"), 64)
+
+				w.Header().Set("Content-Type", "application/json")
+				points := int((end-start)/step) + 1
+				if (tc.maxPoints > 0 && points > tc.maxPoints) || step < tc.minStep.Seconds() {
+					w.WriteHeader(422)
+					_, _ = w.Write([]byte(`{"status":"error","errorType":"execution","error":"query processing would load too many samples into memory in query execution"}`))
+					return
+				}
+
+				w.WriteHeader(200)
+				var values []string
+				for i := start; i <= end; i += step {
+					values = append(values, fmt.Sprintf(`[%3f,"1"]`, i))
+				}
+				_, _ = w.Write([]byte(fmt.Sprintf(
+					`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"instance":"1"}, "values":[%s]}]}}`,
+					strings.Join(values, ","))))
+			}))
+			defer srv.Close()
+
+			prom := promapi.NewPrometheus("test", srv.URL, nil, time.Second, 4, nil)
+			prom.StartWorkers()
+			defer prom.Close()
+
+			qr, err := prom.RangeQuery(context.Background(), "foo", promapi.NewAbsoluteRange(tc.start, tc.end, tc.step))
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.resStep, qr.Step)
+			if tc.queries > 0 {
+				require.Equal(t, tc.queries, queries)
+			}
+			require.Len(t, qr.Samples, 1)
+			require.Equal(t, generateSamples(tc.start, tc.end, tc.resStep), qr.Samples[0].Values)
+		})
+	}
+}
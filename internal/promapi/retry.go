@@ -0,0 +1,201 @@
+package promapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/rs/zerolog/log"
+)
+
+// RetryConfig controls how many times a query that failed with a transient
+// error is retried on the same server before moving to the next one.
+type RetryConfig struct {
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// backoff returns how long to wait before given retry attempt,
+// doubling the wait time on every attempt.
+func (rc RetryConfig) backoff(attempt int) time.Duration {
+	d := rc.MinBackoff
+	for i := 1; i < attempt && (rc.MaxBackoff <= 0 || d < rc.MaxBackoff); i++ {
+		d *= 2
+	}
+	if rc.MaxBackoff > 0 && d > rc.MaxBackoff {
+		return rc.MaxBackoff
+	}
+	return d
+}
+
+func (rc RetryConfig) do(ctx context.Context, prom *Prometheus, endpoint string, fn func() error) (err error) {
+	for attempt := 0; ; attempt++ {
+		if err = fn(); err == nil || attempt >= rc.MaxRetries || !IsRetryableError(err) {
+			return err
+		}
+
+		delay := rc.backoff(attempt + 1)
+		log.Debug().
+			Err(err).
+			Str("uri", prom.uri).
+			Str("endpoint", endpoint).
+			Int("attempt", attempt+1).
+			Str("delay", delay.String()).
+			Msg("Retrying failed query")
+		prometheusQueryRetriesTotal.WithLabelValues(prom.name, endpoint).Inc()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// IsRetryableError returns true for errors that are likely to go away
+// if the same query is sent again after a short delay.
+func IsRetryableError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var neterr net.Error
+	if ok := errors.As(err, &neterr); ok && neterr.Timeout() {
+		return true
+	}
+
+	var apiErr *v1.Error
+	if ok := errors.As(err, &apiErr); ok {
+		switch {
+		case apiErr.Type == v1.ErrTimeout:
+			return true
+		case apiErr.Type == v1.ErrClient && apiErr.Msg == "client error: 429":
+			return true
+		case apiErr.Type == v1.ErrServer && (apiErr.Msg == "server error: 503" || apiErr.Msg == "server error: 504"):
+			return true
+		}
+	}
+
+	return false
+}
+
+type circuitBreakerOpenError struct {
+	uri   string
+	until time.Time
+}
+
+func (e circuitBreakerOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker is open for %s until %s after too many failed queries", e.uri, e.until.Format(time.RFC3339))
+}
+
+// circuitBreaker stops sending queries to a server that keeps failing.
+// After failures consecutive failed queries all queries will skip this server
+// until cooldown passes, then a single trial query is allowed. If it succeeds
+// the breaker is closed, if it fails the breaker is opened again.
+type circuitBreaker struct {
+	name     string
+	uri      string
+	failures int
+	cooldown time.Duration
+	now      func() time.Time
+
+	mu          sync.Mutex
+	consecutive int
+	openUntil   time.Time
+	probing     bool
+}
+
+func newCircuitBreaker(name, uri string, failures int, cooldown time.Duration) *circuitBreaker {
+	// Metrics are labelled by name only, all URIs of a failover group
+	// share them, so make sure the gauge exists without resetting it.
+	prometheusCircuitBreakerOpen.WithLabelValues(name).Add(0)
+	return &circuitBreaker{name: name, uri: uri, failures: failures, cooldown: cooldown, now: time.Now}
+}
+
+func (cb *circuitBreaker) allow() error {
+	if cb == nil {
+		return nil
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.openUntil.IsZero() {
+		return nil
+	}
+	if cb.probing || cb.now().Before(cb.openUntil) {
+		return circuitBreakerOpenError{uri: cb.uri, until: cb.openUntil}
+	}
+	log.Debug().Str("name", cb.name).Str("uri", cb.uri).Msg("Sending trial query to a server with open circuit breaker")
+	cb.probing = true
+	return nil
+}
+
+func (cb *circuitBreaker) success() {
+	if cb == nil {
+		return
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if !cb.openUntil.IsZero() {
+		log.Info().Str("name", cb.name).Str("uri", cb.uri).Msg("Closing circuit breaker")
+		prometheusCircuitBreakerOpen.WithLabelValues(cb.name).Dec()
+	}
+	cb.consecutive = 0
+	cb.openUntil = time.Time{}
+	cb.probing = false
+}
+
+// cancel is called when a query allowed by the breaker was cancelled before
+// it could succeed or fail, so another trial query can be sent.
+func (cb *circuitBreaker) cancel() {
+	if cb == nil {
+		return
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.probing = false
+}
+
+func (cb *circuitBreaker) failure() {
+	if cb == nil {
+		return
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.probing = false
+	cb.consecutive++
+	if cb.consecutive < cb.failures {
+		return
+	}
+
+	wasOpen := !cb.openUntil.IsZero()
+	cb.openUntil = cb.now().Add(cb.cooldown)
+	log.Warn().
+		Str("name", cb.name).
+		Str("uri", cb.uri).
+		Int("failures", cb.consecutive).
+		Str("until", cb.openUntil.Format(time.RFC3339)).
+		Msg("Opening circuit breaker")
+	if !wasOpen {
+		prometheusCircuitBreakerOpen.WithLabelValues(cb.name).Inc()
+	}
+	prometheusCircuitBreakerTripsTotal.WithLabelValues(cb.name).Inc()
+}
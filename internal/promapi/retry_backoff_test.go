@@ -0,0 +1,100 @@
+package promapi
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	type testCaseT struct {
+		rc      RetryConfig
+		attempt int
+		backoff time.Duration
+	}
+
+	testCases := []testCaseT{
+		{rc: RetryConfig{MinBackoff: time.Second, MaxBackoff: time.Minute}, attempt: 1, backoff: time.Second},
+		{rc: RetryConfig{MinBackoff: time.Second, MaxBackoff: time.Minute}, attempt: 2, backoff: time.Second * 2},
+		{rc: RetryConfig{MinBackoff: time.Second, MaxBackoff: time.Minute}, attempt: 4, backoff: time.Second * 8},
+		{rc: RetryConfig{MinBackoff: time.Second, MaxBackoff: time.Minute}, attempt: 7, backoff: time.Minute},
+		{rc: RetryConfig{MinBackoff: time.Second, MaxBackoff: time.Minute}, attempt: 100, backoff: time.Minute},
+		{rc: RetryConfig{MinBackoff: time.Minute * 2, MaxBackoff: time.Minute}, attempt: 1, backoff: time.Minute},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			require.Equal(t, tc.backoff, tc.rc.backoff(tc.attempt))
+		})
+	}
+}
+
+func TestCircuitBreakerMetrics(t *testing.T) {
+	// Metrics are global, reset them so the test can be run multiple times.
+	prometheusCircuitBreakerOpen.DeleteLabelValues("metrics")
+	prometheusCircuitBreakerTripsTotal.DeleteLabelValues("metrics")
+
+	// Both breakers belong to the same failover group, so they share metrics.
+	primary := newCircuitBreaker("metrics", "http://localhost:1", 1, time.Hour)
+	secondary := newCircuitBreaker("metrics", "http://localhost:2", 1, time.Hour)
+	open := prometheusCircuitBreakerOpen.WithLabelValues("metrics")
+	trips := prometheusCircuitBreakerTripsTotal.WithLabelValues("metrics")
+	require.Equal(t, 0.0, testutil.ToFloat64(open))
+
+	primary.failure()
+	secondary.failure()
+	require.Equal(t, 2.0, testutil.ToFloat64(open))
+	require.Equal(t, 2.0, testutil.ToFloat64(trips))
+
+	// Failures of an already open breaker only count as another trip.
+	primary.failure()
+	require.Equal(t, 2.0, testutil.ToFloat64(open))
+	require.Equal(t, 3.0, testutil.ToFloat64(trips))
+
+	// Closing one breaker doesn't hide the other one that is still open.
+	primary.success()
+	require.Equal(t, 1.0, testutil.ToFloat64(open))
+	secondary.success()
+	require.Equal(t, 0.0, testutil.ToFloat64(open))
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	cb := newCircuitBreaker("half-open", "http://localhost", 2, time.Minute)
+	cb.now = func() time.Time { return now }
+
+	require.NoError(t, cb.allow())
+	cb.failure()
+	require.NoError(t, cb.allow(), "breaker opened before reaching failures limit")
+	cb.failure()
+	require.Error(t, cb.allow())
+
+	// After cooldown only a single trial query is allowed.
+	now = now.Add(time.Minute)
+	require.NoError(t, cb.allow())
+	require.Error(t, cb.allow())
+	require.Error(t, cb.allow())
+
+	// Failed trial query opens the breaker again for another cooldown.
+	cb.failure()
+	require.Error(t, cb.allow())
+	now = now.Add(time.Second * 59)
+	require.Error(t, cb.allow())
+
+	// Cancelled trial query allows another one.
+	now = now.Add(time.Second)
+	require.NoError(t, cb.allow())
+	cb.cancel()
+	require.NoError(t, cb.allow())
+	require.Error(t, cb.allow())
+
+	// Successful trial query closes the breaker.
+	cb.success()
+	require.NoError(t, cb.allow())
+	require.NoError(t, cb.allow())
+	cb.failure()
+	require.NoError(t, cb.allow(), "closed breaker wasn't reset")
+}
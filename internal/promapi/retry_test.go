@@ -0,0 +1,140 @@
+package promapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+func TestFailoverGroupRetry(t *testing.T) {
+	type testCaseT struct {
+		name     string
+		status   int
+		failures int64
+		retry    promapi.RetryConfig
+		requests int64
+		err      string
+	}
+
+	testCases := []testCaseT{
+		{
+			name:     "503 without retries",
+			status:   503,
+			failures: 1,
+			requests: 1,
+			err:      "server_error: server error: 503",
+		},
+		{
+			name:     "503 with retries",
+			status:   503,
+			failures: 2,
+			retry:    promapi.RetryConfig{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond * 10},
+			requests: 3,
+		},
+		{
+			name:     "429 with retries",
+			status:   429,
+			failures: 1,
+			retry:    promapi.RetryConfig{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond * 10},
+			requests: 2,
+		},
+		{
+			name:     "too many failures",
+			status:   503,
+			failures: 10,
+			retry:    promapi.RetryConfig{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond * 10},
+			requests: 3,
+			err:      "server_error: server error: 503",
+		},
+		{
+			name:     "500 is not retried",
+			status:   500,
+			failures: 1,
+			retry:    promapi.RetryConfig{MaxRetries: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond * 10},
+			requests: 1,
+			err:      "server_error: server error: 500",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt64(&requests, 1) <= tc.failures {
+					w.WriteHeader(tc.status)
+					_, _ = w.Write([]byte("fake error\n"))
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(200)
+				_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+			}))
+			defer srv.Close()
+
+			fg := promapi.NewFailoverGroup("test", []*promapi.Prometheus{
+				promapi.NewPrometheus("test", srv.URL, nil, time.Second, 1, nil),
+			}, true)
+			fg.SetRetry(tc.retry)
+			fg.StartWorkers()
+			defer fg.Close()
+
+			_, err := fg.Query(context.Background(), "foo")
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.requests, atomic.LoadInt64(&requests))
+		})
+	}
+}
+
+func TestFailoverGroupCircuitBreaker(t *testing.T) {
+	var primaryRequests, secondaryRequests int64
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&primaryRequests, 1)
+		w.WriteHeader(500)
+		_, _ = w.Write([]byte("fake error\n"))
+	}))
+	defer primary.Close()
+
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&secondaryRequests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+
+	fg := promapi.NewFailoverGroup("test", []*promapi.Prometheus{
+		promapi.NewPrometheus("test", primary.URL, nil, time.Second, 1, nil),
+		promapi.NewPrometheus("test", secondary.URL, nil, time.Second, 1, nil),
+	}, true)
+	fg.SetCircuitBreaker(2, time.Hour)
+	fg.StartWorkers()
+	defer fg.Close()
+
+	for _, q := range []string{"a", "b", "c", "d"} {
+		_, err := fg.Query(context.Background(), q)
+		require.NoError(t, err)
+	}
+	require.Equal(t, int64(2), atomic.LoadInt64(&primaryRequests), "primary wasn't skipped after 2 failures")
+	require.Equal(t, int64(4), atomic.LoadInt64(&secondaryRequests))
+
+	// once all servers are failing the breaker error is returned
+	secondary.Close()
+	var err error
+	for _, q := range []string{"e", "f"} {
+		_, err = fg.Query(context.Background(), q)
+		require.Error(t, err)
+	}
+	_, err = fg.Query(context.Background(), "g")
+	require.ErrorContains(t, err, "circuit breaker is open for "+primary.URL)
+	require.True(t, promapi.IsUnavailableError(err))
+}
@@ -0,0 +1,129 @@
+package promapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// QueryStats are statistics returned by Prometheus for queries sent with stats=all.
+type QueryStats struct {
+	Timings QueryTimings `json:"timings"`
+	Samples QuerySamples `json:"samples"`
+}
+
+type QueryTimings struct {
+	EvalTotalTime        float64 `json:"evalTotalTime"`
+	ResultSortTime       float64 `json:"resultSortTime"`
+	QueryPreparationTime float64 `json:"queryPreparationTime"`
+	InnerEvalTime        float64 `json:"innerEvalTime"`
+	ExecQueueTime        float64 `json:"execQueueTime"`
+	ExecTotalTime        float64 `json:"execTotalTime"`
+}
+
+type QuerySamples struct {
+	TotalQueryableSamples int64 `json:"totalQueryableSamples"`
+	PeakSamples           int   `json:"peakSamples"`
+}
+
+// EvalDuration returns how long it took Prometheus to evaluate the query.
+func (qs QueryStats) EvalDuration() time.Duration {
+	return time.Duration(qs.Timings.EvalTotalTime * float64(time.Second))
+}
+
+type statsResult struct {
+	value model.Value
+	stats *QueryStats
+}
+
+// queryWithStats sends an instant query with stats=all. The client_golang
+// API doesn't support query stats so we need to send it and decode
+// the response ourselves.
+func queryWithStats(ctx context.Context, client api.Client, expr string, ts time.Time) (any, error) {
+	args := url.Values{}
+	args.Set("query", expr)
+	args.Set("time", strconv.FormatFloat(float64(ts.Unix())+float64(ts.Nanosecond())/1e9, 'f', -1, 64))
+	args.Set("stats", "all")
+
+	u := client.URL("/api/v1/query", nil)
+	req, err := http.NewRequest(http.MethodPost, u.String(), strings.NewReader(args.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, body, err := client.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Same error handling as in the client_golang API.
+	code := resp.StatusCode
+	if code/100 != 2 && code != http.StatusBadRequest && code != http.StatusUnprocessableEntity {
+		switch code / 100 {
+		case 4:
+			return nil, &v1.Error{Type: v1.ErrClient, Msg: fmt.Sprintf("client error: %d", code), Detail: string(body)}
+		case 5:
+			return nil, &v1.Error{Type: v1.ErrServer, Msg: fmt.Sprintf("server error: %d", code), Detail: string(body)}
+		default:
+			return nil, &v1.Error{Type: v1.ErrBadResponse, Msg: fmt.Sprintf("bad response code %d", code), Detail: string(body)}
+		}
+	}
+
+	var result struct {
+		Status    string          `json:"status"`
+		Data      json.RawMessage `json:"data"`
+		ErrorType v1.ErrorType    `json:"errorType"`
+		Error     string          `json:"error"`
+	}
+	if err = json.Unmarshal(body, &result); err != nil {
+		return nil, &v1.Error{Type: v1.ErrBadResponse, Msg: err.Error()}
+	}
+	if result.Status == "error" {
+		return nil, &v1.Error{Type: result.ErrorType, Msg: result.Error}
+	}
+
+	var data struct {
+		ResultType model.ValueType `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+		Stats      *QueryStats     `json:"stats"`
+	}
+	if err = json.Unmarshal(result.Data, &data); err != nil {
+		return nil, &v1.Error{Type: v1.ErrBadResponse, Msg: err.Error()}
+	}
+
+	sr := statsResult{stats: data.Stats}
+	switch data.ResultType {
+	case model.ValVector:
+		var v model.Vector
+		err = json.Unmarshal(data.Result, &v)
+		sr.value = v
+	case model.ValMatrix:
+		var v model.Matrix
+		err = json.Unmarshal(data.Result, &v)
+		sr.value = v
+	case model.ValScalar:
+		v := &model.Scalar{}
+		err = json.Unmarshal(data.Result, v)
+		sr.value = v
+	case model.ValString:
+		v := &model.String{}
+		err = json.Unmarshal(data.Result, v)
+		sr.value = v
+	default:
+		err = fmt.Errorf("unexpected value type %q", data.ResultType)
+	}
+	if err != nil {
+		return nil, &v1.Error{Type: v1.ErrBadResponse, Msg: err.Error()}
+	}
+	return sr, nil
+}
@@ -0,0 +1,55 @@
+package promapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+type tenantContextKey struct{}
+
+// Tenant is the HTTP header used to select a tenant on multi-tenant
+// Prometheus compatible servers, like Cortex, Mimir or Thanos.
+type Tenant struct {
+	Header string
+	Value  string
+}
+
+func withTenant(ctx context.Context, tenant *Tenant) context.Context {
+	if tenant == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, tenantContextKey{}, *tenant)
+}
+
+func tenantFromContext(ctx context.Context) (Tenant, bool) {
+	tenant, ok := ctx.Value(tenantContextKey{}).(Tenant)
+	return tenant, ok
+}
+
+// writeTenantCacheKey adds the tenant to the cache key, so results
+// returned for one tenant are never reused for queries sent for another.
+func writeTenantCacheKey(ctx context.Context, w io.Writer) {
+	if tenant, ok := tenantFromContext(ctx); ok {
+		_, _ = io.WriteString(w, "\n")
+		_, _ = io.WriteString(w, tenant.Header)
+		_, _ = io.WriteString(w, "\n")
+		_, _ = io.WriteString(w, tenant.Value)
+	}
+}
+
+type headersRoundTripper struct {
+	headers map[string]string
+	rt      http.RoundTripper
+}
+
+func (hrt headersRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range hrt.headers {
+		req.Header.Set(k, v)
+	}
+	if tenant, ok := tenantFromContext(req.Context()); ok {
+		req.Header.Set(tenant.Header, tenant.Value)
+	}
+	return hrt.rt.RoundTrip(req)
+}
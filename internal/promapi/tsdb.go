@@ -0,0 +1,305 @@
+package promapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/prometheus/client_golang/api"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/util/stats"
+	"github.com/rs/zerolog/log"
+)
+
+const tsdbAddress = "http://tsdb.invalid"
+
+// NewTSDB returns a Prometheus instance that answers all queries using
+// blocks stored in a local TSDB directory, without any network access.
+// The newest sample found in the TSDB is treated as the current time,
+// so checks looking at recent data will work with old snapshots.
+func NewTSDB(name, dir string, timeout time.Duration, concurrency int) (*Prometheus, error) {
+	rt, err := newTSDBRoundTripper(dir, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := api.NewClient(api.Config{Address: tsdbAddress, RoundTripper: rt})
+	if err != nil {
+		return nil, err
+	}
+
+	cache, _ := lru.NewARC(1000)
+
+	prom := Prometheus{
+		name:        name,
+		uri:         dir,
+		api:         v1.NewAPI(client),
+		client:      client,
+		timeout:     timeout,
+		cache:       cache,
+		concurrency: concurrency,
+		closer:      rt,
+	}
+	return &prom, nil
+}
+
+type tsdbRoundTripper struct {
+	dir    string
+	blocks []*tsdb.Block
+	engine *promql.Engine
+	offset time.Duration
+}
+
+func newTSDBRoundTripper(dir string, timeout time.Duration) (*tsdbRoundTripper, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open TSDB: %w", err)
+	}
+
+	rt := tsdbRoundTripper{dir: dir}
+	maxt := int64(math.MinInt64)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, entry.Name(), "meta.json")); err != nil {
+			continue
+		}
+		block, err := tsdb.OpenBlock(nil, filepath.Join(dir, entry.Name()), nil)
+		if err != nil {
+			_ = rt.Close()
+			return nil, fmt.Errorf("failed to open TSDB block: %w", err)
+		}
+		rt.blocks = append(rt.blocks, block)
+		if block.MaxTime() > maxt {
+			maxt = block.MaxTime()
+		}
+	}
+	if len(rt.blocks) == 0 {
+		return nil, fmt.Errorf("no TSDB blocks found in %s", dir)
+	}
+
+	if offset := time.Since(timestamp.Time(maxt)); offset > 0 {
+		rt.offset = offset.Truncate(time.Second)
+	}
+
+	rt.engine = promql.NewEngine(promql.EngineOpts{
+		MaxSamples:           50000000,
+		Timeout:              timeout,
+		LookbackDelta:        time.Minute * 5,
+		EnableAtModifier:     true,
+		EnableNegativeOffset: true,
+		NoStepSubqueryIntervalFn: func(int64) int64 {
+			return time.Minute.Milliseconds()
+		},
+	})
+
+	log.Debug().
+		Str("dir", dir).
+		Int("blocks", len(rt.blocks)).
+		Str("maxTime", timestamp.Time(maxt).Format(time.RFC3339)).
+		Msg("Opened TSDB")
+
+	return &rt, nil
+}
+
+func (rt *tsdbRoundTripper) Close() error {
+	var err error
+	for _, block := range rt.blocks {
+		if cerr := block.Close(); cerr != nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func (rt *tsdbRoundTripper) Querier(ctx context.Context, mint, maxt int64) (storage.Querier, error) {
+	queriers := make([]storage.Querier, 0, len(rt.blocks))
+	for _, block := range rt.blocks {
+		if !block.OverlapsClosedInterval(mint, maxt) {
+			continue
+		}
+		q, err := tsdb.NewBlockQuerier(block, mint, maxt)
+		if err != nil {
+			return nil, err
+		}
+		queriers = append(queriers, q)
+	}
+	return storage.NewMergeQuerier(queriers, nil, storage.ChainedSeriesMerge), nil
+}
+
+func (rt *tsdbRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.ParseForm(); err != nil {
+		return rt.respondError(req, http.StatusBadRequest, v1.ErrBadData, err)
+	}
+
+	switch req.URL.Path {
+	case "/api/v1/query":
+		return rt.query(req)
+	case "/api/v1/query_range":
+		return rt.queryRange(req)
+	case "/api/v1/metadata":
+		return rt.respond(req, map[string]any{})
+	case "/api/v1/status/config":
+		return rt.respond(req, v1.ConfigResult{YAML: "global: {}\n"})
+	default:
+		return rt.respondError(req, http.StatusNotFound, v1.ErrBadData, fmt.Errorf("%s is not supported by TSDB backend", req.URL.Path))
+	}
+}
+
+func (rt *tsdbRoundTripper) query(req *http.Request) (*http.Response, error) {
+	ts := time.Now()
+	if t := req.Form.Get("time"); t != "" {
+		var err error
+		if ts, err = parseTSDBTime(t); err != nil {
+			return rt.respondError(req, http.StatusBadRequest, v1.ErrBadData, err)
+		}
+	}
+
+	q, err := rt.engine.NewInstantQuery(rt, nil, req.Form.Get("query"), ts.Add(rt.offset*-1))
+	if err != nil {
+		return rt.respondError(req, http.StatusBadRequest, v1.ErrBadData, err)
+	}
+	return rt.execute(req, q)
+}
+
+func (rt *tsdbRoundTripper) queryRange(req *http.Request) (*http.Response, error) {
+	start, err := parseTSDBTime(req.Form.Get("start"))
+	if err != nil {
+		return rt.respondError(req, http.StatusBadRequest, v1.ErrBadData, err)
+	}
+	end, err := parseTSDBTime(req.Form.Get("end"))
+	if err != nil {
+		return rt.respondError(req, http.StatusBadRequest, v1.ErrBadData, err)
+	}
+	step, err := strconv.ParseFloat(req.Form.Get("step"), 64)
+	if err != nil || step <= 0 {
+		return rt.respondError(req, http.StatusBadRequest, v1.ErrBadData, fmt.Errorf("invalid step: %q", req.Form.Get("step")))
+	}
+
+	q, err := rt.engine.NewRangeQuery(
+		rt, nil, req.Form.Get("query"),
+		start.Add(rt.offset*-1), end.Add(rt.offset*-1),
+		time.Duration(step*float64(time.Second)),
+	)
+	if err != nil {
+		return rt.respondError(req, http.StatusBadRequest, v1.ErrBadData, err)
+	}
+	return rt.execute(req, q)
+}
+
+func (rt *tsdbRoundTripper) execute(req *http.Request, q promql.Query) (*http.Response, error) {
+	defer q.Close()
+
+	res := q.Exec(req.Context())
+	if res.Err != nil {
+		var (
+			eqc promql.ErrQueryCanceled
+			eqt promql.ErrQueryTimeout
+			es  promql.ErrStorage
+		)
+		switch {
+		case errors.As(res.Err, &eqc):
+			return rt.respondError(req, http.StatusServiceUnavailable, v1.ErrCanceled, res.Err)
+		case errors.As(res.Err, &eqt):
+			return rt.respondError(req, http.StatusServiceUnavailable, v1.ErrTimeout, res.Err)
+		case errors.As(res.Err, &es):
+			return rt.respondError(req, http.StatusInternalServerError, v1.ErrServer, res.Err)
+		default:
+			return rt.respondError(req, http.StatusUnprocessableEntity, v1.ErrExec, res.Err)
+		}
+	}
+
+	data := map[string]any{
+		"resultType": res.Value.Type(),
+		"result":     rt.shiftValue(res.Value),
+	}
+	if req.Form.Get("stats") != "" {
+		data["stats"] = stats.NewQueryStats(q.Stats())
+	}
+	return rt.respond(req, data)
+}
+
+// shiftValue moves all timestamps in the result forward by the TSDB offset,
+// so results look like they were returned for the requested time.
+func (rt *tsdbRoundTripper) shiftValue(value parser.Value) parser.Value {
+	offset := rt.offset.Milliseconds()
+	switch v := value.(type) {
+	case promql.Vector:
+		for i := range v {
+			v[i].T += offset
+		}
+	case promql.Matrix:
+		for i := range v {
+			for j := range v[i].Points {
+				v[i].Points[j].T += offset
+			}
+		}
+	case promql.Scalar:
+		v.T += offset
+		return v
+	case promql.String:
+		v.T += offset
+		return v
+	}
+	return value
+}
+
+func (rt *tsdbRoundTripper) respond(req *http.Request, data any) (*http.Response, error) {
+	return rt.writeResponse(req, http.StatusOK, map[string]any{
+		"status": "success",
+		"data":   data,
+	})
+}
+
+func (rt *tsdbRoundTripper) respondError(req *http.Request, code int, errType v1.ErrorType, err error) (*http.Response, error) {
+	return rt.writeResponse(req, code, map[string]any{
+		"status":    "error",
+		"errorType": errType,
+		"error":     err.Error(),
+	})
+}
+
+func (rt *tsdbRoundTripper) writeResponse(req *http.Request, code int, body any) (*http.Response, error) {
+	content, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(content)),
+		ContentLength: int64(len(content)),
+		Request:       req,
+	}, nil
+}
+
+func parseTSDBTime(s string) (time.Time, error) {
+	if t, err := strconv.ParseFloat(s, 64); err == nil {
+		sec, ns := math.Modf(t)
+		return time.Unix(int64(sec), int64(ns*float64(time.Second))).UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q to a valid timestamp", s)
+}
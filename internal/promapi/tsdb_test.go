@@ -0,0 +1,102 @@
+package promapi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/promapi"
+)
+
+type tsdbSample struct {
+	t int64
+	v float64
+}
+
+func (s tsdbSample) T() int64   { return s.t }
+func (s tsdbSample) V() float64 { return s.v }
+
+func newTestTSDB(t *testing.T, end time.Time) string {
+	dir := t.TempDir()
+
+	series := []storage.Series{}
+	for _, job := range []string{"foo", "bar"} {
+		samples := []tsdbutil.Sample{}
+		for ts := end.Add(time.Hour * -6); !ts.After(end); ts = ts.Add(time.Minute) {
+			samples = append(samples, tsdbSample{t: timestamp.FromTime(ts), v: 1})
+		}
+		series = append(series, storage.NewListSeries(labels.FromStrings(labels.MetricName, "up", "job", job), samples))
+	}
+
+	_, err := tsdb.CreateBlock(series, dir, 0, log.NewNopLogger())
+	require.NoError(t, err)
+	return dir
+}
+
+func TestTSDB(t *testing.T) {
+	end := time.Now().Add(time.Hour * -24 * 30).Truncate(time.Minute)
+	dir := newTestTSDB(t, end)
+
+	emptyDir := t.TempDir()
+	_, err := promapi.NewTSDB("test", emptyDir, time.Second, 1)
+	require.EqualError(t, err, "no TSDB blocks found in "+emptyDir)
+
+	prom, err := promapi.NewTSDB("test", dir, time.Second*10, 1)
+	require.NoError(t, err)
+	fg := promapi.NewFailoverGroup("test", []*promapi.Prometheus{prom}, true)
+	fg.StartWorkers()
+	defer fg.Close()
+
+	ctx := context.Background()
+
+	qr, err := fg.Query(ctx, "count(up)")
+	require.NoError(t, err)
+	require.Equal(t, dir, qr.URI)
+	require.Len(t, qr.Series, 1)
+	require.Equal(t, model.SampleValue(2), qr.Series[0].Value)
+
+	qr, err = fg.QueryWithStats(ctx, "count(up)")
+	require.NoError(t, err)
+	require.Len(t, qr.Series, 1)
+	require.NotNil(t, qr.Stats)
+	require.Greater(t, qr.Stats.Timings.EvalTotalTime, float64(0))
+
+	qr, err = fg.Query(ctx, `up{job="foo"}`)
+	require.NoError(t, err)
+	require.Len(t, qr.Series, 1)
+	require.Equal(t, model.LabelValue("foo"), qr.Series[0].Metric["job"])
+	require.WithinDuration(t, time.Now(), qr.Series[0].Timestamp.Time(), time.Minute)
+
+	qr, err = fg.Query(ctx, "notfound")
+	require.NoError(t, err)
+	require.Len(t, qr.Series, 0)
+
+	_, err = fg.Query(ctx, "sum(up) by(")
+	require.Error(t, err)
+
+	rqr, err := fg.RangeQuery(ctx, `up{job="bar"}`, promapi.NewRelativeRange(time.Hour*24, time.Minute*5))
+	require.NoError(t, err)
+	require.Len(t, rqr.Samples, 1)
+	require.Equal(t, model.LabelValue("bar"), rqr.Samples[0].Metric["job"])
+	first := rqr.Samples[0].Values[0].Timestamp.Time()
+	last := rqr.Samples[0].Values[len(rqr.Samples[0].Values)-1].Timestamp.Time()
+	require.WithinDuration(t, time.Now().Add(time.Hour*-6), first, time.Minute*10)
+	require.WithinDuration(t, time.Now(), last, time.Minute*10)
+
+	metadata, err := fg.Metadata(ctx, "up")
+	require.NoError(t, err)
+	require.Len(t, metadata.Metadata, 0)
+
+	cfg, err := fg.Config(ctx)
+	require.NoError(t, err)
+	require.Equal(t, dir, cfg.URI)
+}
@@ -0,0 +1,108 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+const baselineVersion = 1
+
+// BaselineProblem is a single problem recorded in the baseline file.
+// Only Fingerprint is used to match problems, all other fields are there
+// to make the baseline file easier to review.
+type BaselineProblem struct {
+	Fingerprint string `json:"fingerprint"`
+	Path        string `json:"path"`
+	Reporter    string `json:"reporter"`
+	Rule        string `json:"rule"`
+	Text        string `json:"text"`
+}
+
+// Baseline is a list of known problems that should not be reported.
+type Baseline struct {
+	Version  int               `json:"version"`
+	Problems []BaselineProblem `json:"problems"`
+}
+
+// NewBaseline creates a baseline with all problems from given summary.
+func NewBaseline(summary Summary) Baseline {
+	b := Baseline{Version: baselineVersion, Problems: []BaselineProblem{}}
+	for _, report := range summary.Reports {
+		if !shouldReport(report) {
+			continue
+		}
+		b.Problems = append(b.Problems, BaselineProblem{
+			Fingerprint: fingerprint(report),
+			Path:        report.Path,
+			Reporter:    report.Problem.Reporter,
+			Rule:        report.Rule.Name(),
+			Text:        report.Problem.Text,
+		})
+	}
+	sort.SliceStable(b.Problems, func(i, j int) bool {
+		if b.Problems[i].Path != b.Problems[j].Path {
+			return b.Problems[i].Path < b.Problems[j].Path
+		}
+		if b.Problems[i].Rule != b.Problems[j].Rule {
+			return b.Problems[i].Rule < b.Problems[j].Rule
+		}
+		if b.Problems[i].Reporter != b.Problems[j].Reporter {
+			return b.Problems[i].Reporter < b.Problems[j].Reporter
+		}
+		return b.Problems[i].Text < b.Problems[j].Text
+	})
+	return b
+}
+
+// LoadBaseline reads baseline from given file.
+func LoadBaseline(path string) (b Baseline, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return b, err
+	}
+	if err = json.Unmarshal(content, &b); err != nil {
+		return b, fmt.Errorf("failed to parse baseline file %s: %w", path, err)
+	}
+	if b.Version != baselineVersion {
+		return b, fmt.Errorf("unsupported baseline file version %d in %s", b.Version, path)
+	}
+	return b, nil
+}
+
+// Write saves baseline to given file.
+func (b Baseline) Write(path string) error {
+	content, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0o644)
+}
+
+// Filter removes all problems present in the baseline from the summary.
+// If the same problem is recorded N times in the baseline then only N
+// occurrences of it will be removed.
+// It also returns the number of problems removed and the number of
+// baseline entries that didn't match any problem.
+func (b Baseline) Filter(summary Summary) (filtered Summary, suppressed, unused int) {
+	known := map[string]int{}
+	for _, p := range b.Problems {
+		known[p.Fingerprint]++
+	}
+
+	for _, report := range summary.Reports {
+		fp := fingerprint(report)
+		if known[fp] > 0 {
+			known[fp]--
+			suppressed++
+			continue
+		}
+		filtered.Reports = append(filtered.Reports, report)
+	}
+
+	for _, c := range known {
+		unused += c
+	}
+	return filtered, suppressed, unused
+}
@@ -0,0 +1,120 @@
+package reporter_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+func TestBaseline(t *testing.T) {
+	summary := mockSummary(t)
+
+	baseline := reporter.NewBaseline(summary)
+	require.Equal(t, []reporter.BaselineProblem{
+		{
+			Fingerprint: mockFingerprint("bar.txt", "alerts/comparison", "target is down", "rule uses <foo> & bar"),
+			Path:        "bar.txt",
+			Reporter:    "alerts/comparison",
+			Rule:        "target is down",
+			Text:        "rule uses <foo> & bar",
+		},
+		{
+			Fingerprint: mockFingerprint("bar.txt", "promql/regexp", "target is down", "info"),
+			Path:        "bar.txt",
+			Reporter:    "promql/regexp",
+			Rule:        "target is down",
+			Text:        "info",
+		},
+		{
+			Fingerprint: mockFingerprint("foo.txt", "promql/series", "sum errors", `prometheus "prom" at http://localhost doesn't have any series for "errors" metric`),
+			Path:        "foo.txt",
+			Reporter:    "promql/series",
+			Rule:        "sum errors",
+			Text:        `prometheus "prom" at http://localhost doesn't have any series for "errors" metric`,
+		},
+	}, baseline.Problems)
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	require.NoError(t, baseline.Write(path))
+	loaded, err := reporter.LoadBaseline(path)
+	require.NoError(t, err)
+	require.Equal(t, baseline, loaded)
+
+	// Move all problems to different lines and add a new one.
+	shifted := reporter.Summary{}
+	for _, report := range summary.Reports {
+		report.ModifiedLines = []int{10, 11, 12}
+		report.Problem.Lines = []int{11}
+		report.Problem.Position = nil
+		shifted.Reports = append(shifted.Reports, report)
+	}
+	newProblem := shifted.Reports[0]
+	newProblem.Problem.Text = "new problem"
+	newProblem.Problem.Severity = checks.Fatal
+	shifted.Reports = append(shifted.Reports, newProblem)
+
+	filtered, suppressed, unused := loaded.Filter(shifted)
+	require.Equal(t, 3, suppressed)
+	require.Equal(t, 0, unused)
+	require.Equal(t, []reporter.Report{shifted.Reports[3], newProblem}, filtered.Reports)
+
+	filtered, suppressed, unused = loaded.Filter(reporter.Summary{Reports: []reporter.Report{newProblem}})
+	require.Equal(t, 0, suppressed)
+	require.Equal(t, 3, unused)
+	require.Equal(t, []reporter.Report{newProblem}, filtered.Reports)
+}
+
+func TestBaselineDuplicates(t *testing.T) {
+	summary := mockSummary(t)
+	report := summary.Reports[0]
+
+	baseline := reporter.NewBaseline(reporter.Summary{Reports: []reporter.Report{report}})
+	filtered, suppressed, unused := baseline.Filter(reporter.Summary{Reports: []reporter.Report{report, report}})
+	require.Equal(t, 1, suppressed)
+	require.Equal(t, 0, unused)
+	require.Equal(t, []reporter.Report{report}, filtered.Reports)
+}
+
+func TestBaselineMovedReference(t *testing.T) {
+	summary := mockSummary(t)
+	report := summary.Reports[0]
+	report.Problem.Reporter = checks.DuplicateCheckName
+	report.Problem.Text = `duplicated recording rule, "foo" with identical labels is also recorded at rules/other.yml:4`
+
+	baseline := reporter.NewBaseline(reporter.Summary{Reports: []reporter.Report{report}})
+	require.Equal(t, report.Problem.Text, baseline.Problems[0].Text)
+
+	// Referenced rule was moved down by one line.
+	moved := report
+	moved.Problem.Text = `duplicated recording rule, "foo" with identical labels is also recorded at rules/other.yml:5`
+	// Referenced rule was moved to a different file.
+	other := report
+	other.Problem.Text = `duplicated recording rule, "foo" with identical labels is also recorded at rules/another.yml:4`
+
+	filtered, suppressed, unused := baseline.Filter(reporter.Summary{Reports: []reporter.Report{moved, other}})
+	require.Equal(t, 1, suppressed)
+	require.Equal(t, 0, unused)
+	require.Equal(t, []reporter.Report{other}, filtered.Reports)
+}
+
+func TestLoadBaselineErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := reporter.LoadBaseline(filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+
+	bad := filepath.Join(dir, "bad.json")
+	require.NoError(t, os.WriteFile(bad, []byte("{"), 0o644))
+	_, err = reporter.LoadBaseline(bad)
+	require.EqualError(t, err, "failed to parse baseline file "+bad+": unexpected end of JSON input")
+
+	version := filepath.Join(dir, "version.json")
+	require.NoError(t, os.WriteFile(version, []byte(`{"version": 2, "problems": []}`), 0o644))
+	_, err = reporter.LoadBaseline(version)
+	require.EqualError(t, err, "unsupported baseline file version 2 in "+version)
+}
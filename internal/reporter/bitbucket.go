@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/cloudflare/pint/internal/checks"
@@ -21,12 +22,13 @@ type BitBucketReport struct {
 }
 
 type BitBucketAnnotation struct {
-	Path     string `json:"path"`
-	Line     int    `json:"line"`
-	Message  string `json:"message"`
-	Severity string `json:"severity"`
-	Type     string `json:"type"`
-	Link     string `json:"link"`
+	ExternalID string `json:"externalId,omitempty"`
+	Path       string `json:"path"`
+	Line       int    `json:"line"`
+	Message    string `json:"message"`
+	Severity   string `json:"severity"`
+	Type       string `json:"type"`
+	Link       string `json:"link"`
 }
 
 type BitBucketAnnotations struct {
@@ -118,24 +120,25 @@ func (r BitBucketReporter) makeAnnotation(report Report, pb git.FileBlames) (ann
 	}
 
 	a := BitBucketAnnotation{
-		Path:     report.Path,
-		Line:     reportLine,
-		Message:  fmt.Sprintf("%s: %s", report.Problem.Reporter, report.Problem.Text),
-		Severity: severity,
-		Type:     atype,
-		Link:     fmt.Sprintf("https://cloudflare.github.io/pint/checks/%s.html", report.Problem.Reporter),
+		ExternalID: fingerprint(report),
+		Path:       report.Path,
+		Line:       reportLine,
+		Message:    fmt.Sprintf("%s: %s", report.Problem.Reporter, report.Problem.Text),
+		Severity:   severity,
+		Type:       atype,
+		Link:       fmt.Sprintf("https://cloudflare.github.io/pint/checks/%s.html", report.Problem.Reporter),
 	}
 	annotations = append(annotations, a)
 
 	return
 }
 
-func (r BitBucketReporter) bitBucketRequest(method, url string, body []byte) error {
+func (r BitBucketReporter) bitBucketRequest(method, url string, body []byte) ([]byte, error) {
 	log.Debug().Str("url", url).Str("method", method).Msg("Sending a request to BitBucket")
 	log.Debug().Bytes("body", body).Msg("Request payload")
 	req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", r.authToken))
@@ -146,21 +149,21 @@ func (r BitBucketReporter) bitBucketRequest(method, url string, body []byte) err
 
 	resp, err := netClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	log.Debug().Int("status", resp.StatusCode).Msg("BitBucket request completed")
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to read response body")
+	}
 	if resp.StatusCode >= 300 {
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			log.Error().Err(err).Msg("Failed to read response body")
-		}
-		log.Error().Bytes("body", body).Str("url", url).Int("code", resp.StatusCode).Msg("Got a non 2xx response")
-		return fmt.Errorf("%s request failed", method)
+		log.Error().Bytes("body", content).Str("url", url).Int("code", resp.StatusCode).Msg("Got a non 2xx response")
+		return nil, fmt.Errorf("%s request failed", method)
 	}
 
-	return nil
+	return content, err
 }
 
 func (r BitBucketReporter) createReport(commit string, isPassing bool) error {
@@ -173,22 +176,48 @@ func (r BitBucketReporter) createReport(commit string, isPassing bool) error {
 		Result: result,
 	})
 
-	url := fmt.Sprintf("%s/rest/insights/1.0/projects/%s/repos/%s/commits/%s/reports/pint",
-		r.uri, r.project, r.repo, commit)
-	return r.bitBucketRequest(http.MethodPut, url, payload)
+	_, err := r.bitBucketRequest(http.MethodPut, r.reportURL(commit), payload)
+	return err
+}
+
+func (r BitBucketReporter) getAnnotations(commit string) ([]BitBucketAnnotation, error) {
+	content, err := r.bitBucketRequest(http.MethodGet, r.reportURL(commit)+"/annotations", nil)
+	if err != nil {
+		return nil, err
+	}
+	var existing BitBucketAnnotations
+	if len(content) > 0 {
+		if err = json.Unmarshal(content, &existing); err != nil {
+			return nil, fmt.Errorf("failed to decode BitBucket annotations: %w", err)
+		}
+	}
+	return existing.Annotations, nil
 }
 
 func (r BitBucketReporter) createAnnotations(commit string, annotations []BitBucketAnnotation) error {
 	payload, _ := json.Marshal(BitBucketAnnotations{Annotations: annotations})
-	url := fmt.Sprintf("%s/rest/insights/1.0/projects/%s/repos/%s/commits/%s/reports/pint/annotations",
-		r.uri, r.project, r.repo, commit)
-	return r.bitBucketRequest(http.MethodPost, url, payload)
+	_, err := r.bitBucketRequest(http.MethodPost, r.reportURL(commit)+"/annotations", payload)
+	return err
 }
 
-func (r BitBucketReporter) deleteAnnotations(commit string) error {
-	url := fmt.Sprintf("%s/rest/insights/1.0/projects/%s/repos/%s/commits/%s/reports/pint/annotations",
+// deleteAnnotations removes annotations with given external IDs, or all
+// annotations if no IDs are passed.
+func (r BitBucketReporter) deleteAnnotations(commit string, externalIDs []string) error {
+	uri := r.reportURL(commit) + "/annotations"
+	if len(externalIDs) > 0 {
+		q := url.Values{}
+		for _, id := range externalIDs {
+			q.Add("externalId", id)
+		}
+		uri += "?" + q.Encode()
+	}
+	_, err := r.bitBucketRequest(http.MethodDelete, uri, nil)
+	return err
+}
+
+func (r BitBucketReporter) reportURL(commit string) string {
+	return fmt.Sprintf("%s/rest/insights/1.0/projects/%s/repos/%s/commits/%s/reports/pint",
 		r.uri, r.project, r.repo, commit)
-	return r.bitBucketRequest(http.MethodDelete, url, nil)
 }
 
 func (r BitBucketReporter) postReport(commit string, isPassing bool, annotations []BitBucketAnnotation) error {
@@ -197,17 +226,58 @@ func (r BitBucketReporter) postReport(commit string, isPassing bool, annotations
 		return fmt.Errorf("failed to create BitBucket report: %w", err)
 	}
 
-	// Try to delete annotations when that happens so we don't end up with stale data if we run
-	// pint twice, first with problems found, and second without any.
-	err = r.deleteAnnotations(commit)
+	existing, err := r.getAnnotations(commit)
+	if err != nil {
+		return fmt.Errorf("failed to get BitBucket annotations: %w", err)
+	}
+
+	current := map[string]struct{}{}
+	for _, ann := range annotations {
+		current[ann.ExternalID] = struct{}{}
+	}
+
+	// Delete annotations for problems that are no longer reported, so we don't
+	// end up with stale data if we run pint twice, first with problems found,
+	// and second without any. Annotations without an external ID were created
+	// by older pint versions and we can't tell which problem they are for,
+	// so if there are any we delete everything and start from scratch.
+	reported := map[string]struct{}{}
+	var stale []string
+	var deleteAll bool
+	for _, ann := range existing {
+		if ann.ExternalID == "" {
+			deleteAll = true
+			break
+		}
+		if _, ok := current[ann.ExternalID]; ok {
+			reported[ann.ExternalID] = struct{}{}
+			continue
+		}
+		stale = append(stale, ann.ExternalID)
+	}
+	if deleteAll {
+		reported = map[string]struct{}{}
+		err = r.deleteAnnotations(commit, nil)
+	} else if len(stale) > 0 {
+		err = r.deleteAnnotations(commit, stale)
+	}
 	if err != nil {
 		return err
 	}
 
+	added := []BitBucketAnnotation{}
+	for _, ann := range annotations {
+		if _, ok := reported[ann.ExternalID]; ok {
+			continue
+		}
+		reported[ann.ExternalID] = struct{}{}
+		added = append(added, ann)
+	}
+
 	// BitBucket API requires at least one annotation, if there aren't any report is PASS anyway
-	if len(annotations) == 0 {
+	if len(added) == 0 {
 		return nil
 	}
 
-	return r.createAnnotations(commit, annotations)
+	return r.createAnnotations(commit, added)
 }
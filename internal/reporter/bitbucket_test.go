@@ -37,7 +37,9 @@ func TestBitBucketReporter(t *testing.T) {
 		summary      reporter.Summary
 		httpHandler  http.Handler
 		report       reporter.BitBucketReport
+		existing     reporter.BitBucketAnnotations
 		annotations  reporter.BitBucketAnnotations
+		deleted      []string
 		errorHandler errorCheck
 	}
 
@@ -272,28 +274,31 @@ func TestBitBucketReporter(t *testing.T) {
 			annotations: reporter.BitBucketAnnotations{
 				Annotations: []reporter.BitBucketAnnotation{
 					{
-						Path:     "foo.txt",
-						Line:     2,
-						Message:  "mock: bad name",
-						Severity: "HIGH",
-						Type:     "BUG",
-						Link:     "https://cloudflare.github.io/pint/checks/mock.html",
+						ExternalID: mockFingerprint("foo.txt", "mock", "sum errors", "bad name"),
+						Path:       "foo.txt",
+						Line:       2,
+						Message:    "mock: bad name",
+						Severity:   "HIGH",
+						Type:       "BUG",
+						Link:       "https://cloudflare.github.io/pint/checks/mock.html",
 					},
 					{
-						Path:     "foo.txt",
-						Line:     2,
-						Message:  "mock: mock text",
-						Severity: "MEDIUM",
-						Type:     "BUG",
-						Link:     "https://cloudflare.github.io/pint/checks/mock.html",
+						ExternalID: mockFingerprint("foo.txt", "mock", "target is down", "mock text"),
+						Path:       "foo.txt",
+						Line:       2,
+						Message:    "mock: mock text",
+						Severity:   "MEDIUM",
+						Type:       "BUG",
+						Link:       "https://cloudflare.github.io/pint/checks/mock.html",
 					},
 					{
-						Path:     "foo.txt",
-						Line:     4,
-						Message:  "mock: mock text 2",
-						Severity: "LOW",
-						Type:     "CODE_SMELL",
-						Link:     "https://cloudflare.github.io/pint/checks/mock.html",
+						ExternalID: mockFingerprint("foo.txt", "mock", "sum errors", "mock text 2"),
+						Path:       "foo.txt",
+						Line:       4,
+						Message:    "mock: mock text 2",
+						Severity:   "LOW",
+						Type:       "CODE_SMELL",
+						Link:       "https://cloudflare.github.io/pint/checks/mock.html",
 					},
 				},
 			},
@@ -304,6 +309,58 @@ func TestBitBucketReporter(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			description: "problem position is used to select reported line",
+			gitCmd: func(args ...string) ([]byte, error) {
+				if args[0] == "rev-parse" {
+					return []byte("fake-commit-id"), nil
+				}
+				if args[0] == "blame" {
+					content := blameLine("fake-commit-id", 2, "foo.txt", "expr: |") +
+						blameLine("fake-commit-id", 3, "foo.txt", "sum(errors)") +
+						blameLine("fake-commit-id", 4, "foo.txt", "by (job)")
+					return []byte(content), nil
+				}
+				return nil, nil
+			},
+			summary: reporter.Summary{
+				Reports: []reporter.Report{
+					{
+						Path:          "foo.txt",
+						ModifiedLines: []int{2, 3, 4},
+						Rule:          mockRules[1],
+						Problem: checks.Problem{
+							Fragment: "errors",
+							Lines:    []int{2, 3, 4},
+							Reporter: "mock",
+							Text:     "mock text",
+							Severity: checks.Bug,
+							Position: &checks.Position{FirstLine: 3, FirstColumn: 9, LastLine: 3, LastColumn: 14},
+						},
+					},
+				},
+			},
+			report: reporter.BitBucketReport{
+				Title:  "Pint - Prometheus rules linter (version: v0.0.0)",
+				Result: "FAIL",
+			},
+			annotations: reporter.BitBucketAnnotations{
+				Annotations: []reporter.BitBucketAnnotation{
+					{
+						ExternalID: mockFingerprint("foo.txt", "mock", "sum errors", "mock text"),
+						Path:       "foo.txt",
+						Line:       3,
+						Message:    "mock: mock text",
+						Severity:   "MEDIUM",
+						Type:       "BUG",
+						Link:       "https://cloudflare.github.io/pint/checks/mock.html",
+					},
+				},
+			},
+			errorHandler: func(err error) error {
+				return err
+			},
+		},
 		{
 			description: "FATAL errors are always reported, regardless of line number",
 			gitCmd: func(args ...string) ([]byte, error) {
@@ -343,12 +400,13 @@ func TestBitBucketReporter(t *testing.T) {
 			annotations: reporter.BitBucketAnnotations{
 				Annotations: []reporter.BitBucketAnnotation{
 					{
-						Path:     "foo.txt",
-						Line:     3,
-						Message:  "test/mock: syntax error",
-						Severity: "HIGH",
-						Type:     "BUG",
-						Link:     "https://cloudflare.github.io/pint/checks/test/mock.html",
+						ExternalID: mockFingerprint("foo.txt", "test/mock", "sum errors", "syntax error"),
+						Path:       "foo.txt",
+						Line:       3,
+						Message:    "test/mock: syntax error",
+						Severity:   "HIGH",
+						Type:       "BUG",
+						Link:       "https://cloudflare.github.io/pint/checks/test/mock.html",
 					},
 				},
 			},
@@ -461,12 +519,13 @@ func TestBitBucketReporter(t *testing.T) {
 			annotations: reporter.BitBucketAnnotations{
 				Annotations: []reporter.BitBucketAnnotation{
 					{
-						Path:     "foo.txt",
-						Line:     4,
-						Message:  "mock: mock text 2",
-						Severity: "LOW",
-						Type:     "CODE_SMELL",
-						Link:     "https://cloudflare.github.io/pint/checks/mock.html",
+						ExternalID: mockFingerprint("foo.txt", "mock", "sum errors", "mock text 2"),
+						Path:       "foo.txt",
+						Line:       4,
+						Message:    "mock: mock text 2",
+						Severity:   "LOW",
+						Type:       "CODE_SMELL",
+						Link:       "https://cloudflare.github.io/pint/checks/mock.html",
 					},
 				},
 			},
@@ -477,20 +536,185 @@ func TestBitBucketReporter(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			description: "only stale annotations are deleted",
+			gitCmd: func(args ...string) ([]byte, error) {
+				if args[0] == "rev-parse" {
+					return []byte("fake-commit-id"), nil
+				}
+				if args[0] == "blame" {
+					content := blameLine("fake-commit-id", 2, "foo.txt", "up == 0") + blameLine("fake-commit-id", 4, "foo.txt", "errors")
+					return []byte(content), nil
+				}
+				return nil, nil
+			},
+			summary: reporter.Summary{
+				Reports: []reporter.Report{
+					{
+						Path:          "foo.txt",
+						ModifiedLines: []int{2, 4},
+						Rule:          mockRules[0],
+						Problem: checks.Problem{
+							Fragment: "up == 0",
+							Lines:    []int{2},
+							Reporter: "mock",
+							Text:     "mock text",
+							Severity: checks.Bug,
+						},
+					},
+					{
+						Path:          "foo.txt",
+						ModifiedLines: []int{2, 4},
+						Rule:          mockRules[1],
+						Problem: checks.Problem{
+							Fragment: "errors",
+							Lines:    []int{4},
+							Reporter: "mock",
+							Text:     "mock text 2",
+							Severity: checks.Warning,
+						},
+					},
+				},
+			},
+			report: reporter.BitBucketReport{
+				Title:  "Pint - Prometheus rules linter (version: v0.0.0)",
+				Result: "FAIL",
+			},
+			existing: reporter.BitBucketAnnotations{
+				Annotations: []reporter.BitBucketAnnotation{
+					{
+						ExternalID: mockFingerprint("foo.txt", "mock", "target is down", "mock text"),
+						Path:       "foo.txt",
+						Line:       2,
+						Message:    "mock: mock text",
+					},
+					{
+						ExternalID: "stale",
+						Path:       "foo.txt",
+						Line:       3,
+						Message:    "mock: fixed problem",
+					},
+				},
+			},
+			annotations: reporter.BitBucketAnnotations{
+				Annotations: []reporter.BitBucketAnnotation{
+					{
+						ExternalID: mockFingerprint("foo.txt", "mock", "sum errors", "mock text 2"),
+						Path:       "foo.txt",
+						Line:       4,
+						Message:    "mock: mock text 2",
+						Severity:   "LOW",
+						Type:       "CODE_SMELL",
+						Link:       "https://cloudflare.github.io/pint/checks/mock.html",
+					},
+				},
+			},
+			deleted: []string{"externalId=stale"},
+			errorHandler: func(err error) error {
+				return err
+			},
+		},
+		{
+			description: "annotations without external ID are all replaced",
+			gitCmd: func(args ...string) ([]byte, error) {
+				if args[0] == "rev-parse" {
+					return []byte("fake-commit-id"), nil
+				}
+				if args[0] == "blame" {
+					content := blameLine("fake-commit-id", 2, "foo.txt", "up == 0") + blameLine("fake-commit-id", 4, "foo.txt", "errors")
+					return []byte(content), nil
+				}
+				return nil, nil
+			},
+			summary: reporter.Summary{
+				Reports: []reporter.Report{
+					{
+						Path:          "foo.txt",
+						ModifiedLines: []int{2, 4},
+						Rule:          mockRules[0],
+						Problem: checks.Problem{
+							Fragment: "up == 0",
+							Lines:    []int{2},
+							Reporter: "mock",
+							Text:     "mock text",
+							Severity: checks.Bug,
+						},
+					},
+					{
+						Path:          "foo.txt",
+						ModifiedLines: []int{2, 4},
+						Rule:          mockRules[1],
+						Problem: checks.Problem{
+							Fragment: "errors",
+							Lines:    []int{4},
+							Reporter: "mock",
+							Text:     "mock text 2",
+							Severity: checks.Warning,
+						},
+					},
+				},
+			},
+			report: reporter.BitBucketReport{
+				Title:  "Pint - Prometheus rules linter (version: v0.0.0)",
+				Result: "FAIL",
+			},
+			existing: reporter.BitBucketAnnotations{
+				Annotations: []reporter.BitBucketAnnotation{
+					{
+						Path:    "foo.txt",
+						Line:    2,
+						Message: "mock: mock text",
+					},
+				},
+			},
+			annotations: reporter.BitBucketAnnotations{
+				Annotations: []reporter.BitBucketAnnotation{
+					{
+						ExternalID: mockFingerprint("foo.txt", "mock", "target is down", "mock text"),
+						Path:       "foo.txt",
+						Line:       2,
+						Message:    "mock: mock text",
+						Severity:   "MEDIUM",
+						Type:       "BUG",
+						Link:       "https://cloudflare.github.io/pint/checks/mock.html",
+					},
+					{
+						ExternalID: mockFingerprint("foo.txt", "mock", "sum errors", "mock text 2"),
+						Path:       "foo.txt",
+						Line:       4,
+						Message:    "mock: mock text 2",
+						Severity:   "LOW",
+						Type:       "CODE_SMELL",
+						Link:       "https://cloudflare.github.io/pint/checks/mock.html",
+					},
+				},
+			},
+			deleted: []string{""},
+			errorHandler: func(err error) error {
+				return err
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.description, func(t *testing.T) {
+			var deleted []string
 			var srv *httptest.Server
 			if tc.httpHandler == nil {
 				srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 					defer r.Body.Close()
 
 					if r.Method == http.MethodDelete {
+						deleted = append(deleted, r.URL.RawQuery)
 						w.WriteHeader(200)
 						return
 					}
 
+					if r.Method == http.MethodGet {
+						_ = json.NewEncoder(w).Encode(tc.existing)
+						return
+					}
+
 					switch r.URL.Path {
 					case "/rest/insights/1.0/projects/proj/repos/repo/commits/fake-commit-id/reports/pint":
 						var resp reporter.BitBucketReport
@@ -529,6 +753,7 @@ func TestBitBucketReporter(t *testing.T) {
 				t.Errorf("error check failure: %s", e)
 				return
 			}
+			require.Equal(t, tc.deleted, deleted, "Got wrong deleted annotations")
 		})
 	}
 }
@@ -0,0 +1,82 @@
+package reporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+func NewCheckstyleReporter(output io.Writer) CheckstyleReporter {
+	return CheckstyleReporter{output: output}
+}
+
+// CheckstyleReporter writes all reports using Checkstyle XML format.
+type CheckstyleReporter struct {
+	output io.Writer
+}
+
+type checkstyleOutput struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+func (cr CheckstyleReporter) Submit(summary Summary) error {
+	out := checkstyleOutput{Version: "4.3"}
+	for _, report := range sortReports(summary.Reports) {
+		if !shouldReport(report) {
+			continue
+		}
+
+		if len(out.Files) == 0 || out.Files[len(out.Files)-1].Name != report.Path {
+			out.Files = append(out.Files, checkstyleFile{Name: report.Path})
+		}
+		file := &out.Files[len(out.Files)-1]
+
+		line, _ := report.Problem.LineRange()
+		var column int
+		if pos := report.Problem.Position; pos != nil {
+			line, column = pos.FirstLine, pos.FirstColumn
+		}
+		file.Errors = append(file.Errors, checkstyleError{
+			Line:     line,
+			Column:   column,
+			Severity: checkstyleSeverity(report.Problem.Severity),
+			Message:  report.Problem.Text,
+			Source:   report.Problem.Reporter,
+		})
+	}
+
+	content, err := xml.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(cr.output, "%s%s\n", xml.Header, content)
+	return err
+}
+
+func checkstyleSeverity(s checks.Severity) string {
+	switch s {
+	case checks.Bug, checks.Fatal:
+		return "error"
+	case checks.Warning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
@@ -0,0 +1,36 @@
+package reporter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+func TestCheckstyleReporter(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	r := reporter.NewCheckstyleReporter(out)
+	require.NoError(t, r.Submit(mockSummary(t)))
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3">
+  <file name="bar.txt">
+    <error line="2" severity="warning" message="rule uses &lt;foo&gt; &amp; bar" source="alerts/comparison"></error>
+    <error line="2" severity="info" message="info" source="promql/regexp"></error>
+  </file>
+  <file name="foo.txt">
+    <error line="5" column="13" severity="error" message="prometheus &#34;prom&#34; at http://localhost doesn&#39;t have any series for &#34;errors&#34; metric" source="promql/series"></error>
+  </file>
+</checkstyle>
+`, out.String())
+}
+
+func TestCheckstyleReporterEmpty(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	r := reporter.NewCheckstyleReporter(out)
+	require.NoError(t, r.Submit(reporter.Summary{}))
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3"></checkstyle>
+`, out.String())
+}
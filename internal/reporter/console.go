@@ -24,29 +24,7 @@ type ConsoleReporter struct {
 }
 
 func (cr ConsoleReporter) Submit(summary Summary) error {
-	reports := summary.Reports
-	reps := reports[:]
-	sort.Slice(reps, func(i, j int) bool {
-		if reports[i].Path < reports[j].Path {
-			return true
-		}
-		if reports[i].Path > reports[j].Path {
-			return false
-		}
-		if reports[i].Problem.Lines[0] < reports[j].Problem.Lines[0] {
-			return true
-		}
-		if reports[i].Problem.Lines[0] > reports[j].Problem.Lines[0] {
-			return false
-		}
-		if reports[i].Problem.Reporter < reports[j].Problem.Reporter {
-			return true
-		}
-		if reports[i].Problem.Reporter > reports[j].Problem.Reporter {
-			return false
-		}
-		return reports[i].Problem.Text < reports[j].Problem.Text
-	})
+	reps := sortReports(summary.Reports)
 
 	perFile := map[string][]string{}
 	for _, report := range reps {
@@ -69,15 +47,13 @@ func (cr ConsoleReporter) Submit(summary Summary) error {
 
 		msg := []string{}
 		firstLine, lastLine := report.Problem.LineRange()
-		msg = append(msg, color.CyanString("%s:%s: ", report.Path, printLineRange(firstLine, lastLine)))
-		switch report.Problem.Severity {
-		case checks.Bug, checks.Fatal:
-			msg = append(msg, color.RedString(report.Problem.Text))
-		case checks.Warning:
-			msg = append(msg, color.YellowString(report.Problem.Text))
-		default:
-			msg = append(msg, color.HiBlackString(report.Problem.Text))
+		pos := report.Problem.Position
+		if pos != nil {
+			firstLine, lastLine = pos.FirstLine, pos.LastLine
 		}
+		msg = append(msg, color.CyanString("%s:%s: ", report.Path, printLineRange(firstLine, lastLine)))
+		colorize := severityColor(report.Problem.Severity)
+		msg = append(msg, colorize(report.Problem.Text))
 		msg = append(msg, color.MagentaString(" (%s)\n", report.Problem.Reporter))
 
 		lines := strings.Split(content, "\n")
@@ -85,8 +61,13 @@ func (cr ConsoleReporter) Submit(summary Summary) error {
 			lastLine = len(lines) - 1
 			log.Warn().Str("path", report.Path).Msgf("Tried to read more lines than present in the source file, this is likely due to '\n' usage in some rules, see https://github.com/cloudflare/pint/issues/20 for details")
 		}
-		for _, c := range lines[firstLine-1 : lastLine] {
+		for i, c := range lines[firstLine-1 : lastLine] {
 			msg = append(msg, color.WhiteString("%s\n", c))
+			if pos != nil {
+				if u := underline(c, firstLine+i, *pos); u != "" {
+					msg = append(msg, colorize("%s\n", u))
+				}
+			}
 		}
 		perFile[report.Path] = append(perFile[report.Path], strings.Join(msg, ""))
 	}
@@ -107,6 +88,34 @@ func (cr ConsoleReporter) Submit(summary Summary) error {
 	return nil
 }
 
+func severityColor(s checks.Severity) func(format string, a ...interface{}) string {
+	switch s {
+	case checks.Bug, checks.Fatal:
+		return color.RedString
+	case checks.Warning:
+		return color.YellowString
+	default:
+		return color.HiBlackString
+	}
+}
+
+// underline returns a line of carets pointing at the part of given source
+// line that is covered by the problem position.
+func underline(line string, lineNo int, pos checks.Position) string {
+	start := len(line) - len(strings.TrimLeft(line, " ")) + 1
+	if lineNo == pos.FirstLine {
+		start = pos.FirstColumn
+	}
+	end := len(line)
+	if lineNo == pos.LastLine && pos.LastColumn < end {
+		end = pos.LastColumn
+	}
+	if start < 1 || end < start {
+		return ""
+	}
+	return strings.Repeat(" ", start-1) + strings.Repeat("^", end-start+1)
+}
+
 func readFile(path string) (string, error) {
 	f, err := os.Open(path)
 	if err != nil {
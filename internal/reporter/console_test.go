@@ -0,0 +1,101 @@
+package reporter_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+func TestConsoleReporter(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+	color.NoColor = true
+
+	content := `- record: foo
+  expr: sum(foo{job="bar"})
+- record: bar
+  expr: |
+    sum(bar)
+    /
+    sum(baz{job="bar"})
+`
+	path := filepath.Join(t.TempDir(), "rules.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	type testCaseT struct {
+		description string
+		report      reporter.Report
+		output      string
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "no position",
+			report: reporter.Report{
+				Path:          path,
+				ModifiedLines: []int{2},
+				Problem: checks.Problem{
+					Lines:    []int{2},
+					Reporter: "mock",
+					Text:     "problem",
+					Severity: checks.Bug,
+				},
+			},
+			output: path + ":2: problem (mock)\n  expr: sum(foo{job=\"bar\"})\n\n",
+		},
+		{
+			description: "single line position",
+			report: reporter.Report{
+				Path:          path,
+				ModifiedLines: []int{2},
+				Problem: checks.Problem{
+					Fragment: `foo{job="bar"}`,
+					Lines:    []int{2},
+					Reporter: "mock",
+					Text:     "problem",
+					Severity: checks.Bug,
+					Position: &checks.Position{FirstLine: 2, FirstColumn: 13, LastLine: 2, LastColumn: 26},
+				},
+			},
+			output: path + ":2: problem (mock)\n  expr: sum(foo{job=\"bar\"})\n            ^^^^^^^^^^^^^^\n\n",
+		},
+		{
+			description: "multi line position",
+			report: reporter.Report{
+				Path:          path,
+				ModifiedLines: []int{4, 5, 6, 7},
+				Problem: checks.Problem{
+					Fragment: `bar) / sum(baz`,
+					Lines:    []int{4, 5, 6, 7},
+					Reporter: "mock",
+					Text:     "problem",
+					Severity: checks.Warning,
+					Position: &checks.Position{FirstLine: 5, FirstColumn: 9, LastLine: 7, LastColumn: 11},
+				},
+			},
+			output: path + ":5-7: problem (mock)\n" +
+				"    sum(bar)\n" +
+				"        ^^^^\n" +
+				"    /\n" +
+				"    ^\n" +
+				"    sum(baz{job=\"bar\"})\n" +
+				"    ^^^^^^^\n\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			r := reporter.NewConsoleReporter(out)
+			require.NoError(t, r.Submit(reporter.Summary{Reports: []reporter.Report{tc.report}}))
+			require.Equal(t, tc.output, out.String())
+		})
+	}
+}
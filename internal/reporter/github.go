@@ -4,16 +4,27 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/go-github/v37/github"
 	"github.com/rs/zerolog/log"
 	"golang.org/x/oauth2"
 
+	"github.com/cloudflare/pint/internal/checks"
 	"github.com/cloudflare/pint/internal/git"
 )
 
+const (
+	GithubModeReview = "review"
+	GithubModeChecks = "checks"
+
+	// GitHub API accepts up to 50 annotations per request.
+	githubMaxAnnotations = 50
+)
+
 type GithubReporter struct {
+	mode      string
 	baseURL   string
 	uploadURL string
 	timeout   time.Duration
@@ -26,8 +37,11 @@ type GithubReporter struct {
 
 // NewGithubReporter creates a new GitHub reporter that reports
 // problems via comments on a given pull request number (integer).
-func NewGithubReporter(baseURL, uploadURL string, timeout time.Duration, token, owner, repo string, prNum int, gitCmd git.CommandRunner) GithubReporter {
+// If mode is set to GithubModeChecks then problems are instead reported
+// as annotations of a check run created for the HEAD commit.
+func NewGithubReporter(mode, baseURL, uploadURL string, timeout time.Duration, token, owner, repo string, prNum int, gitCmd git.CommandRunner) GithubReporter {
 	return GithubReporter{
+		mode:      mode,
 		baseURL:   baseURL,
 		uploadURL: uploadURL,
 		timeout:   timeout,
@@ -61,20 +75,63 @@ func (gr GithubReporter) Submit(summary Summary) error {
 		client = github.NewClient(tc)
 	}
 
+	if gr.mode == GithubModeChecks {
+		return gr.createCheckRun(ctx, client, summary)
+	}
+	return gr.createReview(ctx, client, summary)
+}
+
+func (gr GithubReporter) createReview(ctx context.Context, client *github.Client, summary Summary) error {
+	existing, err := gr.listPintComments(ctx, client)
+	if err != nil {
+		return fmt.Errorf("listing comments: %w", err)
+	}
+
+	existingIssue, err := gr.listPintIssueComments(ctx, client)
+	if err != nil {
+		return fmt.Errorf("listing issue comments: %w", err)
+	}
+
+	current := map[string]struct{}{}
 	comments := []*github.DraftReviewComment{}
 	for _, rep := range summary.Reports {
 		rep := rep
 
+		// Problems on unmodified rules can't be review comments, GitHub only
+		// accepts those on lines that are part of the diff.
+		if rep.Unmodified {
+			continue
+		}
 		if len(rep.ModifiedLines) == 0 {
 			continue
 		}
 
+		fp := fingerprint(rep)
+		if _, ok := current[fp]; ok {
+			continue
+		}
+		current[fp] = struct{}{}
+		if _, ok := existing[fp]; ok {
+			log.Debug().Str("path", rep.Path).Str("fingerprint", fp).Msg("Problem already reported, skipping")
+			continue
+		}
+
+		body := github.String(fmt.Sprintf("%s\n\n%s", rep.Problem.Text, fingerprintComment(fp)))
 		var comment *github.DraftReviewComment
 
-		if len(rep.ModifiedLines) == 1 {
+		if pos := rep.Problem.Position; pos != nil && isModified(rep.ModifiedLines, pos.FirstLine) && isModified(rep.ModifiedLines, pos.LastLine) {
 			comment = &github.DraftReviewComment{
 				Path: github.String(rep.Path),
-				Body: github.String(rep.Problem.Text),
+				Body: body,
+				Line: github.Int(pos.LastLine),
+			}
+			if pos.FirstLine != pos.LastLine {
+				comment.StartLine = github.Int(pos.FirstLine)
+			}
+		} else if len(rep.ModifiedLines) == 1 {
+			comment = &github.DraftReviewComment{
+				Path: github.String(rep.Path),
+				Body: body,
 				Line: github.Int(rep.ModifiedLines[0]),
 			}
 		} else if len(rep.ModifiedLines) > 1 {
@@ -82,7 +139,7 @@ func (gr GithubReporter) Submit(summary Summary) error {
 			start, end := rep.ModifiedLines[0], rep.ModifiedLines[len(rep.ModifiedLines)-1]
 			comment = &github.DraftReviewComment{
 				Path:      github.String(rep.Path),
-				Body:      github.String(rep.Problem.Text),
+				Body:      body,
 				Line:      github.Int(end),
 				StartLine: github.Int(start),
 			}
@@ -91,6 +148,24 @@ func (gr GithubReporter) Submit(summary Summary) error {
 		comments = append(comments, comment)
 	}
 
+	// Delete comments created by previous runs for problems that are now fixed
+	// and any duplicated comments for problems that are still reported.
+	for fp, ids := range existing {
+		if _, ok := current[fp]; ok {
+			ids = ids[1:]
+		}
+		for _, id := range ids {
+			if _, err = client.PullRequests.DeleteComment(ctx, gr.owner, gr.repo, id); err != nil {
+				return fmt.Errorf("deleting comment: %w", err)
+			}
+			log.Info().Int64("id", id).Str("fingerprint", fp).Msg("Deleted stale or duplicated comment")
+		}
+	}
+
+	if err = gr.updateIssueComments(ctx, client, summary, existingIssue); err != nil {
+		return err
+	}
+
 	if len(comments) > 0 {
 		_, resp, err := client.PullRequests.CreateReview(ctx, gr.owner, gr.repo, gr.prNum, &github.PullRequestReviewRequest{
 			Event:    github.String("COMMENT"),
@@ -104,3 +179,225 @@ func (gr GithubReporter) Submit(summary Summary) error {
 
 	return nil
 }
+
+// listPintComments returns IDs of all pull request comments created by pint
+// grouped by the fingerprint of the problem they were created for.
+// There might be more than one comment for the same problem if pint was run
+// concurrently, IDs are in the order comments were returned by GitHub.
+func (gr GithubReporter) listPintComments(ctx context.Context, client *github.Client) (map[string][]int64, error) {
+	comments := map[string][]int64{}
+	opts := &github.PullRequestListCommentsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		page, resp, err := client.PullRequests.ListComments(ctx, gr.owner, gr.repo, gr.prNum, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range page {
+			if fp, ok := parseFingerprint(c.GetBody()); ok {
+				comments[fp] = append(comments[fp], c.GetID())
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return comments, nil
+}
+
+// updateIssueComments reports problems found on unmodified rules as general
+// pull request comments and deletes comments created by previous runs for
+// problems that are now fixed.
+func (gr GithubReporter) updateIssueComments(ctx context.Context, client *github.Client, summary Summary, existing map[string][]int64) error {
+	current := map[string]struct{}{}
+	for _, rep := range sortReports(summary.Reports) {
+		if !rep.Unmodified {
+			continue
+		}
+
+		fp := fingerprint(rep)
+		if _, ok := current[fp]; ok {
+			continue
+		}
+		current[fp] = struct{}{}
+		if _, ok := existing[fp]; ok {
+			log.Debug().Str("path", rep.Path).Str("fingerprint", fp).Msg("Problem already reported, skipping")
+			continue
+		}
+
+		body := github.String(fmt.Sprintf("Problem found in `%s` on line %d, that rule wasn't modified in this pull request but it's affected by it.\n\n%s\n\n%s",
+			rep.Path, reportedLine(rep), rep.Problem.Text, fingerprintComment(fp)))
+		if _, _, err := client.Issues.CreateComment(ctx, gr.owner, gr.repo, gr.prNum, &github.IssueComment{Body: body}); err != nil {
+			return fmt.Errorf("creating issue comment: %w", err)
+		}
+	}
+
+	for fp, ids := range existing {
+		if _, ok := current[fp]; ok {
+			ids = ids[1:]
+		}
+		for _, id := range ids {
+			if _, err := client.Issues.DeleteComment(ctx, gr.owner, gr.repo, id); err != nil {
+				return fmt.Errorf("deleting issue comment: %w", err)
+			}
+			log.Info().Int64("id", id).Str("fingerprint", fp).Msg("Deleted stale or duplicated issue comment")
+		}
+	}
+
+	return nil
+}
+
+// listPintIssueComments returns IDs of all general pull request comments
+// created by pint grouped by the fingerprint of the problem they were created for.
+func (gr GithubReporter) listPintIssueComments(ctx context.Context, client *github.Client) (map[string][]int64, error) {
+	comments := map[string][]int64{}
+	opts := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		page, resp, err := client.Issues.ListComments(ctx, gr.owner, gr.repo, gr.prNum, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range page {
+			if fp, ok := parseFingerprint(c.GetBody()); ok {
+				comments[fp] = append(comments[fp], c.GetID())
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return comments, nil
+}
+
+func (gr GithubReporter) createCheckRun(ctx context.Context, client *github.Client, summary Summary) error {
+	headCommit, err := git.HeadCommit(gr.gitCmd)
+	if err != nil {
+		return fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+	log.Info().Str("commit", headCommit).Msg("Got HEAD commit from git")
+
+	annotations := []*github.CheckRunAnnotation{}
+	for _, rep := range sortReports(summary.Reports) {
+		if !shouldReport(rep) {
+			continue
+		}
+		annotations = append(annotations, githubAnnotation(rep))
+	}
+
+	title, text := githubCheckSummary(summary)
+	batches := [][]*github.CheckRunAnnotation{}
+	for len(annotations) > githubMaxAnnotations {
+		batches = append(batches, annotations[:githubMaxAnnotations])
+		annotations = annotations[githubMaxAnnotations:]
+	}
+	batches = append(batches, annotations)
+
+	run, resp, err := client.Checks.CreateCheckRun(ctx, gr.owner, gr.repo, github.CreateCheckRunOptions{
+		Name:        "pint",
+		HeadSHA:     headCommit,
+		Status:      github.String("completed"),
+		Conclusion:  github.String(githubConclusion(summary)),
+		CompletedAt: &github.Timestamp{Time: time.Now()},
+		Output: &github.CheckRunOutput{
+			Title:       github.String(title),
+			Summary:     github.String(text),
+			Annotations: batches[0],
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("creating check run: %w", err)
+	}
+
+	// Any remaining annotations are appended to the check run by updating it.
+	for _, batch := range batches[1:] {
+		_, resp, err = client.Checks.UpdateCheckRun(ctx, gr.owner, gr.repo, run.GetID(), github.UpdateCheckRunOptions{
+			Name: "pint",
+			Output: &github.CheckRunOutput{
+				Title:       github.String(title),
+				Summary:     github.String(text),
+				Annotations: batch,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("updating check run: %w", err)
+		}
+	}
+	log.Info().Str("status", resp.Status).Msg("Report submitted")
+
+	return nil
+}
+
+func githubAnnotation(rep Report) *github.CheckRunAnnotation {
+	a := &github.CheckRunAnnotation{
+		Path:            github.String(rep.Path),
+		AnnotationLevel: github.String(githubAnnotationLevel(rep.Problem.Severity)),
+		Title:           github.String(rep.Problem.Reporter),
+		Message:         github.String(rep.Problem.Text),
+	}
+	if pos := rep.Problem.Position; pos != nil {
+		a.StartLine = github.Int(pos.FirstLine)
+		a.EndLine = github.Int(pos.LastLine)
+		// Columns are only allowed if the annotation is on a single line.
+		if pos.FirstLine == pos.LastLine {
+			a.StartColumn = github.Int(pos.FirstColumn)
+			a.EndColumn = github.Int(pos.LastColumn)
+		}
+	} else {
+		start, end := rep.Problem.LineRange()
+		a.StartLine = github.Int(start)
+		a.EndLine = github.Int(end)
+	}
+	return a
+}
+
+func githubAnnotationLevel(s checks.Severity) string {
+	switch s {
+	case checks.Bug, checks.Fatal:
+		return "failure"
+	case checks.Warning:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+func githubConclusion(summary Summary) string {
+	conclusion := "success"
+	for s := range summary.CountBySeverity() {
+		if s >= checks.Bug {
+			return "failure"
+		}
+		conclusion = "neutral"
+	}
+	return conclusion
+}
+
+// githubCheckSummary returns the title and markdown summary of a check run.
+func githubCheckSummary(summary Summary) (title, text string) {
+	counts := summary.CountBySeverity()
+	if len(counts) == 0 {
+		return "No problems found", "pint didn't find any problems."
+	}
+
+	var total int
+	var b strings.Builder
+	b.WriteString("| Severity | Problems |\n")
+	b.WriteString("|----------|----------|\n")
+	for _, s := range []checks.Severity{checks.Fatal, checks.Bug, checks.Warning, checks.Information} {
+		if c, ok := counts[s]; ok {
+			fmt.Fprintf(&b, "| %s | %d |\n", s, c)
+			total += c
+		}
+	}
+	return fmt.Sprintf("Found %d problem(s)", total), b.String()
+}
+
+func isModified(modified []int, line int) bool {
+	_, ok := firstModifiedLine(modified, line, line)
+	return ok
+}
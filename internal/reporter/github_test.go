@@ -1,13 +1,18 @@
 package reporter_test
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/google/go-github/v37/github"
 	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
 
 	"github.com/cloudflare/pint/internal/checks"
 	"github.com/cloudflare/pint/internal/git"
@@ -68,7 +73,7 @@ func TestGithubReporter(t *testing.T) {
 				if err == nil {
 					return fmt.Errorf("expected an error")
 				}
-				if err.Error() != "creating review: context deadline exceeded" {
+				if err.Error() != "listing comments: context deadline exceeded" {
 					return fmt.Errorf("unexpected error")
 				}
 				return nil
@@ -153,6 +158,7 @@ func TestGithubReporter(t *testing.T) {
 			srv := httptest.NewServer(handler)
 			defer srv.Close()
 			reporter := reporter.NewGithubReporter(
+				reporter.GithubModeReview,
 				srv.URL,
 				srv.URL,
 				tcase.timeout,
@@ -171,3 +177,509 @@ func TestGithubReporter(t *testing.T) {
 		})
 	}
 }
+
+func TestGithubReporterChecks(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+
+	p := parser.NewParser()
+	mockRules, _ := p.Parse([]byte(`
+- record: target is down
+  expr: up == 0
+`))
+
+	type checkRunRequest struct {
+		method      string
+		path        string
+		status      string
+		conclusion  string
+		title       string
+		summary     string
+		annotations int
+	}
+
+	type testCaseT struct {
+		description string
+		summary     reporter.Summary
+		requests    []checkRunRequest
+	}
+
+	manyReports := []reporter.Report{}
+	for i := 1; i <= 120; i++ {
+		severity := checks.Warning
+		if i%2 == 0 {
+			severity = checks.Bug
+		}
+		manyReports = append(manyReports, reporter.Report{
+			Path:          fmt.Sprintf("%03d.yml", i),
+			ModifiedLines: []int{2},
+			Rule:          mockRules[0],
+			Problem: checks.Problem{
+				Lines:    []int{2},
+				Reporter: "mock",
+				Text:     "problem",
+				Severity: severity,
+			},
+		})
+	}
+
+	for _, tc := range []testCaseT{
+		{
+			description: "no problems",
+			summary:     reporter.Summary{},
+			requests: []checkRunRequest{
+				{
+					method:     http.MethodPost,
+					path:       "/api/v3/repos/foo/bar/check-runs",
+					status:     "completed",
+					conclusion: "success",
+					title:      "No problems found",
+					summary:    "pint didn't find any problems.",
+				},
+			},
+		},
+		{
+			description: "only warnings",
+			summary: reporter.Summary{
+				Reports: []reporter.Report{
+					{
+						Path:          "foo.txt",
+						ModifiedLines: []int{2},
+						Rule:          mockRules[0],
+						Problem: checks.Problem{
+							Lines:    []int{2},
+							Reporter: "mock",
+							Text:     "warning",
+							Severity: checks.Warning,
+						},
+					},
+					{
+						Path:          "foo.txt",
+						ModifiedLines: []int{},
+						Rule:          mockRules[0],
+						Problem: checks.Problem{
+							Lines:    []int{1},
+							Reporter: "mock",
+							Text:     "unmodified",
+							Severity: checks.Bug,
+						},
+					},
+				},
+			},
+			requests: []checkRunRequest{
+				{
+					method:      http.MethodPost,
+					path:        "/api/v3/repos/foo/bar/check-runs",
+					status:      "completed",
+					conclusion:  "neutral",
+					title:       "Found 1 problem(s)",
+					summary:     "| Severity | Problems |\n|----------|----------|\n| Warning | 1 |\n",
+					annotations: 1,
+				},
+			},
+		},
+		{
+			description: "annotations are sent in batches",
+			summary:     reporter.Summary{Reports: manyReports},
+			requests: []checkRunRequest{
+				{
+					method:      http.MethodPost,
+					path:        "/api/v3/repos/foo/bar/check-runs",
+					status:      "completed",
+					conclusion:  "failure",
+					title:       "Found 120 problem(s)",
+					summary:     "| Severity | Problems |\n|----------|----------|\n| Bug | 60 |\n| Warning | 60 |\n",
+					annotations: 50,
+				},
+				{
+					method:      http.MethodPatch,
+					path:        "/api/v3/repos/foo/bar/check-runs/7",
+					title:       "Found 120 problem(s)",
+					summary:     "| Severity | Problems |\n|----------|----------|\n| Bug | 60 |\n| Warning | 60 |\n",
+					annotations: 50,
+				},
+				{
+					method:      http.MethodPatch,
+					path:        "/api/v3/repos/foo/bar/check-runs/7",
+					title:       "Found 120 problem(s)",
+					summary:     "| Severity | Problems |\n|----------|----------|\n| Bug | 60 |\n| Warning | 60 |\n",
+					annotations: 20,
+				},
+			},
+		},
+	} {
+		t.Run(tc.description, func(t *testing.T) {
+			var requests []checkRunRequest
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var opts struct {
+					HeadSHA    string                `json:"head_sha"`
+					Status     string                `json:"status"`
+					Conclusion string                `json:"conclusion"`
+					Output     github.CheckRunOutput `json:"output"`
+				}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&opts))
+				if r.Method == http.MethodPost {
+					require.Equal(t, "fake-commit-id", opts.HeadSHA)
+				}
+				requests = append(requests, checkRunRequest{
+					method:      r.Method,
+					path:        r.URL.Path,
+					status:      opts.Status,
+					conclusion:  opts.Conclusion,
+					title:       opts.Output.GetTitle(),
+					summary:     opts.Output.GetSummary(),
+					annotations: len(opts.Output.Annotations),
+				})
+				_, _ = w.Write([]byte(`{"id": 7}`))
+			}))
+			defer srv.Close()
+
+			r := reporter.NewGithubReporter(
+				reporter.GithubModeChecks,
+				srv.URL,
+				srv.URL,
+				time.Second,
+				"something",
+				"foo",
+				"bar",
+				0,
+				func(args ...string) ([]byte, error) {
+					return []byte("fake-commit-id"), nil
+				},
+			)
+			require.NoError(t, r.Submit(tc.summary))
+			require.Equal(t, tc.requests, requests)
+		})
+	}
+}
+
+func mockFingerprint(path, reporter, name, text string) string {
+	h := sha256.New()
+	for _, s := range []string{path, reporter, name, text} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func TestGithubReporterStaleComments(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+
+	p := parser.NewParser()
+	mockRules, _ := p.Parse([]byte(`
+- record: target is down
+  expr: up == 0
+- record: sum errors
+  expr: sum(errors) by (job)
+`))
+
+	summary := reporter.Summary{
+		Reports: []reporter.Report{
+			{
+				Path:          "foo.txt",
+				ModifiedLines: []int{2},
+				Rule:          mockRules[0],
+				Problem: checks.Problem{
+					Lines:    []int{2},
+					Reporter: "mock",
+					Text:     "already reported",
+					Severity: checks.Bug,
+				},
+			},
+			{
+				Path:          "foo.txt",
+				ModifiedLines: []int{4},
+				Rule:          mockRules[1],
+				Problem: checks.Problem{
+					Lines:    []int{4},
+					Reporter: "mock",
+					Text:     "new problem",
+					Severity: checks.Bug,
+				},
+			},
+		},
+	}
+
+	reported := mockFingerprint("foo.txt", "mock", "target is down", "already reported")
+	fixed := mockFingerprint("foo.txt", "mock", "sum errors", "fixed problem")
+	added := mockFingerprint("foo.txt", "mock", "sum errors", "new problem")
+
+	var deleted []string
+	var review github.PullRequestReviewRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/foo/bar/pulls/123/comments":
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s/api/v3/repos/foo/bar/pulls/123/comments?page=2>; rel="next"`, "http://"+r.Host))
+				_, _ = w.Write([]byte(fmt.Sprintf(`[
+	{"id": 1, "body": "already reported\n\n<!-- pint fingerprint: %s -->"},
+	{"id": 2, "body": "comment from a human"}
+]`, reported)))
+				return
+			}
+			_, _ = w.Write([]byte(fmt.Sprintf(`[
+	{"id": 3, "body": "fixed problem\n\n<!-- pint fingerprint: %s -->"},
+	{"id": 4, "body": "already reported\n\n<!-- pint fingerprint: %s -->"}
+]`, fixed, reported)))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/foo/bar/issues/123/comments":
+			_, _ = w.Write([]byte("[]"))
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/foo/bar/pulls/123/reviews":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&review))
+			_, _ = w.Write([]byte("{}"))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	r := reporter.NewGithubReporter(
+		reporter.GithubModeReview,
+		srv.URL,
+		srv.URL,
+		time.Second,
+		"something",
+		"foo",
+		"bar",
+		123,
+		nil,
+	)
+	require.NoError(t, r.Submit(summary))
+	require.ElementsMatch(t, []string{"/api/v3/repos/foo/bar/pulls/comments/3", "/api/v3/repos/foo/bar/pulls/comments/4"}, deleted)
+	require.Len(t, review.Comments, 1)
+	require.Equal(t, "foo.txt", review.Comments[0].GetPath())
+	require.Equal(t, 4, review.Comments[0].GetLine())
+	require.Equal(t, fmt.Sprintf("new problem\n\n<!-- pint fingerprint: %s -->", added), review.Comments[0].GetBody())
+}
+
+func TestGithubReporterUnmodified(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+
+	p := parser.NewParser()
+	mockRules, _ := p.Parse([]byte(`
+- record: target is down
+  expr: up == 0
+- alert: foo
+  expr: sum errors > 0
+`))
+
+	summary := reporter.Summary{
+		Reports: []reporter.Report{
+			{
+				Path:          "foo.txt",
+				ModifiedLines: []int{2},
+				Rule:          mockRules[0],
+				Problem: checks.Problem{
+					Lines:    []int{2},
+					Reporter: "mock",
+					Text:     "modified problem",
+					Severity: checks.Bug,
+				},
+			},
+			{
+				Path:       "bar.txt",
+				Rule:       mockRules[1],
+				Unmodified: true,
+				Problem: checks.Problem{
+					Lines:    []int{4, 5},
+					Reporter: "rule/removed",
+					Text:     "removed problem",
+					Severity: checks.Bug,
+				},
+			},
+		},
+	}
+
+	reported := mockFingerprint("bar.txt", "rule/removed", "foo", "removed problem")
+	fixed := mockFingerprint("bar.txt", "rule/removed", "foo", "fixed problem")
+
+	var deleted []string
+	var created []github.IssueComment
+	var review github.PullRequestReviewRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/foo/bar/pulls/123/comments":
+			_, _ = w.Write([]byte("[]"))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/foo/bar/issues/123/comments":
+			_, _ = w.Write([]byte(fmt.Sprintf(`[
+	{"id": 7, "body": "fixed problem\n\n<!-- pint fingerprint: %s -->"},
+	{"id": 8, "body": "comment from a human"}
+]`, fixed)))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/foo/bar/issues/123/comments":
+			var c github.IssueComment
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&c))
+			created = append(created, c)
+			_, _ = w.Write([]byte("{}"))
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/foo/bar/pulls/123/reviews":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&review))
+			_, _ = w.Write([]byte("{}"))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	r := reporter.NewGithubReporter(
+		reporter.GithubModeReview,
+		srv.URL,
+		srv.URL,
+		time.Second,
+		"something",
+		"foo",
+		"bar",
+		123,
+		nil,
+	)
+	require.NoError(t, r.Submit(summary))
+
+	// Only the problem on a modified line is a review comment.
+	require.Len(t, review.Comments, 1)
+	require.Equal(t, "foo.txt", review.Comments[0].GetPath())
+	require.Equal(t, 2, review.Comments[0].GetLine())
+
+	// Problem on an unmodified rule is a general pull request comment.
+	require.Len(t, created, 1)
+	require.Equal(t, fmt.Sprintf("Problem found in `bar.txt` on line 4, that rule wasn't modified in this pull request but it's affected by it.\n\nremoved problem\n\n<!-- pint fingerprint: %s -->", reported), created[0].GetBody())
+	require.Equal(t, []string{"/api/v3/repos/foo/bar/issues/comments/7"}, deleted)
+}
+
+func TestGithubReporterPositions(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+
+	p := parser.NewParser()
+	mockRules, _ := p.Parse([]byte(`
+- record: foo
+  expr: sum(foo)
+- record: bar
+  expr: |
+    sum(bar)
+    /
+    sum(baz)
+`))
+
+	summary := reporter.Summary{
+		Reports: []reporter.Report{
+			{
+				Path:          "single.yml",
+				ModifiedLines: []int{3},
+				Rule:          mockRules[0],
+				Problem: checks.Problem{
+					Fragment: "foo",
+					Lines:    []int{3},
+					Reporter: "mock",
+					Text:     "single line",
+					Severity: checks.Bug,
+					Position: &checks.Position{FirstLine: 3, FirstColumn: 13, LastLine: 3, LastColumn: 15},
+				},
+			},
+			{
+				Path:          "multi.yml",
+				ModifiedLines: []int{5, 6, 7, 8},
+				Rule:          mockRules[1],
+				Problem: checks.Problem{
+					Fragment: "sum(bar) / sum(baz)",
+					Lines:    []int{5, 6, 7, 8},
+					Reporter: "mock",
+					Text:     "multi line",
+					Severity: checks.Bug,
+					Position: &checks.Position{FirstLine: 6, FirstColumn: 5, LastLine: 8, LastColumn: 12},
+				},
+			},
+			{
+				Path:          "unmodified.yml",
+				ModifiedLines: []int{5},
+				Rule:          mockRules[1],
+				Problem: checks.Problem{
+					Fragment: "sum(baz)",
+					Lines:    []int{5, 6, 7, 8},
+					Reporter: "mock",
+					Text:     "position on unmodified line",
+					Severity: checks.Bug,
+					Position: &checks.Position{FirstLine: 8, FirstColumn: 5, LastLine: 8, LastColumn: 12},
+				},
+			},
+		},
+	}
+
+	t.Run("review", func(t *testing.T) {
+		var review github.PullRequestReviewRequest
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet:
+				_, _ = w.Write([]byte("[]"))
+			case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/foo/bar/pulls/123/reviews":
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&review))
+				_, _ = w.Write([]byte("{}"))
+			default:
+				t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}))
+		defer srv.Close()
+
+		r := reporter.NewGithubReporter(reporter.GithubModeReview, srv.URL, srv.URL, time.Second, "something", "foo", "bar", 123, nil)
+		require.NoError(t, r.Submit(summary))
+
+		type comment struct {
+			path      string
+			startLine int
+			line      int
+		}
+		comments := []comment{}
+		for _, c := range review.Comments {
+			comments = append(comments, comment{path: c.GetPath(), startLine: c.GetStartLine(), line: c.GetLine()})
+		}
+		require.Equal(t, []comment{
+			{path: "single.yml", line: 3},
+			{path: "multi.yml", startLine: 6, line: 8},
+			{path: "unmodified.yml", line: 5},
+		}, comments)
+	})
+
+	t.Run("checks", func(t *testing.T) {
+		var annotations []*github.CheckRunAnnotation
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var opts struct {
+				Output github.CheckRunOutput `json:"output"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&opts))
+			annotations = append(annotations, opts.Output.Annotations...)
+			_, _ = w.Write([]byte(`{"id": 7}`))
+		}))
+		defer srv.Close()
+
+		r := reporter.NewGithubReporter(reporter.GithubModeChecks, srv.URL, srv.URL, time.Second, "something", "foo", "bar", 0,
+			func(args ...string) ([]byte, error) {
+				return []byte("fake-commit-id"), nil
+			},
+		)
+		require.NoError(t, r.Submit(summary))
+
+		type annotation struct {
+			path                   string
+			startLine, endLine     int
+			startColumn, endColumn int
+		}
+		got := map[string]annotation{}
+		for _, a := range annotations {
+			got[a.GetMessage()] = annotation{
+				path:        a.GetPath(),
+				startLine:   a.GetStartLine(),
+				endLine:     a.GetEndLine(),
+				startColumn: a.GetStartColumn(),
+				endColumn:   a.GetEndColumn(),
+			}
+		}
+		require.Equal(t, map[string]annotation{
+			"single line":                 {path: "single.yml", startLine: 3, endLine: 3, startColumn: 13, endColumn: 15},
+			"multi line":                  {path: "multi.yml", startLine: 6, endLine: 8},
+			"position on unmodified line": {path: "unmodified.yml", startLine: 8, endLine: 8, startColumn: 5, endColumn: 12},
+		}, got)
+	})
+}
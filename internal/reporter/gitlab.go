@@ -0,0 +1,300 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/output"
+)
+
+const (
+	gitLabSummaryMarker = "<!-- pint summary -->"
+)
+
+type GitLabDiffRefs struct {
+	BaseSHA  string `json:"base_sha"`
+	HeadSHA  string `json:"head_sha"`
+	StartSHA string `json:"start_sha"`
+}
+
+type GitLabMergeRequest struct {
+	DiffRefs GitLabDiffRefs `json:"diff_refs"`
+}
+
+type GitLabPosition struct {
+	BaseSHA      string `json:"base_sha"`
+	HeadSHA      string `json:"head_sha"`
+	StartSHA     string `json:"start_sha"`
+	PositionType string `json:"position_type"`
+	NewPath      string `json:"new_path"`
+	NewLine      int    `json:"new_line"`
+}
+
+type GitLabNote struct {
+	ID       int             `json:"id"`
+	Body     string          `json:"body"`
+	Resolved bool            `json:"resolved"`
+	Position *GitLabPosition `json:"position,omitempty"`
+}
+
+type GitLabDiscussion struct {
+	ID    string       `json:"id"`
+	Notes []GitLabNote `json:"notes"`
+}
+
+type GitLabNewDiscussion struct {
+	Body     string          `json:"body"`
+	Position *GitLabPosition `json:"position,omitempty"`
+}
+
+type GitLabNewNote struct {
+	Body string `json:"body"`
+}
+
+func NewGitLabReporter(version, uri string, timeout time.Duration, token, project string, mrIID int) GitLabReporter {
+	return GitLabReporter{
+		version:   version,
+		uri:       strings.TrimSuffix(uri, "/"),
+		timeout:   timeout,
+		authToken: token,
+		project:   project,
+		mrIID:     mrIID,
+	}
+}
+
+// GitLabReporter reports problems as merge request discussion threads using
+// https://docs.gitlab.com/ee/api/discussions.html#merge-requests
+type GitLabReporter struct {
+	version   string
+	uri       string
+	timeout   time.Duration
+	authToken string
+	project   string
+	mrIID     int
+}
+
+func (r GitLabReporter) Submit(summary Summary) error {
+	var mr GitLabMergeRequest
+	if _, err := r.gitLabRequest(http.MethodGet, r.mrURL(""), nil, &mr); err != nil {
+		return fmt.Errorf("failed to get GitLab merge request: %w", err)
+	}
+	log.Info().
+		Str("base", mr.DiffRefs.BaseSHA).
+		Str("head", mr.DiffRefs.HeadSHA).
+		Str("start", mr.DiffRefs.StartSHA).
+		Msg("Got merge request diff refs from GitLab")
+
+	discussions, err := r.getDiscussions()
+	if err != nil {
+		return fmt.Errorf("failed to get GitLab merge request discussions: %w", err)
+	}
+
+	// Unresolved threads created by previous runs, by problem fingerprint.
+	existing := map[string]string{}
+	for _, d := range discussions {
+		if fp, ok := pintProblemDiscussion(d); ok {
+			existing[fp] = d.ID
+		}
+	}
+
+	current := map[string]struct{}{}
+	var created int
+	for _, report := range sortReports(summary.Reports) {
+		if !shouldReport(report) {
+			log.Debug().
+				Str("path", report.Path).
+				Str("lines", output.FormatLineRangeString(report.Problem.Lines)).
+				Msg("Problem reported on unmodified line, skipping")
+			continue
+		}
+
+		fp := fingerprint(report)
+		if _, ok := current[fp]; ok {
+			continue
+		}
+		current[fp] = struct{}{}
+
+		if _, ok := existing[fp]; ok {
+			log.Debug().Str("path", report.Path).Str("fingerprint", fp).Msg("Problem already reported, skipping")
+			continue
+		}
+
+		d := GitLabNewDiscussion{
+			Body: gitLabProblemBody(report, fp),
+		}
+		// Unmodified rules are not part of the diff, so problems reported on
+		// them are posted as general merge request threads.
+		if !report.Unmodified {
+			d.Position = &GitLabPosition{
+				BaseSHA:      mr.DiffRefs.BaseSHA,
+				HeadSHA:      mr.DiffRefs.HeadSHA,
+				StartSHA:     mr.DiffRefs.StartSHA,
+				PositionType: "text",
+				NewPath:      report.Path,
+				NewLine:      reportedLine(report),
+			}
+		}
+		if _, err = r.gitLabRequest(http.MethodPost, r.mrURL("/discussions"), d, nil); err != nil {
+			return fmt.Errorf("failed to create GitLab discussion: %w", err)
+		}
+		created++
+	}
+
+	// Resolve all threads we've created before for problems that are no longer reported.
+	var resolved int
+	for _, d := range discussions {
+		fp, ok := pintProblemDiscussion(d)
+		if !ok {
+			continue
+		}
+		if _, ok := current[fp]; ok {
+			continue
+		}
+		if _, err = r.gitLabRequest(http.MethodPut, r.mrURL("/discussions/"+d.ID+"?resolved=true"), nil, nil); err != nil {
+			return fmt.Errorf("failed to resolve GitLab discussion: %w", err)
+		}
+		resolved++
+	}
+
+	if err = r.postSummary(summary, discussions); err != nil {
+		return fmt.Errorf("failed to create GitLab summary note: %w", err)
+	}
+
+	log.Info().Int("created", created).Int("resolved", resolved).Msg("Report submitted")
+	return nil
+}
+
+// postSummary creates a merge request note with the number of problems
+// found, or updates it if it already exists.
+func (r GitLabReporter) postSummary(summary Summary, discussions []GitLabDiscussion) error {
+	note := GitLabNewNote{Body: gitLabSummaryBody(r.version, summary)}
+	for _, d := range discussions {
+		for _, n := range d.Notes {
+			if !strings.HasPrefix(n.Body, gitLabSummaryMarker) {
+				continue
+			}
+			_, err := r.gitLabRequest(http.MethodPut, r.mrURL(fmt.Sprintf("/notes/%d", n.ID)), note, nil)
+			return err
+		}
+	}
+	_, err := r.gitLabRequest(http.MethodPost, r.mrURL("/notes"), note, nil)
+	return err
+}
+
+func (r GitLabReporter) getDiscussions() ([]GitLabDiscussion, error) {
+	var discussions []GitLabDiscussion
+	page := "1"
+	for page != "" {
+		var batch []GitLabDiscussion
+		header, err := r.gitLabRequest(http.MethodGet, r.mrURL("/discussions?per_page=100&page="+page), nil, &batch)
+		if err != nil {
+			return nil, err
+		}
+		discussions = append(discussions, batch...)
+		page = header.Get("X-Next-Page")
+	}
+	return discussions, nil
+}
+
+func (r GitLabReporter) mrURL(suffix string) string {
+	return fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d%s", r.uri, url.PathEscape(r.project), r.mrIID, suffix)
+}
+
+func (r GitLabReporter) gitLabRequest(method, uri string, payload, dst any) (http.Header, error) {
+	var body []byte
+	if payload != nil {
+		body, _ = json.Marshal(payload)
+	}
+
+	log.Debug().Str("url", uri).Str("method", method).Msg("Sending a request to GitLab")
+	log.Debug().Bytes("body", body).Msg("Request payload")
+	req, err := http.NewRequest(method, uri, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("PRIVATE-TOKEN", r.authToken)
+
+	netClient := &http.Client{
+		Timeout: r.timeout,
+	}
+
+	resp, err := netClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	log.Debug().Int("status", resp.StatusCode).Msg("GitLab request completed")
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		log.Error().Bytes("body", content).Str("url", uri).Int("code", resp.StatusCode).Msg("Got a non 2xx response")
+		return nil, fmt.Errorf("%s request failed", method)
+	}
+
+	if dst != nil {
+		if err = json.Unmarshal(content, dst); err != nil {
+			return nil, fmt.Errorf("failed to decode GitLab response: %w", err)
+		}
+	}
+	return resp.Header, nil
+}
+
+func gitLabProblemBody(report Report, fp string) string {
+	text := report.Problem.Text
+	if report.Unmodified {
+		text = fmt.Sprintf("Problem found in `%s` on line %d, that rule wasn't modified in this merge request but it's affected by it.\n\n%s",
+			report.Path, reportedLine(report), text)
+	}
+	return fmt.Sprintf("**%s** reported by [pint](https://cloudflare.github.io/pint/) **%s** check.\n\n%s\n\n[Click here](https://cloudflare.github.io/pint/checks/%s.html) to see pint docs for %s check.\n\n%s",
+		report.Problem.Severity, report.Problem.Reporter,
+		text,
+		report.Problem.Reporter, report.Problem.Reporter,
+		fingerprintComment(fp))
+}
+
+func gitLabSummaryBody(version string, summary Summary) string {
+	counts := summary.CountBySeverity()
+	if len(counts) == 0 {
+		return fmt.Sprintf("%s\npint %s didn't find any problems in this merge request.", gitLabSummaryMarker, version)
+	}
+
+	severities := []checks.Severity{}
+	var total int
+	for s, c := range counts {
+		severities = append(severities, s)
+		total += c
+	}
+	sort.Slice(severities, func(i, j int) bool {
+		return severities[i] > severities[j]
+	})
+
+	parts := []string{}
+	for _, s := range severities {
+		parts = append(parts, fmt.Sprintf("- %s: %d", s, counts[s]))
+	}
+	return fmt.Sprintf("%s\npint %s found %d problem(s) in this merge request:\n\n%s",
+		gitLabSummaryMarker, version, total, strings.Join(parts, "\n"))
+}
+
+// pintProblemDiscussion returns the fingerprint of the problem a discussion
+// was created for, if it was created by pint and it's not yet resolved.
+func pintProblemDiscussion(d GitLabDiscussion) (string, bool) {
+	if len(d.Notes) == 0 || d.Notes[0].Resolved {
+		return "", false
+	}
+	return parseFingerprint(d.Notes[0].Body)
+}
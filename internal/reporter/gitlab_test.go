@@ -0,0 +1,255 @@
+package reporter_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+const (
+	gitLabMRPath          = "/api/v4/projects/foo%2Fbar/merge_requests/5"
+	gitLabSummaryBody     = "<!-- pint summary -->\npint v0.0.0 found 1 problem(s) in this merge request:\n\n- Bug: 1"
+	gitLabNoProblemsBody  = "<!-- pint summary -->\npint v0.0.0 didn't find any problems in this merge request."
+	gitLabMergeRequestRes = `{"iid": 5, "diff_refs": {"base_sha": "base", "head_sha": "head", "start_sha": "start"}}`
+)
+
+type gitLabRequest struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+func gitLabProblemBody(text, fp string) string {
+	return fmt.Sprintf("**Bug** reported by [pint](https://cloudflare.github.io/pint/) **mock** check.\n\n%s\n\n[Click here](https://cloudflare.github.io/pint/checks/mock.html) to see pint docs for mock check.\n\n<!-- pint fingerprint: %s -->", text, fp)
+}
+
+func gitLabNoteJSON(body string) string {
+	content, _ := json.Marshal(reporter.GitLabNewNote{Body: body})
+	return string(content)
+}
+
+func TestGitLabReporter(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.FatalLevel)
+
+	p := parser.NewParser()
+	mockRules, _ := p.Parse([]byte(`
+- record: target is down
+  expr: up == 0
+- record: sum errors
+  expr: sum(errors) by (job)
+`))
+
+	mockSummary := reporter.Summary{
+		Reports: []reporter.Report{
+			{
+				Path:          "foo.txt",
+				ModifiedLines: []int{2},
+				Rule:          mockRules[1],
+				Problem: checks.Problem{
+					Fragment: "syntax error",
+					Lines:    []int{2},
+					Reporter: "mock",
+					Text:     "syntax error",
+					Severity: checks.Bug,
+				},
+			},
+			{
+				Path:          "foo.txt",
+				ModifiedLines: []int{},
+				Rule:          mockRules[0],
+				Problem: checks.Problem{
+					Fragment: "up == 0",
+					Lines:    []int{1},
+					Reporter: "mock",
+					Text:     "unmodified line",
+					Severity: checks.Bug,
+				},
+			},
+		},
+	}
+
+	problemBody := gitLabProblemBody("syntax error", mockFingerprint("foo.txt", "mock", "sum errors", "syntax error"))
+	staleBody := gitLabProblemBody("old problem", mockFingerprint("foo.txt", "mock", "sum errors", "old problem"))
+
+	newDiscussion, _ := json.Marshal(reporter.GitLabNewDiscussion{
+		Body: problemBody,
+		Position: &reporter.GitLabPosition{
+			BaseSHA:      "base",
+			HeadSHA:      "head",
+			StartSHA:     "start",
+			PositionType: "text",
+			NewPath:      "foo.txt",
+			NewLine:      2,
+		},
+	})
+
+	unmodifiedSummary := reporter.Summary{
+		Reports: []reporter.Report{
+			{
+				Path:       "bar.txt",
+				Rule:       mockRules[0],
+				Unmodified: true,
+				Problem: checks.Problem{
+					Fragment: "up == 0",
+					Lines:    []int{3},
+					Reporter: "mock",
+					Text:     "removed rule",
+					Severity: checks.Bug,
+				},
+			},
+		},
+	}
+	unmodifiedDiscussion, _ := json.Marshal(reporter.GitLabNewDiscussion{
+		Body: gitLabProblemBody(
+			"Problem found in `bar.txt` on line 3, that rule wasn't modified in this merge request but it's affected by it.\n\nremoved rule",
+			mockFingerprint("bar.txt", "mock", "target is down", "removed rule"),
+		),
+	})
+
+	type testCaseT struct {
+		description string
+		summary     reporter.Summary
+		discussions []string
+		status      map[string]int
+		requests    []gitLabRequest
+		err         string
+	}
+
+	testCases := []testCaseT{
+		{
+			description: "returns an error on merge request failure",
+			summary:     mockSummary,
+			status:      map[string]int{"GET " + gitLabMRPath: http.StatusNotFound},
+			requests: []gitLabRequest{
+				{Method: http.MethodGet, Path: gitLabMRPath},
+			},
+			err: "failed to get GitLab merge request: GET request failed",
+		},
+		{
+			description: "creates discussions and summary note",
+			summary:     mockSummary,
+			discussions: []string{`[]`},
+			requests: []gitLabRequest{
+				{Method: http.MethodGet, Path: gitLabMRPath},
+				{Method: http.MethodGet, Path: gitLabMRPath + "/discussions?per_page=100&page=1"},
+				{Method: http.MethodPost, Path: gitLabMRPath + "/discussions", Body: string(newDiscussion)},
+				{Method: http.MethodPost, Path: gitLabMRPath + "/notes", Body: gitLabNoteJSON(gitLabSummaryBody)},
+			},
+		},
+		{
+			description: "creates discussions without position for unmodified rules",
+			summary:     unmodifiedSummary,
+			discussions: []string{`[]`},
+			requests: []gitLabRequest{
+				{Method: http.MethodGet, Path: gitLabMRPath},
+				{Method: http.MethodGet, Path: gitLabMRPath + "/discussions?per_page=100&page=1"},
+				{Method: http.MethodPost, Path: gitLabMRPath + "/discussions", Body: string(unmodifiedDiscussion)},
+				{Method: http.MethodPost, Path: gitLabMRPath + "/notes", Body: gitLabNoteJSON(gitLabSummaryBody)},
+			},
+		},
+		{
+			description: "returns an error on discussion create failure",
+			summary:     mockSummary,
+			discussions: []string{`[]`},
+			status:      map[string]int{"POST " + gitLabMRPath + "/discussions": http.StatusBadRequest},
+			requests: []gitLabRequest{
+				{Method: http.MethodGet, Path: gitLabMRPath},
+				{Method: http.MethodGet, Path: gitLabMRPath + "/discussions?per_page=100&page=1"},
+				{Method: http.MethodPost, Path: gitLabMRPath + "/discussions", Body: string(newDiscussion)},
+			},
+			err: "failed to create GitLab discussion: POST request failed",
+		},
+		{
+			description: "skips existing discussions and resolves stale ones",
+			summary:     mockSummary,
+			discussions: []string{
+				fmt.Sprintf(`[
+	{"id": "d1", "notes": [{"id": 1, "body": %q, "resolved": false, "position": {"new_path": "foo.txt", "new_line": 2}}]},
+	{"id": "d2", "notes": [{"id": 2, "body": "some other comment", "resolved": false, "position": {"new_path": "foo.txt", "new_line": 3}}]}
+]`, problemBody),
+				fmt.Sprintf(`[
+	{"id": "d3", "notes": [{"id": 3, "body": %q, "resolved": false, "position": {"new_path": "foo.txt", "new_line": 4}}]},
+	{"id": "d4", "notes": [{"id": 4, "body": %q, "resolved": true, "position": {"new_path": "foo.txt", "new_line": 5}}]},
+	{"id": "d5", "notes": [{"id": 5, "body": "<!-- pint summary -->\nold summary"}]}
+]`, staleBody, staleBody),
+			},
+			requests: []gitLabRequest{
+				{Method: http.MethodGet, Path: gitLabMRPath},
+				{Method: http.MethodGet, Path: gitLabMRPath + "/discussions?per_page=100&page=1"},
+				{Method: http.MethodGet, Path: gitLabMRPath + "/discussions?per_page=100&page=2"},
+				{Method: http.MethodPut, Path: gitLabMRPath + "/discussions/d3?resolved=true"},
+				{Method: http.MethodPut, Path: gitLabMRPath + "/notes/5", Body: gitLabNoteJSON(gitLabSummaryBody)},
+			},
+		},
+		{
+			description: "resolves all discussions when there are no problems",
+			summary:     reporter.Summary{},
+			discussions: []string{
+				fmt.Sprintf(`[{"id": "d1", "notes": [{"id": 1, "body": %q, "resolved": false, "position": {"new_path": "foo.txt", "new_line": 2}}]}]`, problemBody),
+			},
+			requests: []gitLabRequest{
+				{Method: http.MethodGet, Path: gitLabMRPath},
+				{Method: http.MethodGet, Path: gitLabMRPath + "/discussions?per_page=100&page=1"},
+				{Method: http.MethodPut, Path: gitLabMRPath + "/discussions/d1?resolved=true"},
+				{Method: http.MethodPost, Path: gitLabMRPath + "/notes", Body: gitLabNoteJSON(gitLabNoProblemsBody)},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var requests []gitLabRequest
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "12345", r.Header.Get("PRIVATE-TOKEN"))
+
+				path := r.URL.EscapedPath()
+				if r.URL.RawQuery != "" {
+					path += "?" + r.URL.RawQuery
+				}
+				body, _ := io.ReadAll(r.Body)
+				requests = append(requests, gitLabRequest{Method: r.Method, Path: path, Body: string(body)})
+
+				if code, ok := tc.status[r.Method+" "+r.URL.EscapedPath()]; ok {
+					w.WriteHeader(code)
+					return
+				}
+
+				switch {
+				case r.Method == http.MethodGet && r.URL.EscapedPath() == gitLabMRPath:
+					_, _ = w.Write([]byte(gitLabMergeRequestRes))
+				case r.Method == http.MethodGet && r.URL.EscapedPath() == gitLabMRPath+"/discussions":
+					var page int
+					_, _ = fmt.Sscan(r.URL.Query().Get("page"), &page)
+					if page < len(tc.discussions) {
+						w.Header().Set("X-Next-Page", fmt.Sprint(page+1))
+					}
+					_, _ = w.Write([]byte(tc.discussions[page-1]))
+				default:
+					_, _ = w.Write([]byte("{}"))
+				}
+			}))
+			defer srv.Close()
+
+			r := reporter.NewGitLabReporter("v0.0.0", srv.URL+"/", time.Second, "12345", "foo/bar", 5)
+			err := r.Submit(tc.summary)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tc.requests, requests)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package reporter
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/cloudflare/pint/internal/output"
+)
+
+func NewJSONReporter(output io.Writer) JSONReporter {
+	return JSONReporter{output: output}
+}
+
+// JSONReporter writes all reports as a JSON list, with one object per report.
+type JSONReporter struct {
+	output io.Writer
+}
+
+type JSONReport struct {
+	Path     string `json:"path"`
+	Lines    []int  `json:"lines"`
+	Reporter string `json:"reporter"`
+	Severity string `json:"severity"`
+	Text     string `json:"text"`
+	Owner    string `json:"owner,omitempty"`
+}
+
+func (jr JSONReporter) Submit(summary Summary) error {
+	reports := []JSONReport{}
+	for _, report := range sortReports(summary.Reports) {
+		if !shouldReport(report) {
+			log.Debug().
+				Str("path", report.Path).
+				Str("lines", output.FormatLineRangeString(report.Problem.Lines)).
+				Msg("Problem reported on unmodified line, skipping")
+			continue
+		}
+		reports = append(reports, JSONReport{
+			Path:     report.Path,
+			Lines:    report.Problem.Lines,
+			Reporter: report.Problem.Reporter,
+			Severity: strings.ToLower(report.Problem.Severity.String()),
+			Text:     report.Problem.Text,
+			Owner:    report.Owner,
+		})
+	}
+
+	enc := json.NewEncoder(jr.output)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(reports)
+}
@@ -0,0 +1,123 @@
+package reporter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/checks"
+	"github.com/cloudflare/pint/internal/parser"
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+func mockSummary(t *testing.T) reporter.Summary {
+	p := parser.NewParser()
+	mockRules, err := p.Parse([]byte(`
+- record: target is down
+  expr: up == 0
+- record: sum errors
+  expr: sum(errors) by (job)
+`))
+	require.NoError(t, err)
+
+	return reporter.Summary{
+		Reports: []reporter.Report{
+			{
+				Path:          "foo.txt",
+				ModifiedLines: []int{4, 5},
+				Rule:          mockRules[1],
+				Problem: checks.Problem{
+					Fragment: "sum(errors) by (job)",
+					Lines:    []int{5},
+					Reporter: "promql/series",
+					Text:     `prometheus "prom" at http://localhost doesn't have any series for "errors" metric`,
+					Severity: checks.Bug,
+					Position: &checks.Position{FirstLine: 5, FirstColumn: 13, LastLine: 5, LastColumn: 18},
+				},
+			},
+			{
+				Path:          "bar.txt",
+				ModifiedLines: []int{2, 3},
+				Rule:          mockRules[0],
+				Owner:         "bob",
+				Problem: checks.Problem{
+					Fragment: "up == 0",
+					Lines:    []int{2, 3},
+					Reporter: "alerts/comparison",
+					Text:     "rule uses <foo> & bar",
+					Severity: checks.Warning,
+				},
+			},
+			{
+				Path:          "bar.txt",
+				ModifiedLines: []int{2, 3},
+				Rule:          mockRules[0],
+				Problem: checks.Problem{
+					Fragment: "up == 0",
+					Lines:    []int{2},
+					Reporter: "promql/regexp",
+					Text:     "info",
+					Severity: checks.Information,
+				},
+			},
+			{
+				Path:          "bar.txt",
+				ModifiedLines: []int{},
+				Rule:          mockRules[0],
+				Problem: checks.Problem{
+					Fragment: "up == 0",
+					Lines:    []int{3},
+					Reporter: "promql/series",
+					Text:     "unmodified line",
+					Severity: checks.Bug,
+				},
+			},
+		},
+	}
+}
+
+func TestJSONReporter(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	r := reporter.NewJSONReporter(out)
+	require.NoError(t, r.Submit(mockSummary(t)))
+	require.Equal(t, `[
+  {
+    "path": "bar.txt",
+    "lines": [
+      2,
+      3
+    ],
+    "reporter": "alerts/comparison",
+    "severity": "warning",
+    "text": "rule uses <foo> & bar",
+    "owner": "bob"
+  },
+  {
+    "path": "bar.txt",
+    "lines": [
+      2
+    ],
+    "reporter": "promql/regexp",
+    "severity": "information",
+    "text": "info"
+  },
+  {
+    "path": "foo.txt",
+    "lines": [
+      5
+    ],
+    "reporter": "promql/series",
+    "severity": "bug",
+    "text": "prometheus \"prom\" at http://localhost doesn't have any series for \"errors\" metric"
+  }
+]
+`, out.String())
+}
+
+func TestJSONReporterEmpty(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	r := reporter.NewJSONReporter(out)
+	require.NoError(t, r.Submit(reporter.Summary{}))
+	require.Equal(t, "[]\n", out.String())
+}
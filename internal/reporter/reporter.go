@@ -1,6 +1,12 @@
 package reporter
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+
 	"github.com/cloudflare/pint/internal/checks"
 	"github.com/cloudflare/pint/internal/git"
 	"github.com/cloudflare/pint/internal/parser"
@@ -12,6 +18,10 @@ type Report struct {
 	Rule          parser.Rule
 	Problem       checks.Problem
 	Owner         string
+	// Unmodified is set for problems reported on rules that weren't modified,
+	// but are affected by other changes. Those can't be reported as comments
+	// on modified lines, so they are reported separately.
+	Unmodified bool
 }
 
 type Summary struct {
@@ -45,6 +55,69 @@ type Reporter interface {
 	Submit(Summary) error
 }
 
+// sortReports sorts reports by path, line, reporter and text.
+func sortReports(reports []Report) []Report {
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Path < reports[j].Path {
+			return true
+		}
+		if reports[i].Path > reports[j].Path {
+			return false
+		}
+		if reports[i].Problem.Lines[0] < reports[j].Problem.Lines[0] {
+			return true
+		}
+		if reports[i].Problem.Lines[0] > reports[j].Problem.Lines[0] {
+			return false
+		}
+		if reports[i].Problem.Reporter < reports[j].Problem.Reporter {
+			return true
+		}
+		if reports[i].Problem.Reporter > reports[j].Problem.Reporter {
+			return false
+		}
+		return reports[i].Problem.Text < reports[j].Problem.Text
+	})
+	return reports
+}
+
+var (
+	fingerprintRe = regexp.MustCompile(`<!-- pint fingerprint: ([0-9a-f]+) -->`)
+	locationRe    = regexp.MustCompile(`\bat (\S+):\d+\b`)
+)
+
+// fingerprint returns an identifier of reported problem that doesn't change
+// between pint runs as long as the problem is still present, even if the rule
+// was moved to a different line.
+// Some checks include the location of other rules in the problem text,
+// line numbers are removed from it so that the fingerprint doesn't change
+// when other rules are moved.
+// It's used to find comments created by previous runs.
+func fingerprint(report Report) string {
+	text := locationRe.ReplaceAllString(report.Problem.Text, "at $1")
+	h := sha256.New()
+	for _, s := range []string{report.Path, report.Problem.Reporter, report.Rule.Name(), text} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// fingerprintComment returns a markdown comment with given fingerprint
+// that can be embedded in the body of a comment without being rendered.
+func fingerprintComment(fp string) string {
+	return fmt.Sprintf("<!-- pint fingerprint: %s -->", fp)
+}
+
+// parseFingerprint returns the fingerprint embedded in a comment body.
+func parseFingerprint(body string) (string, bool) {
+	m := fingerprintRe.FindStringSubmatch(body)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
 func blameReports(reports []Report, gitCmd git.CommandRunner) (pb git.FileBlames, err error) {
 	pb = make(git.FileBlames)
 	for _, report := range reports {
@@ -60,7 +133,7 @@ func blameReports(reports []Report, gitCmd git.CommandRunner) (pb git.FileBlames
 }
 
 func shouldReport(report Report) bool {
-	if report.Problem.Severity == checks.Fatal {
+	if report.Problem.Severity == checks.Fatal || report.Unmodified {
 		return true
 	}
 
@@ -76,6 +149,20 @@ func shouldReport(report Report) bool {
 }
 
 func reportedLine(report Report) (l int) {
+	if report.Unmodified {
+		if pos := report.Problem.Position; pos != nil {
+			return pos.FirstLine
+		}
+		l, _ = report.Problem.LineRange()
+		return l
+	}
+
+	if pos := report.Problem.Position; pos != nil {
+		if ml, ok := firstModifiedLine(report.ModifiedLines, pos.FirstLine, pos.LastLine); ok {
+			return ml
+		}
+	}
+
 	l = -1
 	for _, pl := range report.Problem.Lines {
 		for _, ml := range report.ModifiedLines {
@@ -93,3 +180,13 @@ func reportedLine(report Report) (l int) {
 
 	return
 }
+
+// firstModifiedLine returns the first modified line between first and last.
+func firstModifiedLine(modified []int, first, last int) (line int, ok bool) {
+	for _, ml := range modified {
+		if ml >= first && ml <= last && (!ok || ml < line) {
+			line, ok = ml, true
+		}
+	}
+	return line, ok
+}
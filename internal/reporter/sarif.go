@@ -0,0 +1,172 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/cloudflare/pint/internal/checks"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	pintURI      = "https://cloudflare.github.io/pint/"
+)
+
+func NewSARIFReporter(output io.Writer, version string) SARIFReporter {
+	return SARIFReporter{output: output, version: version}
+}
+
+// SARIFReporter writes all reports using SARIF 2.1.0 format, which can be
+// uploaded to code scanning dashboards.
+type SARIFReporter struct {
+	output  io.Writer
+	version string
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID      string `json:"id"`
+	HelpURI string `json:"helpUri"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	EndLine     int `json:"endLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+func (sr SARIFReporter) Submit(summary Summary) error {
+	reports := []Report{}
+	ruleIDs := []string{}
+	seen := map[string]struct{}{}
+	for _, report := range sortReports(summary.Reports) {
+		if !shouldReport(report) {
+			continue
+		}
+		reports = append(reports, report)
+		if _, ok := seen[report.Problem.Reporter]; !ok {
+			seen[report.Problem.Reporter] = struct{}{}
+			ruleIDs = append(ruleIDs, report.Problem.Reporter)
+		}
+	}
+	sort.Strings(ruleIDs)
+
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "pint",
+				Version:        sr.version,
+				InformationURI: pintURI,
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	ruleIndex := map[string]int{}
+	for i, id := range ruleIDs {
+		ruleIndex[id] = i
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:      id,
+			HelpURI: fmt.Sprintf("%schecks/%s.html", pintURI, id),
+		})
+	}
+
+	for _, report := range reports {
+		region := sarifRegion{}
+		region.StartLine, region.EndLine = report.Problem.LineRange()
+		if pos := report.Problem.Position; pos != nil {
+			region = sarifRegion{
+				StartLine:   pos.FirstLine,
+				EndLine:     pos.LastLine,
+				StartColumn: pos.FirstColumn,
+				// SARIF end column points at the first character after the region.
+				EndColumn: pos.LastColumn + 1,
+			}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    report.Problem.Reporter,
+			RuleIndex: ruleIndex[report.Problem.Reporter],
+			Level:     sarifLevel(report.Problem.Severity),
+			Message:   sarifMessage{Text: report.Problem.Text},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: report.Path},
+						Region:           region,
+					},
+				},
+			},
+		})
+	}
+
+	enc := json.NewEncoder(sr.output)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{run},
+	})
+}
+
+func sarifLevel(s checks.Severity) string {
+	switch s {
+	case checks.Bug, checks.Fatal:
+		return "error"
+	case checks.Warning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,113 @@
+package reporter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/reporter"
+)
+
+func TestSARIFReporter(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	r := reporter.NewSARIFReporter(out, "v1.0.0")
+	require.NoError(t, r.Submit(mockSummary(t)))
+	require.Equal(t, `{
+  "version": "2.1.0",
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "pint",
+          "version": "v1.0.0",
+          "informationUri": "https://cloudflare.github.io/pint/",
+          "rules": [
+            {
+              "id": "alerts/comparison",
+              "helpUri": "https://cloudflare.github.io/pint/checks/alerts/comparison.html"
+            },
+            {
+              "id": "promql/regexp",
+              "helpUri": "https://cloudflare.github.io/pint/checks/promql/regexp.html"
+            },
+            {
+              "id": "promql/series",
+              "helpUri": "https://cloudflare.github.io/pint/checks/promql/series.html"
+            }
+          ]
+        }
+      },
+      "results": [
+        {
+          "ruleId": "alerts/comparison",
+          "ruleIndex": 0,
+          "level": "warning",
+          "message": {
+            "text": "rule uses <foo> & bar"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "bar.txt"
+                },
+                "region": {
+                  "startLine": 2,
+                  "endLine": 3
+                }
+              }
+            }
+          ]
+        },
+        {
+          "ruleId": "promql/regexp",
+          "ruleIndex": 1,
+          "level": "note",
+          "message": {
+            "text": "info"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "bar.txt"
+                },
+                "region": {
+                  "startLine": 2,
+                  "endLine": 2
+                }
+              }
+            }
+          ]
+        },
+        {
+          "ruleId": "promql/series",
+          "ruleIndex": 2,
+          "level": "error",
+          "message": {
+            "text": "prometheus \"prom\" at http://localhost doesn't have any series for \"errors\" metric"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "foo.txt"
+                },
+                "region": {
+                  "startLine": 5,
+                  "endLine": 5,
+                  "startColumn": 13,
+                  "endColumn": 19
+                }
+              }
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`, out.String())
+}
@@ -0,0 +1,40 @@
+package ruletest
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/rules"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedGroups(t *testing.T) {
+	groupsMap := map[string]*rules.Group{}
+	for _, name := range []string{"d", "c", "b", "a", "e"} {
+		groupsMap[name] = rules.NewGroup(rules.GroupOptions{Name: name, File: "rules.yml", Opts: &rules.ManagerOptions{}})
+	}
+
+	for i := 0; i < 10; i++ {
+		names := []string{}
+		for _, g := range orderedGroups(groupsMap, map[string]int{"e": 0, "b": 1}) {
+			names = append(names, g.Name())
+		}
+		require.Equal(t, []string{"a", "c", "d", "e", "b"}, names)
+	}
+}
+
+func TestSortSamples(t *testing.T) {
+	samples := []parsedSample{
+		{Labels: labels.FromStrings("job", "b")},
+		{Labels: labels.FromStrings("job", "a")},
+		{Labels: labels.FromStrings("job", "b")},
+		{Labels: labels.FromStrings("instance", "a", "job", "a")},
+	}
+	sortSamples(samples)
+	require.Equal(t, []parsedSample{
+		{Labels: labels.FromStrings("instance", "a", "job", "a")},
+		{Labels: labels.FromStrings("job", "a")},
+		{Labels: labels.FromStrings("job", "b")},
+		{Labels: labels.FromStrings("job", "b")},
+	}, samples)
+}
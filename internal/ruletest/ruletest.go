@@ -0,0 +1,366 @@
+// Package ruletest runs promtool compatible rule unit tests.
+// Test logic is based on promtool's unit test runner, with
+// failures reported per test case together with the line number
+// it was defined on, so these can be reported as pint problems.
+package ruletest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/promql"
+	promParser "github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/rules"
+	"github.com/prometheus/prometheus/storage"
+	"gopkg.in/yaml.v3"
+
+	"github.com/cloudflare/pint/internal/parser"
+)
+
+// Failure is a single failed test case.
+type Failure struct {
+	Line int
+	Text string
+}
+
+// RunFile runs all tests from given test file.
+// Returned error is only set when test file cannot be read or parsed,
+// test failures are returned as a list of Failure.
+func RunFile(path string) ([]Failure, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tf testFile
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	if err = dec.Decode(&tf); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	ruleFiles, err := resolveRuleFiles(filepath.Dir(path), tf.RuleFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	if tf.EvaluationInterval == 0 {
+		tf.EvaluationInterval = model.Duration(time.Minute)
+	}
+
+	groupOrder := map[string]int{}
+	for i, gn := range tf.GroupEvalOrder {
+		if _, ok := groupOrder[gn]; ok {
+			return nil, fmt.Errorf("group name repeated in evaluation order: %s", gn)
+		}
+		groupOrder[gn] = i
+	}
+
+	var failures []Failure
+	for _, tg := range tf.Tests {
+		failures = append(failures, tg.test(time.Duration(tf.EvaluationInterval), groupOrder, ruleFiles)...)
+	}
+	return failures, nil
+}
+
+// resolveRuleFiles joins all relative paths with the directory of the test file
+// and replaces all globs with matching files.
+func resolveRuleFiles(dir string, paths []string) (files []string, err error) {
+	for _, path := range paths {
+		if path != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no rule files matching %q", path)
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// groupLoader loads rule groups from both plain rule files and
+// files with PrometheusRule manifests.
+type groupLoader struct{}
+
+func (groupLoader) Load(identifier string) (*rulefmt.RuleGroups, []error) {
+	content, err := os.ReadFile(identifier)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", identifier, err)}
+	}
+
+	specs, ok := parser.PrometheusRuleSpecs(content)
+	if !ok {
+		return rulefmt.Parse(content)
+	}
+
+	groups := &rulefmt.RuleGroups{}
+	for _, spec := range specs {
+		rgs, errs := rulefmt.Parse(spec)
+		if errs != nil {
+			return nil, errs
+		}
+		groups.Groups = append(groups.Groups, rgs.Groups...)
+	}
+	return groups, nil
+}
+
+func (groupLoader) Parse(query string) (promParser.Expr, error) {
+	return promParser.ParseExpr(query)
+}
+
+func (tg *testGroup) test(evalInterval time.Duration, groupOrder map[string]int, ruleFiles []string) (failures []Failure) {
+	fail := func(line int, format string, a ...any) {
+		failures = append(failures, Failure{Line: line, Text: tg.testName() + fmt.Sprintf(format, a...)})
+	}
+
+	suite, err := promql.NewLazyLoader(nil, tg.seriesLoadingString(), promql.LazyLoaderOpts{
+		EnableAtModifier:     true,
+		EnableNegativeOffset: true,
+	})
+	if err != nil {
+		fail(tg.line, "failed to load input series: %s", err)
+		return failures
+	}
+	defer suite.Close()
+	suite.SubqueryInterval = evalInterval
+
+	m := rules.NewManager(&rules.ManagerOptions{
+		QueryFunc:   rules.EngineQueryFunc(suite.QueryEngine(), suite.Storage()),
+		Appendable:  suite.Storage(),
+		Context:     context.Background(),
+		NotifyFunc:  func(ctx context.Context, expr string, alerts ...*rules.Alert) {},
+		Logger:      log.NewNopLogger(),
+		GroupLoader: groupLoader{},
+	})
+	groupsMap, errs := m.LoadGroups(time.Duration(tg.Interval), tg.ExternalLabels, tg.ExternalURL, nil, ruleFiles...)
+	if errs != nil {
+		for _, err := range errs {
+			fail(tg.line, "failed to load rules: %s", err)
+		}
+		return failures
+	}
+	groups := orderedGroups(groupsMap, groupOrder)
+
+	mint := time.Unix(0, 0).UTC()
+	maxt := mint.Add(time.Duration(tg.maxEvalTime()))
+
+	alertTests := map[model.Duration][]alertTestCase{}
+	alertsInTest := map[model.Duration]map[string]struct{}{}
+	for _, atc := range tg.AlertRuleTests {
+		if atc.Alertname == "" {
+			fail(atc.line, "alert_rule_test is missing required alertname field")
+			return failures
+		}
+		if _, ok := alertsInTest[atc.EvalTime]; !ok {
+			alertsInTest[atc.EvalTime] = map[string]struct{}{}
+		}
+		alertsInTest[atc.EvalTime][atc.Alertname] = struct{}{}
+		alertTests[atc.EvalTime] = append(alertTests[atc.EvalTime], atc)
+	}
+	alertEvalTimes := make([]model.Duration, 0, len(alertTests))
+	for k := range alertTests {
+		alertEvalTimes = append(alertEvalTimes, k)
+	}
+	sort.Slice(alertEvalTimes, func(i, j int) bool {
+		return alertEvalTimes[i] < alertEvalTimes[j]
+	})
+
+	for _, g := range groups {
+		for _, r := range g.Rules() {
+			if ar, ok := r.(*rules.AlertingRule); ok {
+				// Mark alerting rules as restored, to ensure the ALERTS
+				// time series is created when they run.
+				ar.SetRestored(true)
+			}
+		}
+	}
+
+	var curr int
+	for ts := mint; !ts.After(maxt); ts = ts.Add(evalInterval) {
+		var evalFailed bool
+		suite.WithSamplesTill(ts, func(err error) {
+			if err != nil {
+				fail(tg.line, "failed to load input series: %s", err)
+				evalFailed = true
+				return
+			}
+			for _, g := range groups {
+				g.Eval(suite.Context(), ts)
+				for _, r := range g.Rules() {
+					if r.LastError() != nil {
+						fail(tg.line, "rule %s failed to evaluate at %s: %s", r.Name(), ts.Sub(mint), r.LastError())
+						evalFailed = true
+					}
+				}
+			}
+		})
+		if evalFailed {
+			return failures
+		}
+
+		// Alerts are compared with the evaluation at ts if ts <= eval_time < ts+evalInterval.
+		for curr < len(alertEvalTimes) &&
+			ts.Sub(mint) <= time.Duration(alertEvalTimes[curr]) &&
+			time.Duration(alertEvalTimes[curr]) < ts.Add(evalInterval).Sub(mint) {
+			t := alertEvalTimes[curr]
+			got := activeAlerts(groups, alertsInTest[t])
+			for _, atc := range alertTests[t] {
+				gotAlerts := got[atc.Alertname]
+				expAlerts := atc.expectedAlerts()
+				sort.Sort(gotAlerts)
+				sort.Sort(expAlerts)
+				if !reflect.DeepEqual(expAlerts, gotAlerts) {
+					fail(atc.line, "alert %s at %s doesn't match expected alerts\nexpected: %s\ngot: %s",
+						atc.Alertname, atc.EvalTime, expAlerts, gotAlerts)
+				}
+			}
+			curr++
+		}
+	}
+
+	for _, ptc := range tg.PromqlExprTests {
+		got, err := query(suite.Context(), ptc.Expr, mint.Add(time.Duration(ptc.EvalTime)), suite.QueryEngine(), suite.Queryable())
+		if err != nil {
+			fail(ptc.line, "query %q at %s failed: %s", ptc.Expr, ptc.EvalTime, err)
+			continue
+		}
+		expSamples, err := ptc.expectedSamples()
+		if err != nil {
+			fail(ptc.line, "query %q at %s has invalid exp_samples: %s", ptc.Expr, ptc.EvalTime, err)
+			continue
+		}
+		gotSamples := make([]parsedSample, 0, len(got))
+		for _, s := range got {
+			gotSamples = append(gotSamples, parsedSample{Labels: s.Metric.Copy(), Value: s.V})
+		}
+		sortSamples(expSamples)
+		sortSamples(gotSamples)
+		if len(expSamples) != len(gotSamples) || (len(expSamples) > 0 && !reflect.DeepEqual(expSamples, gotSamples)) {
+			fail(ptc.line, "query %q at %s doesn't match expected samples\nexpected: %s\ngot: %s",
+				ptc.Expr, ptc.EvalTime, parsedSamplesString(expSamples), parsedSamplesString(gotSamples))
+		}
+	}
+
+	return failures
+}
+
+func (atc alertTestCase) expectedAlerts() (expAlerts labelsAndAnnotations) {
+	for _, a := range atc.ExpAlerts {
+		// Expected labels only include labels from the alerting rule,
+		// alertname is added by Prometheus when evaluating it.
+		lset := map[string]string{labels.AlertName: atc.Alertname}
+		for k, v := range a.ExpLabels {
+			lset[k] = v
+		}
+		expAlerts = append(expAlerts, labelAndAnnotation{
+			Labels:      labels.FromMap(lset),
+			Annotations: labels.FromMap(a.ExpAnnotations),
+		})
+	}
+	return expAlerts
+}
+
+func (ptc promqlTestCase) expectedSamples() (expSamples []parsedSample, err error) {
+	for _, s := range ptc.ExpSamples {
+		lset, err := promParser.ParseMetric(s.Labels)
+		if err != nil {
+			return nil, fmt.Errorf("labels %q: %w", s.Labels, err)
+		}
+		expSamples = append(expSamples, parsedSample{Labels: lset, Value: s.Value})
+	}
+	return expSamples, nil
+}
+
+// activeAlerts returns all firing alerts for given alert names.
+// Same alert name can be present in multiple groups, so alerts
+// are collected from all of them.
+func activeAlerts(groups []*rules.Group, names map[string]struct{}) map[string]labelsAndAnnotations {
+	got := map[string]labelsAndAnnotations{}
+	for _, g := range groups {
+		for _, r := range g.Rules() {
+			ar, ok := r.(*rules.AlertingRule)
+			if !ok {
+				continue
+			}
+			if _, ok := names[ar.Name()]; !ok {
+				continue
+			}
+			for _, a := range ar.ActiveAlerts() {
+				if a.State == rules.StateFiring {
+					got[ar.Name()] = append(got[ar.Name()], labelAndAnnotation{
+						Labels:      a.Labels.Copy(),
+						Annotations: a.Annotations.Copy(),
+					})
+				}
+			}
+		}
+	}
+	return got
+}
+
+// orderedGroups returns groups sorted using the order from group_eval_order.
+// Groups not listed there are evaluated first, sorted by name.
+func orderedGroups(groupsMap map[string]*rules.Group, groupOrder map[string]int) []*rules.Group {
+	groups := make([]*rules.Group, 0, len(groupsMap))
+	for _, g := range groupsMap {
+		groups = append(groups, g)
+	}
+	position := func(g *rules.Group) int {
+		if pos, ok := groupOrder[g.Name()]; ok {
+			return pos
+		}
+		return -1
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		pi, pj := position(groups[i]), position(groups[j])
+		if pi != pj {
+			return pi < pj
+		}
+		return groups[i].Name() < groups[j].Name()
+	})
+	return groups
+}
+
+func sortSamples(samples []parsedSample) {
+	sort.Slice(samples, func(i, j int) bool {
+		return labels.Compare(samples[i].Labels, samples[j].Labels) < 0
+	})
+}
+
+func query(ctx context.Context, qs string, t time.Time, engine *promql.Engine, qu storage.Queryable) (promql.Vector, error) {
+	q, err := engine.NewInstantQuery(qu, nil, qs, t)
+	if err != nil {
+		return nil, err
+	}
+	res := q.Exec(ctx)
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	switch v := res.Value.(type) {
+	case promql.Vector:
+		return v, nil
+	case promql.Scalar:
+		return promql.Vector{promql.Sample{
+			Point:  promql.Point(v),
+			Metric: labels.Labels{},
+		}}, nil
+	default:
+		return nil, errors.New("rule result is not a vector or scalar")
+	}
+}
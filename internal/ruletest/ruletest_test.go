@@ -0,0 +1,198 @@
+package ruletest_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudflare/pint/internal/ruletest"
+)
+
+func TestRunFile(t *testing.T) {
+	type testCaseT struct {
+		rules    string
+		tests    string
+		failures []ruletest.Failure
+		err      string
+	}
+
+	rules := `
+groups:
+- name: foo
+  rules:
+  - record: job:up:sum
+    expr: sum(up) by (job)
+  - alert: Down
+    expr: up == 0
+    for: 5m
+    labels:
+      severity: page
+    annotations:
+      summary: "{{ $labels.instance }} is down"
+`
+
+	testCases := []testCaseT{
+		{
+			rules: rules,
+			tests: `
+rule_files:
+- rules.yml
+tests:
+- interval: 1m
+  input_series:
+  - series: up{job="foo", instance="a"}
+    values: 1x10
+  - series: up{job="foo", instance="b"}
+    values: 0x10
+  promql_expr_test:
+  - expr: job:up:sum
+    eval_time: 5m
+    exp_samples:
+    - labels: job:up:sum{job="foo"}
+      value: 1
+  alert_rule_test:
+  - eval_time: 1m
+    alertname: Down
+  - eval_time: 10m
+    alertname: Down
+    exp_alerts:
+    - exp_labels:
+        severity: page
+        job: foo
+        instance: b
+      exp_annotations:
+        summary: b is down
+`,
+		},
+		{
+			rules: rules,
+			tests: `
+rule_files:
+- rules.yml
+tests:
+- interval: 1m
+  name: broken
+  input_series:
+  - series: up{job="foo", instance="a"}
+    values: 1x10
+  promql_expr_test:
+  - expr: job:up:sum
+    eval_time: 5m
+    exp_samples:
+    - labels: job:up:sum{job="foo"}
+      value: 2
+  alert_rule_test:
+  - eval_time: 10m
+    alertname: Down
+    exp_alerts:
+    - exp_labels:
+        instance: a
+`,
+			failures: []ruletest.Failure{
+				{
+					Line: 17,
+					Text: "test \"broken\": alert Down at 10m doesn't match expected alerts\nexpected: [\n  0: labels: {alertname=\"Down\", instance=\"a\"} annotations: {}\n]\ngot: []",
+				},
+				{
+					Line: 11,
+					Text: "test \"broken\": query \"job:up:sum\" at 5m doesn't match expected samples\nexpected: {__name__=\"job:up:sum\", job=\"foo\"} 2E+00\ngot: {__name__=\"job:up:sum\", job=\"foo\"} 1E+00",
+				},
+			},
+		},
+		{
+			rules: `
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: example
+spec:
+  groups:
+  - name: foo
+    rules:
+    - record: job:up:sum
+      expr: sum(up) by (job)
+`,
+			tests: `
+rule_files:
+- rules.yml
+tests:
+- interval: 1m
+  input_series:
+  - series: up{job="foo"}
+    values: 1x10
+  promql_expr_test:
+  - expr: job:up:sum
+    eval_time: 5m
+    exp_samples:
+    - labels: job:up:sum{job="foo"}
+      value: 1
+`,
+		},
+		{
+			rules: rules,
+			tests: `
+rule_files:
+- rules.yml
+tests:
+- interval: 1m
+  input_series:
+  - series: up{job="foo"}
+    values: 1x10
+  promql_expr_test:
+  - expr: sum(
+    eval_time: 5m
+`,
+			failures: []ruletest.Failure{
+				{
+					Line: 10,
+					Text: "query \"sum(\" at 5m failed: 1:5: parse error: unclosed left parenthesis",
+				},
+			},
+		},
+		{
+			rules: "groups:\n- name: foo\n  rules:\n  - record: foo\n    expr: sum(\n",
+			tests: `
+rule_files:
+- rules.yml
+tests:
+- interval: 1m
+`,
+			failures: []ruletest.Failure{
+				{
+					Line: 5,
+					Text: "failed to load rules: 5:11: group \"foo\", rule 1, \"foo\": could not parse expression: 1:5: parse error: unclosed left parenthesis",
+				},
+			},
+		},
+		{
+			rules: rules,
+			tests: "rule_files:\n- missing.yml\n",
+			err:   "no rule files matching",
+		},
+		{
+			rules: rules,
+			tests: "rule_files:\n- rules.yml\nfoo: bar\n",
+			err:   "yaml: unmarshal errors:\n  line 3: field foo not found in type ruletest.testFile",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "rules.yml"), []byte(tc.rules), 0o644))
+			path := filepath.Join(dir, "tests.yml")
+			require.NoError(t, os.WriteFile(path, []byte(tc.tests), 0o644))
+
+			failures, err := ruletest.RunFile(path)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.failures, failures)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package ruletest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"gopkg.in/yaml.v3"
+)
+
+// testFile holds the contents of a single promtool compatible test file.
+type testFile struct {
+	RuleFiles          []string       `yaml:"rule_files"`
+	EvaluationInterval model.Duration `yaml:"evaluation_interval,omitempty"`
+	GroupEvalOrder     []string       `yaml:"group_eval_order"`
+	Tests              []testGroup    `yaml:"tests"`
+}
+
+// testGroup is a group of input series and tests associated with it.
+type testGroup struct {
+	Interval        model.Duration   `yaml:"interval"`
+	InputSeries     []series         `yaml:"input_series"`
+	AlertRuleTests  []alertTestCase  `yaml:"alert_rule_test,omitempty"`
+	PromqlExprTests []promqlTestCase `yaml:"promql_expr_test,omitempty"`
+	ExternalLabels  labels.Labels    `yaml:"external_labels,omitempty"`
+	ExternalURL     string           `yaml:"external_url,omitempty"`
+	TestGroupName   string           `yaml:"name,omitempty"`
+	line            int
+}
+
+func (tg *testGroup) UnmarshalYAML(value *yaml.Node) error {
+	type plain testGroup
+	if err := value.Decode((*plain)(tg)); err != nil {
+		return err
+	}
+	tg.line = value.Line
+	return nil
+}
+
+// seriesLoadingString returns the input series in PromQL notation.
+func (tg *testGroup) seriesLoadingString() string {
+	result := fmt.Sprintf("load %v\n", shortDuration(tg.Interval))
+	for _, is := range tg.InputSeries {
+		result += fmt.Sprintf("  %v %v\n", is.Series, is.Values)
+	}
+	return result
+}
+
+// maxEvalTime returns the max eval time among all alert and promql unit tests.
+func (tg *testGroup) maxEvalTime() (maxd model.Duration) {
+	for _, alert := range tg.AlertRuleTests {
+		if alert.EvalTime > maxd {
+			maxd = alert.EvalTime
+		}
+	}
+	for _, pet := range tg.PromqlExprTests {
+		if pet.EvalTime > maxd {
+			maxd = pet.EvalTime
+		}
+	}
+	return maxd
+}
+
+func (tg *testGroup) testName() string {
+	if tg.TestGroupName != "" {
+		return fmt.Sprintf("test %q: ", tg.TestGroupName)
+	}
+	return ""
+}
+
+type series struct {
+	Series string `yaml:"series"`
+	Values string `yaml:"values"`
+}
+
+type alertTestCase struct {
+	EvalTime  model.Duration `yaml:"eval_time"`
+	Alertname string         `yaml:"alertname"`
+	ExpAlerts []alert        `yaml:"exp_alerts"`
+	line      int
+}
+
+func (atc *alertTestCase) UnmarshalYAML(value *yaml.Node) error {
+	type plain alertTestCase
+	if err := value.Decode((*plain)(atc)); err != nil {
+		return err
+	}
+	atc.line = value.Line
+	return nil
+}
+
+type alert struct {
+	ExpLabels      map[string]string `yaml:"exp_labels"`
+	ExpAnnotations map[string]string `yaml:"exp_annotations"`
+}
+
+type promqlTestCase struct {
+	Expr       string         `yaml:"expr"`
+	EvalTime   model.Duration `yaml:"eval_time"`
+	ExpSamples []sample       `yaml:"exp_samples"`
+	line       int
+}
+
+func (ptc *promqlTestCase) UnmarshalYAML(value *yaml.Node) error {
+	type plain promqlTestCase
+	if err := value.Decode((*plain)(ptc)); err != nil {
+		return err
+	}
+	ptc.line = value.Line
+	return nil
+}
+
+type sample struct {
+	Labels string  `yaml:"labels"`
+	Value  float64 `yaml:"value"`
+}
+
+// parsedSample is a sample with parsed Labels.
+type parsedSample struct {
+	Labels labels.Labels
+	Value  float64
+}
+
+func (ps parsedSample) String() string {
+	return ps.Labels.String() + " " + strconv.FormatFloat(ps.Value, 'E', -1, 64)
+}
+
+func parsedSamplesString(pss []parsedSample) string {
+	if len(pss) == 0 {
+		return "nil"
+	}
+	s := make([]string, 0, len(pss))
+	for _, ps := range pss {
+		s = append(s, ps.String())
+	}
+	return strings.Join(s, ", ")
+}
+
+type labelAndAnnotation struct {
+	Labels      labels.Labels
+	Annotations labels.Labels
+}
+
+func (la labelAndAnnotation) String() string {
+	return "labels: " + la.Labels.String() + " annotations: " + la.Annotations.String()
+}
+
+type labelsAndAnnotations []labelAndAnnotation
+
+func (la labelsAndAnnotations) Len() int      { return len(la) }
+func (la labelsAndAnnotations) Swap(i, j int) { la[i], la[j] = la[j], la[i] }
+func (la labelsAndAnnotations) Less(i, j int) bool {
+	diff := labels.Compare(la[i].Labels, la[j].Labels)
+	if diff != 0 {
+		return diff < 0
+	}
+	return labels.Compare(la[i].Annotations, la[j].Annotations) < 0
+}
+
+func (la labelsAndAnnotations) String() string {
+	if len(la) == 0 {
+		return "[]"
+	}
+	s := make([]string, 0, len(la))
+	for i, l := range la {
+		s = append(s, fmt.Sprintf("  %d: %s", i, l))
+	}
+	return "[\n" + strings.Join(s, "\n") + "\n]"
+}
+
+func shortDuration(d model.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}